@@ -21,7 +21,8 @@ const (
 		models.AccountPermissionReadVirus | models.AccountPermissionWriteVirus |
 		models.AccountPermissionReadDiagnoses | models.AccountPermissionWriteDiagnoses |
 		models.AccountPermissionReadJoints | models.AccountPermissionWriteJoints |
-		models.AccountPermissionReadProphylaxes | models.AccountPermissionWriteProphylaxes
+		models.AccountPermissionReadProphylaxes | models.AccountPermissionWriteProphylaxes |
+		models.AccountPermissionReadAuditEvents
 
 	// aka jointologist
 	snoopDoggPermissions = models.AccountPermissionReadPatient |
@@ -0,0 +1,338 @@
+package actions
+
+import (
+	"shs/app/models"
+	"slices"
+	"time"
+)
+
+var visitReasons = []models.VisitReason{
+	models.VisitReasonPrimaryProphylaxis,
+	models.VisitReasonSecondaryProphylaxis,
+	models.VisitReasonSurgery,
+	models.VisitReasonJointEvaluation,
+	models.VisitReasonJointInjection,
+	models.VisitReasonHemelibra,
+	models.VisitReasonTreatmentAtHome,
+	models.VisitReasonActiveBleeding,
+}
+
+type Appointment struct {
+	Id          uint      `json:"id"`
+	Reason      string    `json:"reason"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	AccountId   uint      `json:"account_id"`
+	Status      string    `json:"status"`
+	Notes       string    `json:"notes"`
+	VisitId     uint      `json:"visit_id"`
+}
+
+func (a *Appointment) FromModel(appointment models.Appointment) {
+	(*a) = Appointment{
+		Id:          appointment.Id,
+		Reason:      string(appointment.Reason),
+		ScheduledAt: appointment.ScheduledAt.In(time.Local),
+		AccountId:   appointment.AccountId,
+		Status:      string(appointment.Status),
+		Notes:       appointment.Notes,
+		VisitId:     appointment.VisitId,
+	}
+}
+
+func (a Appointment) IntoModel() models.Appointment {
+	return models.Appointment{
+		Reason:      models.VisitReason(a.Reason),
+		ScheduledAt: a.ScheduledAt.UTC(),
+		AccountId:   a.AccountId,
+		Status:      models.AppointmentStatus(a.Status),
+		Notes:       a.Notes,
+		VisitId:     a.VisitId,
+	}
+}
+
+func (a Appointment) Validate() error {
+	if !slices.Contains(visitReasons, models.VisitReason(a.Reason)) {
+		return ErrValidation{Field: "reason"}
+	}
+	if a.ScheduledAt.IsZero() {
+		return ErrValidation{Field: "scheduled_at"}
+	}
+
+	return nil
+}
+
+type AppointmentWithPatient struct {
+	Appointment Appointment `json:"appointment"`
+	Patient     Patient     `json:"patient"`
+	// AssigneeDisplayName is the display name of the appointment's assigned
+	// account, and it's empty when the appointment isn't assigned.
+	AssigneeDisplayName string `json:"assignee_display_name"`
+}
+
+type CreateAppointmentParams struct {
+	ActionContext
+	PatientId   string
+	Appointment Appointment
+}
+
+type CreateAppointmentPayload struct {
+	Data Appointment `json:"data"`
+}
+
+func (a *Actions) CreateAppointment(params CreateAppointmentParams) (CreateAppointmentPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionWriteOtherVisits) {
+		return CreateAppointmentPayload{}, ErrPermissionDenied{}
+	}
+
+	err := params.Appointment.Validate()
+	if err != nil {
+		return CreateAppointmentPayload{}, err
+	}
+
+	patient, err := a.app.GetPatientByPublicId(params.PatientId)
+	if err != nil {
+		return CreateAppointmentPayload{}, err
+	}
+
+	if params.Appointment.AccountId != 0 {
+		assignee, err := a.app.GetAccountById(params.Appointment.AccountId)
+		if err != nil {
+			return CreateAppointmentPayload{}, err
+		}
+		if assignee.Type == models.AccountTypePatient {
+			return CreateAppointmentPayload{}, ErrValidation{Field: "account_id"}
+		}
+	}
+
+	appointment := params.Appointment.IntoModel()
+	appointment.PatientId = patient.Id
+	appointment.Status = models.AppointmentStatusBooked
+	appointment.VisitId = 0
+
+	appointment, err = a.app.CreateAppointment(appointment)
+	if err != nil {
+		return CreateAppointmentPayload{}, err
+	}
+
+	outAppointment := new(Appointment)
+	outAppointment.FromModel(appointment)
+
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "CreateAppointment",
+		Kind:            models.AuditEventKindWrite,
+		PatientPublicId: params.PatientId,
+		ResourceIds:     map[string]uint{"appointment_id": appointment.Id},
+	})
+
+	return CreateAppointmentPayload{
+		Data: *outAppointment,
+	}, nil
+}
+
+// truncateToClinicDay is the start of the time's day in the clinic's
+// location, which is the server's local time, as the database connection's is,
+// so that the appointments near midnight are on the clinic's day.
+func truncateToClinicDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+type ListAppointmentsAgendaParams struct {
+	ActionContext
+	// Date is the agenda's day, and it defaults to today.
+	Date time.Time
+}
+
+type ListAppointmentsAgendaPayload struct {
+	Date time.Time                `json:"date"`
+	Data []AppointmentWithPatient `json:"data"`
+}
+
+// ListAppointmentsAgenda lists the appointments that are scheduled on the
+// given day, ordered by their scheduled time.
+func (a *Actions) ListAppointmentsAgenda(params ListAppointmentsAgendaParams) (ListAppointmentsAgendaPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadOtherVisits) {
+		return ListAppointmentsAgendaPayload{}, ErrPermissionDenied{}
+	}
+
+	date := params.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	date = truncateToClinicDay(date)
+
+	appointments, err := a.app.ListAppointmentsOnTimeRange(date.UTC(), date.AddDate(0, 0, 1).UTC())
+	if err != nil {
+		return ListAppointmentsAgendaPayload{}, err
+	}
+
+	accounts, err := a.app.ListAllAccounts()
+	if err != nil {
+		return ListAppointmentsAgendaPayload{}, err
+	}
+
+	accountsNames := make(map[uint]string)
+	for _, account := range accounts {
+		accountsNames[account.Id] = account.DisplayName
+	}
+
+	patients := make(map[uint]Patient)
+	outAppointments := make([]AppointmentWithPatient, 0, len(appointments))
+	for _, appointment := range appointments {
+		patient, ok := patients[appointment.PatientId]
+		if !ok {
+			dbPatient, err := a.app.GetPatientById(appointment.PatientId)
+			if err != nil {
+				return ListAppointmentsAgendaPayload{}, err
+			}
+			patient.FromModel(dbPatient)
+			patients[appointment.PatientId] = patient
+		}
+
+		outAppointment := new(Appointment)
+		outAppointment.FromModel(appointment)
+		outAppointments = append(outAppointments, AppointmentWithPatient{
+			Appointment:         *outAppointment,
+			Patient:             patient,
+			AssigneeDisplayName: accountsNames[appointment.AccountId],
+		})
+	}
+
+	a.audit(params.ActionContext, auditEntry{
+		Action: "ListAppointmentsAgenda",
+		Kind:   models.AuditEventKindRead,
+	})
+
+	return ListAppointmentsAgendaPayload{
+		Date: date,
+		Data: outAppointments,
+	}, nil
+}
+
+type ListPatientAppointmentsParams struct {
+	ActionContext
+	PatientId string
+}
+
+type ListPatientAppointmentsPayload struct {
+	Data []Appointment `json:"data"`
+}
+
+func (a *Actions) ListPatientAppointments(params ListPatientAppointmentsParams) (ListPatientAppointmentsPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadOtherVisits) {
+		return ListPatientAppointmentsPayload{}, ErrPermissionDenied{}
+	}
+
+	patient, err := a.app.GetPatientByPublicId(params.PatientId)
+	if err != nil {
+		return ListPatientAppointmentsPayload{}, err
+	}
+
+	appointments, err := a.app.ListPatientAppointments(patient.Id)
+	if err != nil {
+		return ListPatientAppointmentsPayload{}, err
+	}
+
+	outAppointments := make([]Appointment, 0, len(appointments))
+	for _, appointment := range appointments {
+		outAppointment := new(Appointment)
+		outAppointment.FromModel(appointment)
+		outAppointments = append(outAppointments, *outAppointment)
+	}
+
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "ListPatientAppointments",
+		Kind:            models.AuditEventKindRead,
+		PatientPublicId: params.PatientId,
+	})
+
+	return ListPatientAppointmentsPayload{
+		Data: outAppointments,
+	}, nil
+}
+
+type ListAppointmentAssigneesParams struct {
+	ActionContext
+}
+
+type ListAppointmentAssigneesPayload struct {
+	Data []Account `json:"data"`
+}
+
+// ListAppointmentAssignees lists the staff accounts that an appointment can be
+// assigned to, without needing the permission to read the accounts.
+func (a *Actions) ListAppointmentAssignees(params ListAppointmentAssigneesParams) (ListAppointmentAssigneesPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionWriteOtherVisits) {
+		return ListAppointmentAssigneesPayload{}, ErrPermissionDenied{}
+	}
+
+	accounts, err := a.app.ListAllAccounts()
+	if err != nil {
+		return ListAppointmentAssigneesPayload{}, err
+	}
+
+	outAccounts := make([]Account, 0, len(accounts))
+	for _, account := range accounts {
+		if account.Type == models.AccountTypePatient {
+			continue
+		}
+		outAccounts = append(outAccounts, Account{
+			Id:          account.Id,
+			DisplayName: account.DisplayName,
+			Type:        string(account.Type),
+		})
+	}
+
+	return ListAppointmentAssigneesPayload{
+		Data: outAccounts,
+	}, nil
+}
+
+type UpdateAppointmentStatusParams struct {
+	ActionContext
+	AppointmentId uint
+	Status        string `json:"status"`
+}
+
+type UpdateAppointmentStatusPayload struct {
+}
+
+// UpdateAppointmentStatus marks a booked appointment as a no-show or as
+// cancelled, or books it again, where an appointment is only marked as
+// attended when the patient checks in through a check-up.
+func (a *Actions) UpdateAppointmentStatus(params UpdateAppointmentStatusParams) (UpdateAppointmentStatusPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionWriteOtherVisits) {
+		return UpdateAppointmentStatusPayload{}, ErrPermissionDenied{}
+	}
+
+	status := models.AppointmentStatus(params.Status)
+	switch status {
+	case models.AppointmentStatusBooked, models.AppointmentStatusNoShow, models.AppointmentStatusCancelled:
+	default:
+		return UpdateAppointmentStatusPayload{}, ErrValidation{Field: "status"}
+	}
+
+	appointment, err := a.app.GetAppointment(params.AppointmentId)
+	if err != nil {
+		return UpdateAppointmentStatusPayload{}, err
+	}
+	if appointment.Status == models.AppointmentStatusAttended {
+		return UpdateAppointmentStatusPayload{}, ErrValidation{Field: "status"}
+	}
+
+	err = a.app.UpdateAppointmentStatus(appointment.Id, status, 0)
+	if err != nil {
+		return UpdateAppointmentStatusPayload{}, err
+	}
+
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "UpdateAppointmentStatus",
+		Kind:            models.AuditEventKindWrite,
+		PatientPublicId: a.patientPublicId(appointment.PatientId),
+		ResourceIds:     map[string]uint{"appointment_id": appointment.Id},
+		Before:          map[string]any{"status": appointment.Status},
+		After:           map[string]any{"status": status},
+	})
+
+	return UpdateAppointmentStatusPayload{}, nil
+}
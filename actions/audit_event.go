@@ -0,0 +1,265 @@
+package actions
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"shs/app/models"
+	"shs/log"
+	"strconv"
+	"time"
+)
+
+type AuditEvent struct {
+	Id              uint              `json:"id"`
+	AccountId       uint              `json:"account_id"`
+	AccountUsername string            `json:"account_username"`
+	Action          string            `json:"action"`
+	Kind            string            `json:"kind"`
+	PatientPublicId string            `json:"patient_public_id"`
+	ResourceIds     map[string]uint   `json:"resource_ids"`
+	Diff            map[string]Change `json:"diff"`
+	ClientIp        string            `json:"client_ip"`
+	UserAgent       string            `json:"user_agent"`
+	CreatedAt       time.Time         `json:"created_at"`
+}
+
+// Change is an updated field's value before and after the update.
+type Change struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+func (e *AuditEvent) FromModel(event models.AuditEvent) {
+	(*e) = AuditEvent{
+		Id:              event.Id,
+		AccountId:       event.AccountId,
+		AccountUsername: event.AccountUsername,
+		Action:          event.Action,
+		Kind:            string(event.Kind),
+		PatientPublicId: event.PatientPublicId,
+		ClientIp:        event.ClientIp,
+		UserAgent:       event.UserAgent,
+		CreatedAt:       event.CreatedAt,
+	}
+	if event.ResourceIds != "" {
+		_ = json.Unmarshal([]byte(event.ResourceIds), &e.ResourceIds)
+	}
+	if event.Diff != "" {
+		_ = json.Unmarshal([]byte(event.Diff), &e.Diff)
+	}
+}
+
+// auditEntry is an action's access of a patient's data.
+type auditEntry struct {
+	Action          string
+	Kind            models.AuditEventKind
+	PatientPublicId string
+	ResourceIds     map[string]uint
+	// Before and After are an updated resource's states, where only their
+	// changed fields are recorded.
+	Before any
+	After  any
+}
+
+// audit appends the entry to the audit trail, where a failure to record it is
+// logged, and doesn't fail the action, since the data was already read or
+// written, and an entry of a job that isn't run by an account, e.g. the lab
+// results importer, has an empty context.
+func (a *Actions) audit(ctx ActionContext, entry auditEntry) {
+	event := models.AuditEvent{
+		AccountId:       ctx.Account.Id,
+		AccountUsername: ctx.Account.Username,
+		Action:          entry.Action,
+		Kind:            entry.Kind,
+		PatientPublicId: entry.PatientPublicId,
+		ClientIp:        ctx.ClientIp,
+		UserAgent:       ctx.UserAgent,
+	}
+
+	if len(entry.ResourceIds) > 0 {
+		resourceIds, err := json.Marshal(entry.ResourceIds)
+		if err != nil {
+			log.Errorf("[AUDIT]: Failed to encode %s's resource ids, error: %v\n", entry.Action, err)
+		}
+		event.ResourceIds = string(resourceIds)
+	}
+
+	if entry.Before != nil || entry.After != nil {
+		changes, err := diff(entry.Before, entry.After)
+		if err != nil {
+			log.Errorf("[AUDIT]: Failed to diff %s's changes, error: %v\n", entry.Action, err)
+		}
+		if len(changes) > 0 {
+			changesJson, _ := json.Marshal(changes)
+			event.Diff = string(changesJson)
+		}
+	}
+
+	_, err := a.app.CreateAuditEvent(event)
+	if err != nil {
+		log.Errorf("[AUDIT]: Failed to record %s by %s, error: %v\n", entry.Action, ctx.Account.Username, err)
+	}
+}
+
+// patientPublicId is used to audit the actions that only have the patient's id,
+// where a patient that can't be found is audited without a public id.
+func (a *Actions) patientPublicId(patientId uint) string {
+	patient, err := a.app.GetPatientById(patientId)
+	if err != nil {
+		return ""
+	}
+
+	return patient.PublicId
+}
+
+// diff compares the json fields of before and after, which are expected to be
+// of the same struct type, and returns the changed ones.
+func diff(before, after any) (map[string]Change, error) {
+	beforeFields, err := jsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := jsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]Change)
+	for name, beforeValue := range beforeFields {
+		afterValue := afterFields[name]
+		if !reflect.DeepEqual(beforeValue, afterValue) {
+			changes[name] = Change{Before: beforeValue, After: afterValue}
+		}
+	}
+	for name, afterValue := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			changes[name] = Change{Before: nil, After: afterValue}
+		}
+	}
+
+	return changes, nil
+}
+
+func jsonFields(v any) (map[string]any, error) {
+	fields := make(map[string]any)
+	if v == nil {
+		return fields, nil
+	}
+
+	fieldsJson, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(fieldsJson, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+type ListAuditEventsParams struct {
+	ActionContext
+	AccountId       uint
+	PatientPublicId string
+	From            time.Time
+	To              time.Time
+}
+
+type ListAuditEventsPayload struct {
+	Data []AuditEvent `json:"data"`
+}
+
+// maxListedAuditEvents is the number of the latest events that are listed,
+// where older events are found by narrowing the filter, or in the export.
+const maxListedAuditEvents = 500
+
+func (a *Actions) ListAuditEvents(params ListAuditEventsParams) (ListAuditEventsPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadAuditEvents) {
+		return ListAuditEventsPayload{}, ErrPermissionDenied{}
+	}
+
+	events, err := a.app.ListAuditEvents(models.AuditEventsFilter{
+		AccountId:       params.AccountId,
+		PatientPublicId: params.PatientPublicId,
+		From:            params.From,
+		To:              params.To,
+		Limit:           maxListedAuditEvents,
+	})
+	if err != nil {
+		return ListAuditEventsPayload{}, err
+	}
+
+	outEvents := make([]AuditEvent, 0, len(events))
+	for _, event := range events {
+		outEvent := new(AuditEvent)
+		outEvent.FromModel(event)
+		outEvents = append(outEvents, *outEvent)
+	}
+
+	return ListAuditEventsPayload{
+		Data: outEvents,
+	}, nil
+}
+
+type ExportAuditEventsParams struct {
+	ActionContext
+	AccountId       uint
+	PatientPublicId string
+	From            time.Time
+	To              time.Time
+}
+
+type ExportAuditEventsPayload struct {
+	Csv string `json:"csv"`
+}
+
+// ExportAuditEvents exports all of the filtered events as CSV for compliance
+// reviews, where the resource ids and the diff are kept as JSON.
+func (a *Actions) ExportAuditEvents(params ExportAuditEventsParams) (ExportAuditEventsPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadAuditEvents) {
+		return ExportAuditEventsPayload{}, ErrPermissionDenied{}
+	}
+
+	events, err := a.app.ListAuditEvents(models.AuditEventsFilter{
+		AccountId:       params.AccountId,
+		PatientPublicId: params.PatientPublicId,
+		From:            params.From,
+		To:              params.To,
+	})
+	if err != nil {
+		return ExportAuditEventsPayload{}, err
+	}
+
+	out := new(bytes.Buffer)
+	w := csv.NewWriter(out)
+	_ = w.Write([]string{
+		"id", "created_at", "account_id", "account_username", "action", "kind",
+		"patient_public_id", "resource_ids", "diff", "client_ip", "user_agent",
+	})
+	for _, event := range events {
+		_ = w.Write([]string{
+			strconv.FormatUint(uint64(event.Id), 10),
+			event.CreatedAt.UTC().Format(time.RFC3339),
+			strconv.FormatUint(uint64(event.AccountId), 10),
+			event.AccountUsername,
+			event.Action,
+			string(event.Kind),
+			event.PatientPublicId,
+			event.ResourceIds,
+			event.Diff,
+			event.ClientIp,
+			event.UserAgent,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return ExportAuditEventsPayload{}, err
+	}
+
+	return ExportAuditEventsPayload{
+		Csv: out.String(),
+	}, nil
+}
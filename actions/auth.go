@@ -61,6 +61,9 @@ type TokenPayload struct {
 	Name      string    `json:"name"`
 	Username  string    `json:"username"`
 	CreatedAt time.Time `json:"created_at"`
+	// CalendarFeedVersion is the account's calendar feed version that a
+	// calendar feed token was issued with.
+	CalendarFeedVersion uint `json:"calendar_feed_version,omitempty"`
 }
 
 func (t TokenPayload) Valid() bool {
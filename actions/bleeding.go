@@ -0,0 +1,443 @@
+package actions
+
+import (
+	"shs/app/models"
+	"slices"
+	"time"
+)
+
+type BleedingEpisode struct {
+	Id                   uint      `json:"id"`
+	Site                 string    `json:"site"`
+	Cause                string    `json:"cause"`
+	Severity             string    `json:"severity"`
+	OnsetAt              time.Time `json:"onset_at"`
+	Notes                string    `json:"notes"`
+	VisitId              uint      `json:"visit_id,omitempty"`
+	PrescribedMedicineId uint      `json:"prescribed_medicine_id,omitempty"`
+	ReportedByPatient    bool      `json:"reported_by_patient"`
+	CreatedAt            time.Time `json:"created_at"`
+}
+
+func (b *BleedingEpisode) FromModel(be models.BleedingEpisode) {
+	(*b) = BleedingEpisode{
+		Id:                   be.Id,
+		Site:                 string(be.Site),
+		Cause:                string(be.Cause),
+		Severity:             string(be.Severity),
+		OnsetAt:              be.OnsetAt,
+		Notes:                be.Notes,
+		VisitId:              be.VisitId,
+		PrescribedMedicineId: be.PrescribedMedicineId,
+		ReportedByPatient:    be.ReportedByPatient,
+		CreatedAt:            be.CreatedAt,
+	}
+}
+
+func (b BleedingEpisode) IntoModel() models.BleedingEpisode {
+	return models.BleedingEpisode{
+		Site:                 models.BleedingSite(b.Site),
+		Cause:                models.BleedingCause(b.Cause),
+		Severity:             models.BleedingSeverity(b.Severity),
+		OnsetAt:              b.OnsetAt,
+		Notes:                b.Notes,
+		VisitId:              b.VisitId,
+		PrescribedMedicineId: b.PrescribedMedicineId,
+		ReportedByPatient:    b.ReportedByPatient,
+	}
+}
+
+func (b BleedingEpisode) IsJoint() bool {
+	return models.BleedingSite(b.Site).IsJoint()
+}
+
+func (b BleedingEpisode) Validate() error {
+	switch models.BleedingSite(b.Site) {
+	case models.BleedingSiteRightAnkle, models.BleedingSiteLeftAnkle,
+		models.BleedingSiteRightKnee, models.BleedingSiteLeftKnee,
+		models.BleedingSiteRightElbow, models.BleedingSiteLeftElbow,
+		models.BleedingSiteOtherJoint, models.BleedingSiteMuscle,
+		models.BleedingSiteMucosal, models.BleedingSiteIntracranial,
+		models.BleedingSiteOther:
+	default:
+		return ErrValidation{Field: "site"}
+	}
+
+	switch models.BleedingCause(b.Cause) {
+	case models.BleedingCauseSpontaneous, models.BleedingCauseTraumatic:
+	default:
+		return ErrValidation{Field: "cause"}
+	}
+
+	switch models.BleedingSeverity(b.Severity) {
+	case models.BleedingSeverityMild, models.BleedingSeverityModerate, models.BleedingSeveritySevere:
+	default:
+		return ErrValidation{Field: "severity"}
+	}
+
+	if b.OnsetAt.IsZero() || b.OnsetAt.After(time.Now().UTC()) {
+		return ErrValidation{Field: "onset_at"}
+	}
+
+	return nil
+}
+
+// BleedingRates holds the annualized bleeding rates over a single window,
+// where ABR is all bleeds, AJBR is joint bleeds and SpontaneousABR is spontaneous bleeds,
+// each one is the count of bleeds divided by the observed years.
+type BleedingRates struct {
+	WindowStart       time.Time `json:"window_start"`
+	WindowEnd         time.Time `json:"window_end"`
+	ObservedDays      int       `json:"observed_days"`
+	Bleeds            int       `json:"bleeds"`
+	JointBleeds       int       `json:"joint_bleeds"`
+	SpontaneousBleeds int       `json:"spontaneous_bleeds"`
+	ABR               float64   `json:"abr"`
+	AJBR              float64   `json:"ajbr"`
+	SpontaneousABR    float64   `json:"spontaneous_abr"`
+}
+
+type ProphylaxisBleedingRates struct {
+	Prophylaxis Prophylaxis   `json:"prophylaxis"`
+	Rates       BleedingRates `json:"rates"`
+}
+
+// computeBleedingRates counts the episodes with onset in [from, to), the window is clipped to the
+// observation start, i.e. the earliest of the patient's registration and first recorded episode,
+// so a patient followed for less than the window isn't reported with a falsely low rate.
+func computeBleedingRates(episodes []models.BleedingEpisode, observationStart, from, to time.Time) BleedingRates {
+	rates := BleedingRates{
+		WindowStart: from,
+		WindowEnd:   to,
+	}
+
+	if observationStart.After(from) {
+		from = observationStart
+	}
+	if !from.Before(to) {
+		return rates
+	}
+
+	for _, be := range episodes {
+		if be.OnsetAt.Before(from) || !be.OnsetAt.Before(to) {
+			continue
+		}
+		rates.Bleeds++
+		if be.Site.IsJoint() {
+			rates.JointBleeds++
+		}
+		if be.Cause == models.BleedingCauseSpontaneous {
+			rates.SpontaneousBleeds++
+		}
+	}
+
+	observedDays := to.Sub(from).Hours() / 24
+	rates.ObservedDays = int(observedDays)
+	observedYears := max(observedDays, 1) / 365.25
+
+	rates.ABR = float64(rates.Bleeds) / observedYears
+	rates.AJBR = float64(rates.JointBleeds) / observedYears
+	rates.SpontaneousABR = float64(rates.SpontaneousBleeds) / observedYears
+
+	return rates
+}
+
+func bleedingObservationStart(patient models.Patient, episodes []models.BleedingEpisode) time.Time {
+	start := patient.CreatedAt
+	for _, be := range episodes {
+		if be.OnsetAt.Before(start) {
+			start = be.OnsetAt
+		}
+	}
+
+	return start
+}
+
+// validateBleedingEpisodeLinks makes sure that the episode's visit and prescribed medicine belong to the patient.
+func (a *Actions) validateBleedingEpisodeLinks(patientId uint, be BleedingEpisode) error {
+	if be.VisitId == 0 {
+		if be.PrescribedMedicineId != 0 {
+			return ErrValidation{Field: "prescribed_medicine_id"}
+		}
+		return nil
+	}
+
+	visit, err := a.app.GetPatientVisit(be.VisitId)
+	if err != nil {
+		return err
+	}
+	if visit.PatientId != patientId {
+		return ErrValidation{Field: "visit_id"}
+	}
+
+	if be.PrescribedMedicineId == 0 {
+		return nil
+	}
+
+	prescribedMeds, err := a.app.ListPatientVisitPrescribedMedicine(visit.Id)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(prescribedMeds, func(pm models.PrescribedMedicine) bool {
+		return pm.Id == be.PrescribedMedicineId
+	}) {
+		return ErrValidation{Field: "prescribed_medicine_id"}
+	}
+
+	return nil
+}
+
+type CreatePatientBleedingEpisodeParams struct {
+	ActionContext
+	PatientId       string
+	BleedingEpisode BleedingEpisode `json:"bleeding_episode"`
+}
+
+type CreatePatientBleedingEpisodePayload struct {
+}
+
+func (a *Actions) CreatePatientBleedingEpisode(params CreatePatientBleedingEpisodeParams) (CreatePatientBleedingEpisodePayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionWritePatient) {
+		return CreatePatientBleedingEpisodePayload{}, ErrPermissionDenied{}
+	}
+
+	err := params.BleedingEpisode.Validate()
+	if err != nil {
+		return CreatePatientBleedingEpisodePayload{}, err
+	}
+
+	patient, err := a.app.GetPatientByPublicId(params.PatientId)
+	if err != nil {
+		return CreatePatientBleedingEpisodePayload{}, err
+	}
+
+	err = a.validateBleedingEpisodeLinks(patient.Id, params.BleedingEpisode)
+	if err != nil {
+		return CreatePatientBleedingEpisodePayload{}, err
+	}
+
+	be := params.BleedingEpisode.IntoModel()
+	be.PatientId = patient.Id
+	be.ReportedByPatient = false
+
+	be, err = a.app.CreateBleedingEpisode(be)
+	if err != nil {
+		return CreatePatientBleedingEpisodePayload{}, err
+	}
+
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "CreatePatientBleedingEpisode",
+		Kind:            models.AuditEventKindWrite,
+		PatientPublicId: params.PatientId,
+		ResourceIds:     map[string]uint{"bleeding_episode_id": be.Id},
+	})
+
+	return CreatePatientBleedingEpisodePayload{}, nil
+}
+
+type ReportOwnBleedingEpisodeParams struct {
+	ActionContext
+	BleedingEpisode BleedingEpisode `json:"bleeding_episode"`
+}
+
+type ReportOwnBleedingEpisodePayload struct {
+}
+
+// ReportOwnBleedingEpisode is used by patients from the patient portal,
+// where the patient is the one behind the logged in account.
+func (a *Actions) ReportOwnBleedingEpisode(params ReportOwnBleedingEpisodeParams) (ReportOwnBleedingEpisodePayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionWriteOwnVisit) {
+		return ReportOwnBleedingEpisodePayload{}, ErrPermissionDenied{}
+	}
+
+	err := params.BleedingEpisode.Validate()
+	if err != nil {
+		return ReportOwnBleedingEpisodePayload{}, err
+	}
+
+	patient, err := a.app.GetPatientByPublicId(params.Account.Username)
+	if err != nil {
+		return ReportOwnBleedingEpisodePayload{}, err
+	}
+
+	err = a.validateBleedingEpisodeLinks(patient.Id, params.BleedingEpisode)
+	if err != nil {
+		return ReportOwnBleedingEpisodePayload{}, err
+	}
+
+	be := params.BleedingEpisode.IntoModel()
+	be.PatientId = patient.Id
+	be.ReportedByPatient = true
+
+	be, err = a.app.CreateBleedingEpisode(be)
+	if err != nil {
+		return ReportOwnBleedingEpisodePayload{}, err
+	}
+
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "ReportOwnBleedingEpisode",
+		Kind:            models.AuditEventKindWrite,
+		PatientPublicId: params.Account.Username,
+		ResourceIds:     map[string]uint{"bleeding_episode_id": be.Id},
+	})
+
+	return ReportOwnBleedingEpisodePayload{}, nil
+}
+
+type ListPatientBleedingEpisodesParams struct {
+	ActionContext
+	PatientId string
+}
+
+type ListPatientBleedingEpisodesPayload struct {
+	Data []BleedingEpisode `json:"data"`
+}
+
+func (a *Actions) ListPatientBleedingEpisodes(params ListPatientBleedingEpisodesParams) (ListPatientBleedingEpisodesPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadPatient) {
+		return ListPatientBleedingEpisodesPayload{}, ErrPermissionDenied{}
+	}
+
+	patient, err := a.app.GetPatientByPublicId(params.PatientId)
+	if err != nil {
+		return ListPatientBleedingEpisodesPayload{}, err
+	}
+
+	episodes, err := a.app.ListPatientBleedingEpisodes(patient.Id)
+	if err != nil {
+		return ListPatientBleedingEpisodesPayload{}, err
+	}
+
+	outEpisodes := make([]BleedingEpisode, 0, len(episodes))
+	for _, be := range episodes {
+		outEpisode := new(BleedingEpisode)
+		outEpisode.FromModel(be)
+		outEpisodes = append(outEpisodes, *outEpisode)
+	}
+
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "ListPatientBleedingEpisodes",
+		Kind:            models.AuditEventKindRead,
+		PatientPublicId: params.PatientId,
+	})
+
+	return ListPatientBleedingEpisodesPayload{
+		Data: outEpisodes,
+	}, nil
+}
+
+type DeletePatientBleedingEpisodeParams struct {
+	ActionContext
+	PatientId         string
+	BleedingEpisodeId uint
+}
+
+type DeletePatientBleedingEpisodePayload struct {
+}
+
+func (a *Actions) DeletePatientBleedingEpisode(params DeletePatientBleedingEpisodeParams) (DeletePatientBleedingEpisodePayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionWritePatient) {
+		return DeletePatientBleedingEpisodePayload{}, ErrPermissionDenied{}
+	}
+
+	patient, err := a.app.GetPatientByPublicId(params.PatientId)
+	if err != nil {
+		return DeletePatientBleedingEpisodePayload{}, err
+	}
+
+	err = a.app.DeleteBleedingEpisodeForPatient(params.BleedingEpisodeId, patient.Id)
+	if err != nil {
+		return DeletePatientBleedingEpisodePayload{}, err
+	}
+
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "DeletePatientBleedingEpisode",
+		Kind:            models.AuditEventKindWrite,
+		PatientPublicId: params.PatientId,
+		ResourceIds:     map[string]uint{"bleeding_episode_id": params.BleedingEpisodeId},
+	})
+
+	return DeletePatientBleedingEpisodePayload{}, nil
+}
+
+type GetPatientBleedingRatesParams struct {
+	ActionContext
+	PatientId string
+	// At is the end of the current window, defaults to now.
+	At time.Time
+}
+
+type PatientBleedingRates struct {
+	// Current is the rolling 12 months window ending at the requested time.
+	Current BleedingRates `json:"current"`
+	// Monthly holds the rolling 12 months windows ending at each of the last 12 months, oldest first.
+	Monthly []BleedingRates `json:"monthly"`
+	// Prophylaxes holds the rates while each of the patient's prophylaxes was active.
+	Prophylaxes []ProphylaxisBleedingRates `json:"prophylaxes"`
+}
+
+type GetPatientBleedingRatesPayload struct {
+	Data PatientBleedingRates `json:"data"`
+}
+
+func (a *Actions) GetPatientBleedingRates(params GetPatientBleedingRatesParams) (GetPatientBleedingRatesPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadPatient) {
+		return GetPatientBleedingRatesPayload{}, ErrPermissionDenied{}
+	}
+
+	if params.At.IsZero() {
+		params.At = time.Now().UTC()
+	}
+
+	patient, err := a.app.GetPatientByPublicId(params.PatientId)
+	if err != nil {
+		return GetPatientBleedingRatesPayload{}, err
+	}
+
+	episodes, err := a.app.ListPatientBleedingEpisodes(patient.Id)
+	if err != nil {
+		return GetPatientBleedingRatesPayload{}, err
+	}
+
+	prophylaxes, err := a.app.ListProphylaxesForPatient(patient.Id)
+	if err != nil {
+		return GetPatientBleedingRatesPayload{}, err
+	}
+
+	observationStart := bleedingObservationStart(patient, episodes)
+
+	monthly := make([]BleedingRates, 0, 12)
+	for i := 11; i >= 0; i-- {
+		windowEnd := params.At.AddDate(0, -i, 0)
+		monthly = append(monthly, computeBleedingRates(episodes, observationStart, windowEnd.AddDate(-1, 0, 0), windowEnd))
+	}
+
+	outProphylaxes := make([]ProphylaxisBleedingRates, 0, len(prophylaxes))
+	for _, pp := range prophylaxes {
+		windowEnd := params.At
+		if !pp.EndDate.IsZero() && pp.EndDate.Before(windowEnd) {
+			windowEnd = pp.EndDate
+		}
+
+		outProphylaxis := new(Prophylaxis)
+		outProphylaxis.FromModel(pp)
+		outProphylaxes = append(outProphylaxes, ProphylaxisBleedingRates{
+			Prophylaxis: *outProphylaxis,
+			Rates:       computeBleedingRates(episodes, observationStart, pp.StartsAt(), windowEnd),
+		})
+	}
+
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "GetPatientBleedingRates",
+		Kind:            models.AuditEventKindRead,
+		PatientPublicId: params.PatientId,
+	})
+
+	return GetPatientBleedingRatesPayload{
+		Data: PatientBleedingRates{
+			Current:     monthly[len(monthly)-1],
+			Monthly:     monthly,
+			Prophylaxes: outProphylaxes,
+		},
+	}, nil
+}
@@ -3,6 +3,7 @@ package actions
 import (
 	"errors"
 	"shs/app/models"
+	"strings"
 )
 
 type BloodTestField struct {
@@ -13,6 +14,12 @@ type BloodTestField struct {
 	MinValueString string               `json:"min_value_string"`
 	MaxValueNumber float64              `json:"max_value_number"`
 	MaxValueString string               `json:"max_value_string"`
+	// Formula computes the field from its sibling fields, see [formula.Parse].
+	Formula string `json:"formula"`
+	// LoincCode is the field's LOINC code, where it's empty when the field isn't coded.
+	LoincCode string `json:"loinc_code"`
+	// ReferenceRanges override the min and max values by the patient's age and sex.
+	ReferenceRanges []ReferenceRange `json:"reference_ranges"`
 }
 
 type BloodTest struct {
@@ -25,13 +32,20 @@ type BloodTest struct {
 func (bt BloodTest) IntoModel() models.BloodTest {
 	bloodTestFields := make([]models.BloodTestField, 0, len(bt.Fields))
 	for _, field := range bt.Fields {
+		referenceRanges := make([]models.BloodTestReferenceRange, 0, len(field.ReferenceRanges))
+		for _, rr := range field.ReferenceRanges {
+			referenceRanges = append(referenceRanges, rr.IntoModel())
+		}
 		bloodTestFields = append(bloodTestFields, models.BloodTestField{
-			Name:           field.Name,
-			Unit:           field.Unit,
-			MinValueNumber: field.MinValueNumber,
-			MinValueString: field.MinValueString,
-			MaxValueNumber: field.MaxValueNumber,
-			MaxValueString: field.MaxValueString,
+			Name:            field.Name,
+			Unit:            field.Unit,
+			MinValueNumber:  field.MinValueNumber,
+			MinValueString:  field.MinValueString,
+			MaxValueNumber:  field.MaxValueNumber,
+			MaxValueString:  field.MaxValueString,
+			Formula:         field.Formula,
+			LoincCode:       strings.TrimSpace(field.LoincCode),
+			ReferenceRanges: referenceRanges,
 		})
 	}
 	return models.BloodTest{
@@ -44,14 +58,23 @@ func (bt BloodTest) IntoModel() models.BloodTest {
 func (bt *BloodTest) FromModel(bloodTest models.BloodTest) {
 	btFields := make([]BloodTestField, 0, len(bloodTest.Fields))
 	for _, field := range bloodTest.Fields {
+		referenceRanges := make([]ReferenceRange, 0, len(field.ReferenceRanges))
+		for _, rr := range field.ReferenceRanges {
+			outRange := new(ReferenceRange)
+			outRange.FromModel(rr)
+			referenceRanges = append(referenceRanges, *outRange)
+		}
 		btFields = append(btFields, BloodTestField{
-			Id:             field.Id,
-			Name:           field.Name,
-			Unit:           field.Unit,
-			MinValueNumber: field.MinValueNumber,
-			MinValueString: field.MinValueString,
-			MaxValueNumber: field.MaxValueNumber,
-			MaxValueString: field.MaxValueString,
+			Id:              field.Id,
+			Name:            field.Name,
+			Unit:            field.Unit,
+			MinValueNumber:  field.MinValueNumber,
+			MinValueString:  field.MinValueString,
+			MaxValueNumber:  field.MaxValueNumber,
+			MaxValueString:  field.MaxValueString,
+			Formula:         field.Formula,
+			LoincCode:       field.LoincCode,
+			ReferenceRanges: referenceRanges,
 		})
 	}
 
@@ -76,7 +99,30 @@ func (a *Actions) CreateBloodTest(params CreateBloodTestParams) (CreateBloodTest
 		return CreateBloodTestPayload{}, ErrPermissionDenied{}
 	}
 
-	_, err := a.app.CreateBloodTest(params.BloodTest.IntoModel())
+	for _, field := range params.BloodTest.Fields {
+		for _, rr := range field.ReferenceRanges {
+			err := rr.Validate()
+			if err != nil {
+				return CreateBloodTestPayload{}, err
+			}
+		}
+	}
+
+	err := validateBloodTestFormulas(params.BloodTest.Fields)
+	if err != nil {
+		return CreateBloodTestPayload{}, err
+	}
+
+	bloodTests, err := a.app.ListAllBloodTests()
+	if err != nil {
+		return CreateBloodTestPayload{}, err
+	}
+	err = validateBloodTestLoincCodes(params.BloodTest.Fields, bloodTests)
+	if err != nil {
+		return CreateBloodTestPayload{}, err
+	}
+
+	_, err = a.app.CreateBloodTest(params.BloodTest.IntoModel())
 	if err != nil {
 		return CreateBloodTestPayload{}, err
 	}
@@ -0,0 +1,153 @@
+package actions
+
+import (
+	"math"
+	"shs/app/models"
+	"shs/formula"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// computedFieldDecimals is the number of decimal places that a computed
+// field's value is rounded to, as ratios and indices are reported.
+const computedFieldDecimals = 2
+
+// Computed reports whether the field's value is computed from its formula.
+func (f BloodTestField) Computed() bool {
+	return strings.TrimSpace(f.Formula) != ""
+}
+
+// InputFields returns the blood test's fields that are filled by hand, i.e.
+// without the computed ones.
+func (bt BloodTest) InputFields() []BloodTestField {
+	return slices.DeleteFunc(slices.Clone(bt.Fields), func(field BloodTestField) bool {
+		return field.Computed()
+	})
+}
+
+// validateBloodTestFormulas checks that every formula parses, and references
+// existing sibling fields that aren't ambiguous by their names, and that the
+// formulas don't depend on themselves.
+func validateBloodTestFormulas(fields []BloodTestField) error {
+	namesCount := make(map[string]int)
+	for _, field := range fields {
+		namesCount[strings.TrimSpace(field.Name)]++
+	}
+
+	dependencies := make(map[string][]string)
+	for _, field := range fields {
+		if !field.Computed() {
+			continue
+		}
+
+		expression, err := formula.Parse(field.Formula)
+		if err != nil {
+			return ErrValidation{Field: "formula"}
+		}
+		for _, variable := range expression.Variables() {
+			if namesCount[variable] != 1 || variable == strings.TrimSpace(field.Name) {
+				return ErrValidation{Field: "formula"}
+			}
+		}
+		dependencies[strings.TrimSpace(field.Name)] = expression.Variables()
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	states := make(map[string]int)
+	var hasCycle func(name string) bool
+	hasCycle = func(name string) bool {
+		switch states[name] {
+		case visiting:
+			return true
+		case visited:
+			return false
+		}
+
+		states[name] = visiting
+		for _, dependency := range dependencies[name] {
+			if hasCycle(dependency) {
+				return true
+			}
+		}
+		states[name] = visited
+
+		return false
+	}
+	for name := range dependencies {
+		if hasCycle(name) {
+			return ErrValidation{Field: "formula"}
+		}
+	}
+
+	return nil
+}
+
+// computeBloodTestFormulaFields replaces the computed fields' values of a
+// result with the ones computed from its other fields, where a computed field
+// that can't be evaluated, e.g. when a field it uses was left empty, is left
+// empty as well.
+func computeBloodTestFormulaFields(bloodTest models.BloodTest, filledFields []models.BloodTestFilledField) []models.BloodTestFilledField {
+	fieldsById := make(map[uint]models.BloodTestField, len(bloodTest.Fields))
+	computedFields := make([]models.BloodTestField, 0)
+	for _, field := range bloodTest.Fields {
+		fieldsById[field.Id] = field
+		if strings.TrimSpace(field.Formula) != "" {
+			computedFields = append(computedFields, field)
+		}
+	}
+	if len(computedFields) == 0 {
+		return filledFields
+	}
+
+	values := make(map[string]float64)
+	outFields := make([]models.BloodTestFilledField, 0, len(filledFields)+len(computedFields))
+	for _, filledField := range filledFields {
+		field, ok := fieldsById[filledField.BloodTestFieldId]
+		if ok && strings.TrimSpace(field.Formula) != "" {
+			continue
+		}
+		if value, isNumber := filledField.Number(); ok && isNumber {
+			values[strings.TrimSpace(field.Name)] = value
+		}
+		outFields = append(outFields, filledField)
+	}
+
+	// a computed field can use other computed fields, so the fields are
+	// evaluated until no more fields can be, which is at most a pass for each
+	// field, since the formulas are checked for cycles when they're defined.
+	for range computedFields {
+		progressed := false
+		computedFields = slices.DeleteFunc(computedFields, func(field models.BloodTestField) bool {
+			expression, err := formula.Parse(field.Formula)
+			if err != nil {
+				return true
+			}
+			value, err := expression.Evaluate(values)
+			if err != nil {
+				return false
+			}
+
+			scale := math.Pow10(computedFieldDecimals)
+			value = math.Round(value*scale) / scale
+			values[strings.TrimSpace(field.Name)] = value
+			outFields = append(outFields, models.BloodTestFilledField{
+				BloodTestFieldId: field.Id,
+				ValueNumber:      value,
+				ValueString:      strconv.FormatFloat(value, 'f', -1, 64),
+			})
+			progressed = true
+
+			return true
+		})
+		if !progressed {
+			break
+		}
+	}
+
+	return outFields
+}
@@ -0,0 +1,100 @@
+package actions
+
+import "testing"
+
+func TestValidateBloodTestFormulas(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  []BloodTestField
+		wantErr bool
+	}{
+		{
+			name: "no formulas",
+			fields: []BloodTestField{
+				{Name: "Hb"},
+				{Name: "Hct"},
+			},
+		},
+		{
+			name: "formula of input fields",
+			fields: []BloodTestField{
+				{Name: "Hb"},
+				{Name: "Hct"},
+				{Name: "MCHC", Formula: "{Hb} / {Hct} * 100"},
+			},
+		},
+		{
+			name: "formula of a computed field",
+			fields: []BloodTestField{
+				{Name: "a"},
+				{Name: "b", Formula: "{a} * 2"},
+				{Name: "c", Formula: "{b} + {a}"},
+			},
+		},
+		{
+			name: "field names are trimmed",
+			fields: []BloodTestField{
+				{Name: " Factor VIII "},
+				{Name: "Half", Formula: "{Factor VIII} / 2"},
+			},
+		},
+		{
+			name: "self reference",
+			fields: []BloodTestField{
+				{Name: "a", Formula: "{a} + 1"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "two fields cycle",
+			fields: []BloodTestField{
+				{Name: "a", Formula: "{b} + 1"},
+				{Name: "b", Formula: "{a} + 1"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "three fields cycle",
+			fields: []BloodTestField{
+				{Name: "x"},
+				{Name: "a", Formula: "{b} * {x}"},
+				{Name: "b", Formula: "{c} * 2"},
+				{Name: "c", Formula: "{a} / 2"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing field",
+			fields: []BloodTestField{
+				{Name: "a", Formula: "{b} + 1"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ambiguous field",
+			fields: []BloodTestField{
+				{Name: "a"},
+				{Name: "a"},
+				{Name: "b", Formula: "{a} + 1"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "syntax error",
+			fields: []BloodTestField{
+				{Name: "a"},
+				{Name: "b", Formula: "{a} +"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBloodTestFormulas(tt.fields)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateBloodTestFormulas() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
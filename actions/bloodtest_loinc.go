@@ -0,0 +1,107 @@
+package actions
+
+import (
+	"shs/app/models"
+	"strings"
+)
+
+// isLoincField reports whether a field is the one that's coded with the LOINC
+// code, where a field that isn't coded yet is matched by its legacy name, as
+// the fields were looked up by their names before they had codes.
+func isLoincField(loincCode, legacyName, fieldLoincCode, fieldName string) bool {
+	if fieldLoincCode != "" {
+		return fieldLoincCode == loincCode
+	}
+
+	return fieldName == legacyName
+}
+
+// findLoincField finds the field that's coded with the LOINC code, or the
+// uncoded field with the legacy name when no field is coded with it.
+func findLoincField(bloodTests []models.BloodTest, loincCode, legacyName string) (models.BloodTestField, bool) {
+	var legacyField models.BloodTestField
+	hasLegacyField := false
+	for _, bt := range bloodTests {
+		for _, field := range bt.Fields {
+			if field.LoincCode == loincCode {
+				return field, true
+			}
+			if !hasLegacyField && isLoincField(loincCode, legacyName, field.LoincCode, field.Name) {
+				legacyField, hasLegacyField = field, true
+			}
+		}
+	}
+
+	return legacyField, hasLegacyField
+}
+
+// validateBloodTestLoincCodes checks that the new fields' codes are LOINC
+// codes, and that a code isn't assigned to more than a field, including the
+// existing blood tests' fields.
+func validateBloodTestLoincCodes(fields []BloodTestField, bloodTests []models.BloodTest) error {
+	assigned := make(map[string]bool)
+	for _, bt := range bloodTests {
+		for _, field := range bt.Fields {
+			if field.LoincCode != "" {
+				assigned[field.LoincCode] = true
+			}
+		}
+	}
+
+	for _, field := range fields {
+		loincCode := strings.TrimSpace(field.LoincCode)
+		if loincCode == "" {
+			continue
+		}
+		if !models.ValidLoincCode(loincCode) || assigned[loincCode] {
+			return ErrValidation{Field: "loinc_code"}
+		}
+		assigned[loincCode] = true
+	}
+
+	return nil
+}
+
+type UpdateBloodTestFieldLoincCodeParams struct {
+	ActionContext
+	BloodTestId      uint   `json:"blood_test_id"`
+	BloodTestFieldId uint   `json:"blood_test_field_id"`
+	LoincCode        string `json:"loinc_code"`
+}
+
+type UpdateBloodTestFieldLoincCodePayload struct {
+}
+
+// UpdateBloodTestFieldLoincCode assigns a LOINC code to a field, where an
+// empty code clears the field's code.
+func (a *Actions) UpdateBloodTestFieldLoincCode(params UpdateBloodTestFieldLoincCodeParams) (UpdateBloodTestFieldLoincCodePayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionWriteBloodTest) {
+		return UpdateBloodTestFieldLoincCodePayload{}, ErrPermissionDenied{}
+	}
+
+	bloodTests, err := a.app.ListAllBloodTests()
+	if err != nil {
+		return UpdateBloodTestFieldLoincCodePayload{}, err
+	}
+
+	field, ok := newBloodTestsIndex(bloodTests).fields[params.BloodTestFieldId]
+	if !ok || field.BloodTestId != params.BloodTestId {
+		return UpdateBloodTestFieldLoincCodePayload{}, ErrValidation{Field: "blood_test_field_id"}
+	}
+
+	loincCode := strings.TrimSpace(params.LoincCode)
+	if loincCode == field.LoincCode {
+		return UpdateBloodTestFieldLoincCodePayload{}, nil
+	}
+	err = validateBloodTestLoincCodes([]BloodTestField{{LoincCode: loincCode}}, bloodTests)
+	if err != nil {
+		return UpdateBloodTestFieldLoincCodePayload{}, err
+	}
+
+	err = a.app.UpdateBloodTestFieldLoincCode(field.Id, loincCode)
+	if err != nil {
+		return UpdateBloodTestFieldLoincCodePayload{}, err
+	}
+
+	return UpdateBloodTestFieldLoincCodePayload{}, nil
+}
@@ -0,0 +1,146 @@
+package actions
+
+import (
+	"cmp"
+	"shs/app/models"
+	"slices"
+	"strings"
+	"time"
+)
+
+type BloodTestTrendPoint struct {
+	BloodTestResultId uint      `json:"blood_test_result_id"`
+	TestedAt          time.Time `json:"tested_at"`
+	Value             float64   `json:"value"`
+	Flag              string    `json:"flag"`
+	HasReferenceRange bool      `json:"has_reference_range"`
+	ReferenceLow      float64   `json:"reference_low"`
+	ReferenceHigh     float64   `json:"reference_high"`
+}
+
+// BloodTestFieldTrend is a field's numeric values across the patient's results,
+// ordered by when they were tested, where each point has the reference range
+// that was used for it, since the range can change with the patient's age.
+type BloodTestFieldTrend struct {
+	BloodTestId      uint                  `json:"blood_test_id"`
+	BloodTestName    string                `json:"blood_test_name"`
+	BloodTestFieldId uint                  `json:"blood_test_field_id"`
+	FieldName        string                `json:"field_name"`
+	LoincCode        string                `json:"loinc_code"`
+	Unit             models.BlootTestUnit  `json:"unit"`
+	Points           []BloodTestTrendPoint `json:"points"`
+}
+
+// BloodTestTrends groups the patient's non pending results' numeric values by
+// their field, ordered by the blood test and field names.
+func (p Patient) BloodTestTrends() []BloodTestFieldTrend {
+	trendsIdx := make(map[uint]int)
+	trends := make([]BloodTestFieldTrend, 0)
+	for _, btr := range p.BloodTestResults {
+		if btr.Pending {
+			continue
+		}
+
+		for _, field := range btr.FilledFields {
+			value, ok := field.Number()
+			if !ok {
+				continue
+			}
+
+			idx, ok := trendsIdx[field.BloodTestFieldId]
+			if !ok {
+				idx = len(trends)
+				trendsIdx[field.BloodTestFieldId] = idx
+				trends = append(trends, BloodTestFieldTrend{
+					BloodTestId:      btr.BloodTestId,
+					BloodTestName:    btr.Name,
+					BloodTestFieldId: field.BloodTestFieldId,
+					FieldName:        field.Name,
+					LoincCode:        field.LoincCode,
+					Unit:             field.Unit,
+					Points:           make([]BloodTestTrendPoint, 0),
+				})
+			}
+
+			trends[idx].Points = append(trends[idx].Points, BloodTestTrendPoint{
+				BloodTestResultId: btr.Id,
+				TestedAt:          btr.TestedAt,
+				Value:             value,
+				Flag:              field.Flag,
+				HasReferenceRange: field.HasReferenceRange,
+				ReferenceLow:      field.ReferenceLow,
+				ReferenceHigh:     field.ReferenceHigh,
+			})
+		}
+	}
+
+	for _, trend := range trends {
+		slices.SortStableFunc(trend.Points, func(a, b BloodTestTrendPoint) int {
+			return a.TestedAt.Compare(b.TestedAt)
+		})
+	}
+	slices.SortStableFunc(trends, func(a, b BloodTestFieldTrend) int {
+		return cmp.Or(
+			strings.Compare(a.BloodTestName, b.BloodTestName),
+			strings.Compare(a.FieldName, b.FieldName),
+		)
+	})
+
+	return trends
+}
+
+type ListPatientBloodTestTrendsParams struct {
+	ActionContext
+	PatientId string
+	// BloodTestFieldId limits the trends to a single field when it's not zero.
+	BloodTestFieldId uint
+}
+
+type ListPatientBloodTestTrendsPayload struct {
+	Data []BloodTestFieldTrend `json:"data"`
+}
+
+func (a *Actions) ListPatientBloodTestTrends(params ListPatientBloodTestTrendsParams) (ListPatientBloodTestTrendsPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadPatient) {
+		return ListPatientBloodTestTrendsPayload{}, ErrPermissionDenied{}
+	}
+	if !params.Account.HasPermission(models.AccountPermissionReadBloodTest) {
+		return ListPatientBloodTestTrendsPayload{}, ErrPermissionDenied{}
+	}
+
+	patient, err := a.app.GetPatientByPublicId(params.PatientId)
+	if err != nil {
+		return ListPatientBloodTestTrendsPayload{}, err
+	}
+
+	bloodTests, err := a.app.ListAllBloodTests()
+	if err != nil {
+		return ListPatientBloodTestTrendsPayload{}, err
+	}
+
+	bloodTestResults, err := a.app.ListPatientBloodTestResults(patient.Id)
+	if err != nil {
+		return ListPatientBloodTestTrendsPayload{}, err
+	}
+
+	outPatient := new(Patient)
+	outPatient.FromModel(patient)
+	outPatient.WithBloodTestResults(bloodTestResults, bloodTests)
+
+	trends := outPatient.BloodTestTrends()
+	if params.BloodTestFieldId != 0 {
+		trends = slices.DeleteFunc(trends, func(trend BloodTestFieldTrend) bool {
+			return trend.BloodTestFieldId != params.BloodTestFieldId
+		})
+	}
+
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "ListPatientBloodTestTrends",
+		Kind:            models.AuditEventKindRead,
+		PatientPublicId: params.PatientId,
+	})
+
+	return ListPatientBloodTestTrendsPayload{
+		Data: trends,
+	}, nil
+}
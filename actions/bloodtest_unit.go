@@ -0,0 +1,63 @@
+package actions
+
+import (
+	"shs/app/models"
+	"shs/unitconv"
+	"strconv"
+)
+
+// convertedValueSignificantDigits is the precision that a converted value is
+// kept in, which is more than any lab reports.
+const convertedValueSignificantDigits = 6
+
+// normalizeBloodTestFilledFields converts the entered fields into the blood
+// test's fields, where a field that's entered with a unit other than its
+// field's is converted to the field's unit, and keeps its entered value and
+// unit, and a field without a unit is taken as is in the field's unit.
+func normalizeBloodTestFilledFields(bloodTest models.BloodTest, filledFields []BloodTestFilledField) ([]models.BloodTestFilledField, error) {
+	fieldsById := make(map[uint]models.BloodTestField, len(bloodTest.Fields))
+	for _, field := range bloodTest.Fields {
+		fieldsById[field.Id] = field
+	}
+
+	outFields := make([]models.BloodTestFilledField, 0, len(filledFields))
+	for _, filledField := range filledFields {
+		// the API's clients can send a field's number without its text, where
+		// the number is the value that was entered, so that it's kept as the
+		// field's text like the values that are entered as text.
+		if filledField.ValueString == "" {
+			filledField.ValueString = strconv.FormatFloat(filledField.ValueNumber, 'f', -1, 64)
+		}
+
+		outField := models.BloodTestFilledField{
+			BloodTestFieldId: filledField.BloodTestFieldId,
+			ValueNumber:      filledField.ValueNumber,
+			ValueString:      filledField.ValueString,
+		}
+
+		field, ok := fieldsById[filledField.BloodTestFieldId]
+		if !ok || filledField.Unit == "" || filledField.Unit == field.Unit {
+			outFields = append(outFields, outField)
+			continue
+		}
+
+		value, isNumber := filledField.Number()
+		if !isNumber {
+			return nil, ErrValidation{Field: "unit"}
+		}
+		converted, err := unitconv.Convert(value, filledField.Unit, field.Unit, field.Name, bloodTest.Name)
+		if err != nil {
+			return nil, ErrValidation{Field: "unit"}
+		}
+		converted = unitconv.Round(converted, convertedValueSignificantDigits)
+
+		outField.ValueNumber = converted
+		outField.ValueString = strconv.FormatFloat(converted, 'f', -1, 64)
+		outField.OriginalValue = filledField.ValueString
+		outField.OriginalUnit = filledField.Unit
+
+		outFields = append(outFields, outField)
+	}
+
+	return outFields, nil
+}
@@ -0,0 +1,229 @@
+package actions
+
+import (
+	"fmt"
+	"shs/app/models"
+	"shs/icalgen"
+	"strings"
+	"time"
+)
+
+const (
+	calendarFeedTokenTtlDays = 365
+	// calendarFeedPastDays and calendarFeedUpcomingDays are the feed's window,
+	// where the past days keep the recent history in the subscribed calendars.
+	calendarFeedPastDays     = 30
+	calendarFeedUpcomingDays = 90
+)
+
+// calendarFeedAlarms are the reminders of an all day infusion event, one in
+// the evening before it and one in its morning.
+var calendarFeedAlarms = []icalgen.Alarm{
+	{Offset: -4 * time.Hour, Description: "Prophylaxis infusion tomorrow - جرعة وقائية غداً"},
+	{Offset: 8 * time.Hour, Description: "Prophylaxis infusion today - جرعة وقائية اليوم"},
+}
+
+// calendarFeedAppointmentAlarms are the reminders of a clinic appointment, one
+// a day before it and one two hours before it.
+var calendarFeedAppointmentAlarms = []icalgen.Alarm{
+	{Offset: -24 * time.Hour, Description: "Clinic appointment tomorrow - موعد في العيادة غداً"},
+	{Offset: -2 * time.Hour, Description: "Clinic appointment soon - موعد في العيادة قريباً"},
+}
+
+type GetOwnCalendarFeedTokenParams struct {
+	ActionContext
+}
+
+type GetOwnCalendarFeedTokenPayload struct {
+	Token string `json:"token"`
+}
+
+// GetOwnCalendarFeedToken signs a long living token for the patient's calendar
+// feed, since calendar apps can't send the session token when they refresh a
+// subscribed calendar, where the account's calendar feed version is
+// incremented, so that the previously issued token is revoked.
+func (a *Actions) GetOwnCalendarFeedToken(params GetOwnCalendarFeedTokenParams) (GetOwnCalendarFeedTokenPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadOwnVisit) {
+		return GetOwnCalendarFeedTokenPayload{}, ErrPermissionDenied{}
+	}
+
+	_, err := a.app.GetPatientByPublicId(params.Account.Username)
+	if err != nil {
+		return GetOwnCalendarFeedTokenPayload{}, err
+	}
+
+	account, err := a.app.GetAccountById(params.Account.Id)
+	if err != nil {
+		return GetOwnCalendarFeedTokenPayload{}, err
+	}
+	calendarFeedVersion := account.CalendarFeedVersion + 1
+	err = a.app.UpdateAccountCalendarFeedVersion(account.Id, calendarFeedVersion)
+	if err != nil {
+		return GetOwnCalendarFeedTokenPayload{}, err
+	}
+
+	token, err := a.jwt.Sign(TokenPayload{
+		Name:                params.Account.DisplayName,
+		Username:            params.Account.Username,
+		CreatedAt:           time.Now().UTC(),
+		CalendarFeedVersion: calendarFeedVersion,
+	}, JwtCalendarFeedToken, time.Hour*24*calendarFeedTokenTtlDays)
+	if err != nil {
+		return GetOwnCalendarFeedTokenPayload{}, err
+	}
+
+	return GetOwnCalendarFeedTokenPayload{
+		Token: token,
+	}, nil
+}
+
+type GetOwnCalendarFeedParams struct {
+	Token string
+}
+
+type GetOwnCalendarFeedPayload struct {
+	Calendar string `json:"calendar"`
+}
+
+// GetOwnCalendarFeed renders the patient's active prophylaxes' infusion days,
+// visits, and booked appointments as an iCalendar, where the patient is identified by the calendar
+// feed token.
+func (a *Actions) GetOwnCalendarFeed(params GetOwnCalendarFeedParams) (GetOwnCalendarFeedPayload, error) {
+	token, err := a.jwt.Decode(params.Token, JwtCalendarFeedToken)
+	if err != nil {
+		return GetOwnCalendarFeedPayload{}, ErrInvalidSessionToken{}
+	}
+	if !token.Payload.Valid() {
+		return GetOwnCalendarFeedPayload{}, ErrInvalidSessionToken{}
+	}
+
+	account, err := a.app.GetAccountByUsername(token.Payload.Username)
+	if err != nil {
+		return GetOwnCalendarFeedPayload{}, ErrInvalidSessionToken{}
+	}
+	if token.Payload.CalendarFeedVersion != account.CalendarFeedVersion {
+		return GetOwnCalendarFeedPayload{}, ErrInvalidSessionToken{}
+	}
+	if !account.HasPermission(models.AccountPermissionReadOwnVisit) {
+		return GetOwnCalendarFeedPayload{}, ErrPermissionDenied{}
+	}
+
+	patient, err := a.app.GetPatientByPublicId(account.Username)
+	if err != nil {
+		return GetOwnCalendarFeedPayload{}, err
+	}
+
+	now := time.Now().UTC()
+	from := truncateToClinicDay(now).AddDate(0, 0, -calendarFeedPastDays)
+	to := truncateToClinicDay(now).AddDate(0, 0, calendarFeedUpcomingDays)
+
+	prophylaxes, err := a.app.ListProphylaxesForPatient(patient.Id)
+	if err != nil {
+		return GetOwnCalendarFeedPayload{}, err
+	}
+
+	events := make([]icalgen.Event, 0)
+	for _, pp := range prophylaxes {
+		if !pp.Chosen || (!pp.EndDate.IsZero() && pp.EndDate.Before(from)) {
+			continue
+		}
+
+		description := prophylaxisInfusionDescription(pp)
+		for _, day := range prophylaxisExpectedInfusions(pp, from, to) {
+			event := icalgen.Event{
+				Uid:         fmt.Sprintf("prophylaxis-%d-%s@%s", pp.Id, day.Format(time.DateOnly), patient.PublicId),
+				Summary:     fmt.Sprintf("%s - جرعة وقائية", pp.Title),
+				Description: description,
+				Start:       day,
+				AllDay:      true,
+			}
+			if !day.Before(truncateToClinicDay(now)) {
+				event.Alarms = calendarFeedAlarms
+			}
+			events = append(events, event)
+		}
+	}
+
+	visits, err := a.app.ListPatientVisits(patient.Id)
+	if err != nil {
+		return GetOwnCalendarFeedPayload{}, err
+	}
+
+	for _, visit := range visits {
+		if visit.CreatedAt.Before(from) || !visit.CreatedAt.Before(to) {
+			continue
+		}
+
+		events = append(events, icalgen.Event{
+			Uid:         fmt.Sprintf("visit-%d@%s", visit.Id, patient.PublicId),
+			Summary:     "Visit - زيارة",
+			Description: strings.ReplaceAll(string(visit.Reason), "_", " "),
+			Start:       visit.CreatedAt,
+			End:         visit.CreatedAt.Add(time.Hour),
+		})
+	}
+
+	appointments, err := a.app.ListPatientAppointments(patient.Id)
+	if err != nil {
+		return GetOwnCalendarFeedPayload{}, err
+	}
+
+	for _, appointment := range appointments {
+		if appointment.Status != models.AppointmentStatusBooked ||
+			appointment.ScheduledAt.Before(from) || !appointment.ScheduledAt.Before(to) {
+			continue
+		}
+
+		event := icalgen.Event{
+			Uid:         fmt.Sprintf("appointment-%d@%s", appointment.Id, patient.PublicId),
+			Summary:     "Clinic appointment - موعد في العيادة",
+			Description: strings.ReplaceAll(string(appointment.Reason), "_", " "),
+			Start:       appointment.ScheduledAt,
+			End:         appointment.ScheduledAt.Add(time.Hour),
+		}
+		if appointment.ScheduledAt.After(now) {
+			event.Alarms = calendarFeedAppointmentAlarms
+		}
+		events = append(events, event)
+	}
+
+	calendar := new(strings.Builder)
+	err = icalgen.Generate(calendar, icalgen.Calendar{
+		Name:            "SHS - " + account.DisplayName,
+		RefreshInterval: 12 * time.Hour,
+		Events:          events,
+	}, now)
+	if err != nil {
+		return GetOwnCalendarFeedPayload{}, err
+	}
+
+	// the feed is read by the patient's calendar app, which is only identified
+	// by the token.
+	feedAccount := new(Account)
+	feedAccount.FromModel(account)
+	a.audit(ActionContext{Account: *feedAccount}, auditEntry{
+		Action:          "GetOwnCalendarFeed",
+		Kind:            models.AuditEventKindRead,
+		PatientPublicId: patient.PublicId,
+	})
+
+	return GetOwnCalendarFeedPayload{
+		Calendar: calendar.String(),
+	}, nil
+}
+
+func prophylaxisInfusionDescription(pp models.Prophylaxis) string {
+	sb := new(strings.Builder)
+	sb.WriteString(pp.Medicine.Name)
+	if pp.Medicine.Factor != "" {
+		fmt.Fprintf(sb, " (%s)", pp.Medicine.Factor)
+	}
+	if pp.DoseIu > 0 {
+		fmt.Fprintf(sb, "\n%d IU", pp.DoseIu)
+	}
+	if pp.MedicineAmount > 0 {
+		fmt.Fprintf(sb, "\n%d × %d %s", pp.MedicineAmount, pp.Medicine.Dose, pp.Medicine.Unit)
+	}
+
+	return sb.String()
+}
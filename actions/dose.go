@@ -0,0 +1,213 @@
+package actions
+
+import (
+	"maps"
+	"math"
+	"shs/app/models"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	FactorTypeVIII = "viii"
+	FactorTypeIX   = "ix"
+)
+
+// factorIuPerKgPerDl is the amount of IU/kg needed to raise the plasma factor
+// level by 1 IU/dL (i.e. 1%), 1 IU/kg of FVIII raises it by ~2 IU/dL, and 1
+// IU/kg of FIX raises it by ~1 IU/dL.
+var factorIuPerKgPerDl = map[string]float64{
+	FactorTypeVIII: 0.5,
+	FactorTypeIX:   1,
+}
+
+// maxDosePatientWeight (kg) and maxDoseTargetRise (IU/dL) are the clinical
+// upper bounds of the dose calculator's inputs, which also bound the required
+// IU that the vials are picked for.
+const (
+	maxDosePatientWeight = 250
+	maxDoseTargetRise    = 200
+)
+
+// medicineFactorType maps a medicine's free text factor, e.g. "FVIII", "Factor 8"
+// or "IX", into one of the factor types above, and returns an empty string for
+// anything else.
+func medicineFactorType(factor string) string {
+	factor = strings.ToUpper(strings.TrimSpace(factor))
+	factor = strings.TrimPrefix(factor, "FACTOR")
+	factor = strings.TrimPrefix(strings.TrimSpace(factor), "F")
+
+	switch strings.TrimSpace(factor) {
+	case "VIII", "8":
+		return FactorTypeVIII
+	case "IX", "9":
+		return FactorTypeIX
+	default:
+		return ""
+	}
+}
+
+type CalculateFactorDoseParams struct {
+	ActionContext
+	PatientWeight float64 `json:"patient_weight"`
+	// TargetRise is the wanted factor level rise in IU/dL (or %), e.g. 50 for
+	// a joint bleed.
+	TargetRise float64 `json:"target_rise"`
+	FactorType string  `json:"factor_type"`
+}
+
+type FactorDose struct {
+	RequiredIu   int `json:"required_iu"`
+	PrescribedIu int `json:"prescribed_iu"`
+	// ShortfallIu is set when the in-stock medicines aren't enough to cover
+	// the required dose.
+	ShortfallIu int `json:"shortfall_iu"`
+	// Medicines are the batches to prescribe, where Amount is the number of
+	// packages to prescribe from each batch.
+	Medicines []Medicine `json:"medicines"`
+}
+
+type CalculateFactorDosePayload struct {
+	Data FactorDose `json:"data"`
+}
+
+// CalculateFactorDose computes the required IU for the given weight and target
+// rise, and picks the packages from the in-stock batches of the given factor.
+//
+// The dose is never rounded down, so the packages are picked such that their
+// total is the closest to the required IU without going below it, mixing vial
+// sizes as needed, and with the least number of packages when there's a tie,
+// where the batches that expire first are used first.
+func (a *Actions) CalculateFactorDose(params CalculateFactorDoseParams) (CalculateFactorDosePayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadMedicine) {
+		return CalculateFactorDosePayload{}, ErrPermissionDenied{}
+	}
+
+	iuPerKgPerDl, ok := factorIuPerKgPerDl[params.FactorType]
+	if !ok {
+		return CalculateFactorDosePayload{}, ErrValidation{Field: "factor_type"}
+	}
+	if !(params.PatientWeight > 0 && params.PatientWeight <= maxDosePatientWeight) {
+		return CalculateFactorDosePayload{}, ErrValidation{Field: "patient_weight"}
+	}
+	if !(params.TargetRise > 0 && params.TargetRise <= maxDoseTargetRise) {
+		return CalculateFactorDosePayload{}, ErrValidation{Field: "target_rise"}
+	}
+
+	medicines, err := a.app.ListAllMedicines()
+	if err != nil {
+		return CalculateFactorDosePayload{}, err
+	}
+
+	now := time.Now()
+	batches := make([]models.Medicine, 0, len(medicines))
+	for _, med := range medicines {
+		if med.Amount <= 0 || med.Dose <= 0 || med.IsExpiredAt(now) {
+			continue
+		}
+		if !strings.EqualFold(med.Unit, "IU") || medicineFactorType(med.Factor) != params.FactorType {
+			continue
+		}
+		batches = append(batches, med)
+	}
+	slices.SortStableFunc(batches, func(a, b models.Medicine) int {
+		return a.ExpiresAt.Compare(b.ExpiresAt)
+	})
+
+	requiredIu := int(math.Ceil(params.PatientWeight * params.TargetRise * iuPerKgPerDl))
+
+	vialsStock := make(map[int]int)
+	for _, batch := range batches {
+		vialsStock[batch.Dose] += batch.Amount
+	}
+	vialsCount := pickFactorVials(requiredIu, vialsStock)
+
+	dose := FactorDose{
+		RequiredIu: requiredIu,
+		Medicines:  make([]Medicine, 0),
+	}
+	for _, batch := range batches {
+		packages := min(vialsCount[batch.Dose], batch.Amount)
+		if packages == 0 {
+			continue
+		}
+		vialsCount[batch.Dose] -= packages
+		dose.PrescribedIu += packages * batch.Dose
+
+		outMedicine := new(Medicine)
+		outMedicine.FromModel(batch)
+		outMedicine.Amount = packages
+		dose.Medicines = append(dose.Medicines, *outMedicine)
+	}
+	dose.ShortfallIu = max(0, requiredIu-dose.PrescribedIu)
+
+	return CalculateFactorDosePayload{
+		Data: dose,
+	}, nil
+}
+
+// pickFactorVials returns the count of vials to use per vial size, such that
+// their total is the smallest one that's at least requiredIu, with the least
+// number of vials, and if the stock can't cover requiredIu, all of it is used.
+func pickFactorVials(requiredIu int, vialsStock map[int]int) map[int]int {
+	sizes := make([]int, 0, len(vialsStock))
+	totalStockIu := 0
+	for size, count := range vialsStock {
+		sizes = append(sizes, size)
+		totalStockIu += size * count
+	}
+	slices.Sort(sizes)
+
+	if totalStockIu <= requiredIu {
+		return maps.Clone(vialsStock)
+	}
+
+	// the search goes up to requiredIu plus the largest vial, since any total
+	// beyond that has a smaller total that still covers requiredIu.
+	limit := requiredIu + sizes[len(sizes)-1]
+	const unreachable = math.MaxInt
+
+	// leastVials[t] is the least number of vials out of the sizes so far that
+	// sum up exactly to t, and chosenCounts[i][t] is how many vials of sizes[i]
+	// were used to get there.
+	leastVials := make([]int, limit+1)
+	for t := range leastVials {
+		leastVials[t] = unreachable
+	}
+	leastVials[0] = 0
+	chosenCounts := make([][]int, len(sizes))
+
+	for i, size := range sizes {
+		nextLeastVials := make([]int, limit+1)
+		chosenCounts[i] = make([]int, limit+1)
+		maxCount := min(vialsStock[size], limit/size)
+		for t := range nextLeastVials {
+			nextLeastVials[t] = unreachable
+			for count := 0; count <= maxCount && count*size <= t; count++ {
+				prev := leastVials[t-count*size]
+				if prev == unreachable || prev+count >= nextLeastVials[t] {
+					continue
+				}
+				nextLeastVials[t] = prev + count
+				chosenCounts[i][t] = count
+			}
+		}
+		leastVials = nextLeastVials
+	}
+
+	for t := requiredIu; t <= limit; t++ {
+		if leastVials[t] == unreachable {
+			continue
+		}
+
+		vialsCount := make(map[int]int)
+		for i := len(sizes) - 1; i >= 0; i-- {
+			vialsCount[sizes[i]] = chosenCounts[i][t]
+			t -= chosenCounts[i][t] * sizes[i]
+		}
+		return vialsCount
+	}
+
+	return maps.Clone(vialsStock)
+}
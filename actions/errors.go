@@ -1,6 +1,9 @@
 package actions
 
-import "net/http"
+import (
+	"net/http"
+	"time"
+)
 
 type ErrInvalidLoginCredientials struct{}
 
@@ -175,3 +178,29 @@ func (e ErrInsufficientMedicine) ExtraData() map[string]any {
 func (e ErrInsufficientMedicine) ExposeToClients() bool {
 	return true
 }
+
+type ErrExpiredMedicine struct {
+	MedicineName string
+	BatchNumber  string
+	ExpiresAt    time.Time
+}
+
+func (e ErrExpiredMedicine) Error() string {
+	return "expired-medicine"
+}
+
+func (e ErrExpiredMedicine) ClientStatusCode() int {
+	return http.StatusForbidden
+}
+
+func (e ErrExpiredMedicine) ExtraData() map[string]any {
+	return map[string]any{
+		"medicine_name": e.MedicineName,
+		"batch_number":  e.BatchNumber,
+		"expires_at":    e.ExpiresAt,
+	}
+}
+
+func (e ErrExpiredMedicine) ExposeToClients() bool {
+	return true
+}
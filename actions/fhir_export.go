@@ -0,0 +1,344 @@
+package actions
+
+import (
+	"fmt"
+	"shs/app/models"
+	"shs/fhir"
+	"shs/unitconv"
+	"strings"
+	"time"
+)
+
+// Identifier systems of the patient's ids in the exported record.
+const (
+	fhirPatientPublicIdSystem   = "urn:shs:patient:public-id"
+	fhirPatientNationalIdSystem = "urn:shs:patient:national-id"
+)
+
+type ExportPatientFhirParams struct {
+	ActionContext
+	PatientPublicId string
+}
+
+type ExportPatientFhirPayload struct {
+	Bundle fhir.Bundle `json:"bundle"`
+}
+
+// ExportPatientFhir exports the patient's record as a FHIR R4 collection
+// bundle, so that a patient who's referred to another hospital can carry it,
+// where the visits are only exported when the account can read them.
+func (a *Actions) ExportPatientFhir(params ExportPatientFhirParams) (ExportPatientFhirPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadPatient) {
+		return ExportPatientFhirPayload{}, ErrPermissionDenied{}
+	}
+
+	patient, err := a.getFullPatientByPublicId(params.PatientPublicId)
+	if err != nil {
+		return ExportPatientFhirPayload{}, err
+	}
+
+	visits := make([]Visit, 0)
+	if params.Account.HasPermission(models.AccountPermissionReadOtherVisits) {
+		visitsModels, err := a.app.ListPatientVisits(patient.Id)
+		if err != nil {
+			return ExportPatientFhirPayload{}, err
+		}
+		for _, visit := range visitsModels {
+			outVisit := new(Visit)
+			outVisit.FromModel(visit)
+			visits = append(visits, *outVisit)
+		}
+	}
+
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "ExportPatientFhir",
+		Kind:            models.AuditEventKindRead,
+		PatientPublicId: params.PatientPublicId,
+	})
+
+	return ExportPatientFhirPayload{
+		Bundle: patientFhirBundle(patient, visits, time.Now().UTC()),
+	}, nil
+}
+
+func patientFhirBundle(patient Patient, visits []Visit, now time.Time) fhir.Bundle {
+	bundle := fhir.NewCollection(patient.PublicId, now)
+	subject := bundle.Add(fhirPatient(patient))
+	subject.Display = patient.FullName()
+
+	for _, dr := range patient.Diagnoses {
+		bundle.Add(fhirDiagnosisCondition(patient, dr, subject))
+	}
+	for _, virus := range patient.Viruses {
+		bundle.Add(fhir.Condition{
+			Id:             fmt.Sprintf("%s-virus-%d", patient.PublicId, virus.Id),
+			ClinicalStatus: fhirCodeableConcept(fhir.SystemConditionClinical, "active", "Active"),
+			Category: []fhir.CodeableConcept{
+				*fhirCodeableConcept(fhir.SystemConditionCategory, "problem-list-item", "Problem List Item"),
+			},
+			Code: fhir.CodeableConcept{
+				Text: virus.Name,
+			},
+			Subject: subject,
+		})
+	}
+	for _, btr := range patient.BloodTestResults {
+		for _, field := range btr.FilledFields {
+			bundle.Add(fhirObservation(btr, field, subject))
+		}
+	}
+	for _, pp := range patient.Prophylaxes {
+		bundle.Add(fhirMedicationStatement(pp, subject, now))
+	}
+	for _, visit := range visits {
+		encounter := fhir.Encounter{
+			Id:     fmt.Sprintf("visit-%d", visit.Id),
+			Status: "finished",
+			Class: fhir.Coding{
+				System:  fhir.SystemActCode,
+				Code:    "AMB",
+				Display: "ambulatory",
+			},
+			Subject: subject,
+			Period: &fhir.Period{
+				Start: fhir.DateTime(visit.VisitedAt),
+			},
+		}
+		if visit.Reason != "" {
+			encounter.ReasonCode = []fhir.CodeableConcept{{Text: visit.Reason}}
+		}
+		bundle.Add(encounter)
+	}
+
+	return *bundle
+}
+
+func fhirCodeableConcept(system, code, display string) *fhir.CodeableConcept {
+	return &fhir.CodeableConcept{
+		Coding: []fhir.Coding{{System: system, Code: code, Display: display}},
+	}
+}
+
+func fhirPatient(patient Patient) fhir.Patient {
+	fhirPatient := fhir.Patient{
+		Id: patient.PublicId,
+		Identifier: []fhir.Identifier{
+			{System: fhirPatientPublicIdSystem, Value: patient.PublicId},
+		},
+		Name: []fhir.HumanName{
+			{
+				Use:    "official",
+				Text:   patient.FullName(),
+				Family: patient.LastName,
+				Given:  []string{patient.FirstName},
+			},
+		},
+		Gender:    "female",
+		BirthDate: fhir.Date(patient.DateOfBirth),
+	}
+	if patient.Gender {
+		fhirPatient.Gender = "male"
+	}
+	if patient.NationalId != "" {
+		fhirPatient.Identifier = append(fhirPatient.Identifier, fhir.Identifier{
+			System: fhirPatientNationalIdSystem,
+			Value:  patient.NationalId,
+		})
+	}
+	if patient.PhoneNumber != "" {
+		fhirPatient.Telecom = []fhir.ContactPoint{
+			{
+				System: "phone",
+				Value:  strings.TrimSpace(patient.PhoneNumberCountryCode + " " + patient.PhoneNumber),
+				Use:    "mobile",
+			},
+		}
+	}
+	if patient.Residency.Governorate != "" || patient.Residency.Suburb != "" || patient.Residency.Street != "" {
+		address := fhir.Address{
+			Use:      "home",
+			District: patient.Residency.Suburb,
+			State:    patient.Residency.Governorate,
+		}
+		if patient.Residency.Street != "" {
+			address.Line = []string{patient.Residency.Street}
+		}
+		fhirPatient.Address = []fhir.Address{address}
+	}
+
+	return fhirPatient
+}
+
+func fhirDiagnosisCondition(patient Patient, dr DiagnosisResult, subject fhir.Reference) fhir.Condition {
+	code := fhir.CodeableConcept{
+		Text: dr.Title,
+	}
+	if dr.ICD11 != "" {
+		code.Coding = []fhir.Coding{
+			{System: fhir.SystemIcd11Mms, Code: dr.ICD11, Display: dr.Title},
+		}
+	}
+
+	return fhir.Condition{
+		Id:             fmt.Sprintf("%s-diagnosis-%d", patient.PublicId, dr.Id),
+		ClinicalStatus: fhirCodeableConcept(fhir.SystemConditionClinical, "active", "Active"),
+		Category: []fhir.CodeableConcept{
+			*fhirCodeableConcept(fhir.SystemConditionCategory, "encounter-diagnosis", "Encounter Diagnosis"),
+		},
+		Code:          code,
+		Subject:       subject,
+		OnsetDateTime: fhir.DateTime(dr.DiagnosedAt),
+		RecordedDate:  fhir.DateTime(dr.CreatedAt),
+	}
+}
+
+// fhirInterpretations are the flags' v3 observation interpretation codes.
+var fhirInterpretations = map[string]fhir.Coding{
+	BloodTestFlagLow:          {System: fhir.SystemObservationInterpretation, Code: "L", Display: "Low"},
+	BloodTestFlagHigh:         {System: fhir.SystemObservationInterpretation, Code: "H", Display: "High"},
+	BloodTestFlagCriticalLow:  {System: fhir.SystemObservationInterpretation, Code: "LL", Display: "Critical low"},
+	BloodTestFlagCriticalHigh: {System: fhir.SystemObservationInterpretation, Code: "HH", Display: "Critical high"},
+}
+
+func fhirQuantity(value float64, unit models.BlootTestUnit) *fhir.Quantity {
+	quantity := &fhir.Quantity{
+		Value: value,
+	}
+	if unit != models.BlootTestUnitNoUnit {
+		quantity.Unit = string(unit)
+	}
+	if code, ok := unitconv.Ucum(unit); ok {
+		quantity.System = fhir.SystemUcum
+		quantity.Code = code
+	}
+
+	return quantity
+}
+
+// fhirObservation converts a result's field, where the field's LOINC code is
+// used when it has one, and a pending result is a preliminary observation.
+func fhirObservation(btr BloodTestResult, field BloodTestFilledField, subject fhir.Reference) fhir.Observation {
+	code := fhir.CodeableConcept{
+		Text: field.Name,
+	}
+	if btr.Name != field.Name {
+		code.Text = btr.Name + " - " + field.Name
+	}
+	if field.LoincCode != "" {
+		code.Coding = []fhir.Coding{
+			{System: fhir.SystemLoinc, Code: field.LoincCode, Display: field.Name},
+		}
+	}
+
+	observation := fhir.Observation{
+		Id:     fmt.Sprintf("blood-test-result-%d-field-%d", btr.Id, field.BloodTestFieldId),
+		Status: "final",
+		Category: []fhir.CodeableConcept{
+			*fhirCodeableConcept(fhir.SystemObservationCategory, "laboratory", "Laboratory"),
+		},
+		Code:              code,
+		Subject:           subject,
+		EffectiveDateTime: fhir.DateTime(btr.TestedAt),
+	}
+	if btr.Pending {
+		observation.Status = "preliminary"
+	}
+
+	if value, ok := field.Number(); ok {
+		observation.ValueQuantity = fhirQuantity(value, field.Unit)
+	} else {
+		observation.ValueString = field.ValueString
+	}
+
+	if field.HasReferenceRange {
+		observation.ReferenceRange = []fhir.ObservationReferenceRange{
+			{
+				Low:  fhirQuantity(field.ReferenceLow, field.Unit),
+				High: fhirQuantity(field.ReferenceHigh, field.Unit),
+			},
+		}
+		interpretation, ok := fhirInterpretations[field.Flag]
+		if !ok {
+			interpretation = fhir.Coding{System: fhir.SystemObservationInterpretation, Code: "N", Display: "Normal"}
+		}
+		observation.Interpretation = []fhir.CodeableConcept{{Coding: []fhir.Coding{interpretation}}}
+	}
+	if field.Converted() {
+		observation.Note = []fhir.Annotation{
+			{Text: fmt.Sprintf("Entered as %s %s", field.OriginalValue, field.OriginalUnit)},
+		}
+	}
+	if field.Computed {
+		observation.Note = append(observation.Note, fhir.Annotation{
+			Text: "Computed from the result's other fields",
+		})
+	}
+
+	return observation
+}
+
+// fhirMedicationStatement converts a prophylaxis, where the chosen prophylaxis
+// is active until it ends, and the other ones weren't taken.
+func fhirMedicationStatement(pp Prophylaxis, subject fhir.Reference, now time.Time) fhir.MedicationStatement {
+	status := "not-taken"
+	if pp.Chosen {
+		status = "active"
+		if !pp.EndDate.IsZero() && pp.EndDate.Before(now) {
+			status = "completed"
+		}
+	}
+
+	medication := pp.PrescribedMedicine.Name
+	if pp.PrescribedMedicine.Factor != "" {
+		medication += " (" + pp.PrescribedMedicine.Factor + ")"
+	}
+	if medication == "" {
+		medication = pp.Title
+	}
+
+	statement := fhir.MedicationStatement{
+		Id:     fmt.Sprintf("prophylaxis-%d", pp.Id),
+		Status: status,
+		MedicationCodeableConcept: fhir.CodeableConcept{
+			Text: medication,
+		},
+		Subject: subject,
+	}
+	if !pp.StartDate.IsZero() {
+		statement.EffectivePeriod = &fhir.Period{
+			Start: fhir.DateTime(pp.StartDate),
+			End:   fhir.DateTime(pp.EndDate),
+		}
+	}
+	if dosage := prophylaxisDosageText(pp); dosage != "" {
+		statement.Dosage = []fhir.Dosage{{Text: dosage}}
+	}
+
+	return statement
+}
+
+// prophylaxisDosageText formats the prophylaxis' dose and schedule, as in
+// "1000 IU on Monday, Thursday".
+func prophylaxisDosageText(pp Prophylaxis) string {
+	parts := make([]string, 0, 2)
+	if pp.DoseIu > 0 {
+		parts = append(parts, fmt.Sprintf("%d IU", pp.DoseIu))
+	}
+
+	switch models.ProphylaxisScheduleKind(pp.ScheduleKind) {
+	case models.ProphylaxisScheduleKindWeekdays:
+		if len(pp.Weekdays) > 0 {
+			weekdays := make([]string, 0, len(pp.Weekdays))
+			for _, day := range pp.Weekdays {
+				weekdays = append(weekdays, day.String())
+			}
+			parts = append(parts, "on "+strings.Join(weekdays, ", "))
+		}
+	case models.ProphylaxisScheduleKindEveryNDays:
+		if pp.IntervalDays > 0 {
+			parts = append(parts, fmt.Sprintf("every %d days", pp.IntervalDays))
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
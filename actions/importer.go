@@ -402,6 +402,12 @@ func (a *Actions) ImportPatientsFromCsv(params ImportPatientsFromCsvParams) (Imp
 		}
 
 		newPatients = append(newPatients, newPatient)
+
+		a.audit(params.ActionContext, auditEntry{
+			Action:          "ImportPatientsFromCsv",
+			Kind:            models.AuditEventKindWrite,
+			PatientPublicId: newPatient.PublicId,
+		})
 	}
 
 	diagnoses, err := a.app.ListAllDiagnoses()
@@ -422,150 +428,75 @@ func (a *Actions) ImportPatientsFromCsv(params ImportPatientsFromCsvParams) (Imp
 	if err != nil {
 		log.Warningln("No blood tests were found,", err)
 	}
+	// the fields are looked up by their LOINC codes, where the fields that
+	// aren't coded yet are looked up by their names.
+	aboField, hasAboField := findLoincField(bloodTests, models.LoincAboGroup, "ABO")
+	rhField, hasRhField := findLoincField(bloodTests, models.LoincRhType, "Rh(D)")
 	for key := range mPatientBloodGroup {
-		foundBtIdx := slices.IndexFunc(bloodTests, func(bt models.BloodTest) bool {
-			return bt.Name == "Blood Group"
-		})
-		if foundBtIdx > -1 {
-			mPatientBloodGroup[key].Id = bloodTests[foundBtIdx].Id
-		}
-		foundBtFieldAboIdx := slices.IndexFunc(bloodTests[foundBtIdx].Fields, func(btf models.BloodTestField) bool {
-			return btf.Name == "ABO"
-		})
-		if foundBtFieldAboIdx > -1 {
-			mPatientBloodGroup[key].ABOFieldId = bloodTests[foundBtIdx].Fields[foundBtFieldAboIdx].Id
+		if hasAboField {
+			mPatientBloodGroup[key].Id = aboField.BloodTestId
+			mPatientBloodGroup[key].ABOFieldId = aboField.Id
 		}
-		foundBtFieldRhIdx := slices.IndexFunc(bloodTests[foundBtIdx].Fields, func(btf models.BloodTestField) bool {
-			return btf.Name == "Rh(D)"
-		})
-		if foundBtFieldRhIdx > -1 {
-			mPatientBloodGroup[key].RhFieldId = bloodTests[foundBtIdx].Fields[foundBtFieldRhIdx].Id
+		if hasRhField {
+			mPatientBloodGroup[key].RhFieldId = rhField.Id
 		}
 	}
 
-	for key := range mPatientFactorVIII {
-		foundBtIdx := slices.IndexFunc(bloodTests, func(bt models.BloodTest) bool {
-			return bt.Name == "Factor - VIII"
-		})
-		if foundBtIdx > -1 {
-			mPatientFactorVIII[key].Id = bloodTests[foundBtIdx].Id
-		}
-		foundBtFieldAboIdx := slices.IndexFunc(bloodTests[foundBtIdx].Fields, func(btf models.BloodTestField) bool {
-			return btf.Name == "Factor - VIII"
-		})
-		if foundBtFieldAboIdx > -1 {
-			mPatientFactorVIII[key].FieldId = bloodTests[foundBtIdx].Fields[foundBtFieldAboIdx].Id
+	if field, ok := findLoincField(bloodTests, models.LoincFactorViiiActivity, "Factor - VIII"); ok {
+		for key := range mPatientFactorVIII {
+			mPatientFactorVIII[key].Id = field.BloodTestId
+			mPatientFactorVIII[key].FieldId = field.Id
 		}
 	}
 
-	for key := range mPatientFactorVII {
-		foundBtIdx := slices.IndexFunc(bloodTests, func(bt models.BloodTest) bool {
-			return bt.Name == "Factor - VII"
-		})
-		if foundBtIdx > -1 {
-			mPatientFactorVII[key].Id = bloodTests[foundBtIdx].Id
-		}
-		foundBtFieldAboIdx := slices.IndexFunc(bloodTests[foundBtIdx].Fields, func(btf models.BloodTestField) bool {
-			return btf.Name == "Factor - VII"
-		})
-		if foundBtFieldAboIdx > -1 {
-			mPatientFactorVII[key].FieldId = bloodTests[foundBtIdx].Fields[foundBtFieldAboIdx].Id
+	if field, ok := findLoincField(bloodTests, models.LoincFactorViiActivity, "Factor - VII"); ok {
+		for key := range mPatientFactorVII {
+			mPatientFactorVII[key].Id = field.BloodTestId
+			mPatientFactorVII[key].FieldId = field.Id
 		}
 	}
 
-	for key := range mPatientFactorV {
-		foundBtIdx := slices.IndexFunc(bloodTests, func(bt models.BloodTest) bool {
-			return bt.Name == "Factor - V"
-		})
-		if foundBtIdx > -1 {
-			mPatientFactorV[key].Id = bloodTests[foundBtIdx].Id
-		}
-		foundBtFieldAboIdx := slices.IndexFunc(bloodTests[foundBtIdx].Fields, func(btf models.BloodTestField) bool {
-			return btf.Name == "Factor - V"
-		})
-		if foundBtFieldAboIdx > -1 {
-			mPatientFactorV[key].FieldId = bloodTests[foundBtIdx].Fields[foundBtFieldAboIdx].Id
+	if field, ok := findLoincField(bloodTests, models.LoincFactorVActivity, "Factor - V"); ok {
+		for key := range mPatientFactorV {
+			mPatientFactorV[key].Id = field.BloodTestId
+			mPatientFactorV[key].FieldId = field.Id
 		}
 	}
 
-	for key := range mPatientFactorX {
-		foundBtIdx := slices.IndexFunc(bloodTests, func(bt models.BloodTest) bool {
-			return bt.Name == "Factor - X"
-		})
-		if foundBtIdx > -1 {
-			mPatientFactorX[key].Id = bloodTests[foundBtIdx].Id
-		}
-		foundBtFieldAboIdx := slices.IndexFunc(bloodTests[foundBtIdx].Fields, func(btf models.BloodTestField) bool {
-			return btf.Name == "Factor - X"
-		})
-		if foundBtFieldAboIdx > -1 {
-			mPatientFactorX[key].FieldId = bloodTests[foundBtIdx].Fields[foundBtFieldAboIdx].Id
+	if field, ok := findLoincField(bloodTests, models.LoincFactorXActivity, "Factor - X"); ok {
+		for key := range mPatientFactorX {
+			mPatientFactorX[key].Id = field.BloodTestId
+			mPatientFactorX[key].FieldId = field.Id
 		}
 	}
 
-	for key := range mPatientFactorIX {
-		foundBtIdx := slices.IndexFunc(bloodTests, func(bt models.BloodTest) bool {
-			return bt.Name == "Factor - IX"
-		})
-		if foundBtIdx > -1 {
-			mPatientFactorIX[key].Id = bloodTests[foundBtIdx].Id
-		}
-		foundBtFieldAboIdx := slices.IndexFunc(bloodTests[foundBtIdx].Fields, func(btf models.BloodTestField) bool {
-			return btf.Name == "Factor - IX"
-		})
-		if foundBtFieldAboIdx > -1 {
-			mPatientFactorIX[key].FieldId = bloodTests[foundBtIdx].Fields[foundBtFieldAboIdx].Id
+	if field, ok := findLoincField(bloodTests, models.LoincFactorIxActivity, "Factor - IX"); ok {
+		for key := range mPatientFactorIX {
+			mPatientFactorIX[key].Id = field.BloodTestId
+			mPatientFactorIX[key].FieldId = field.Id
 		}
 	}
 
-	for key := range mPatientVWFAg {
-		foundBtIdx := slices.IndexFunc(bloodTests, func(bt models.BloodTest) bool {
-			return bt.Name == "VWF:Ag"
-		})
-		if foundBtIdx > -1 {
-			mPatientVWFAg[key].Id = bloodTests[foundBtIdx].Id
-		}
-		foundBtFieldAboIdx := slices.IndexFunc(bloodTests[foundBtIdx].Fields, func(btf models.BloodTestField) bool {
-			return btf.Name == "VWF:Ag"
-		})
-		if foundBtFieldAboIdx > -1 {
-			mPatientVWFAg[key].FieldId = bloodTests[foundBtIdx].Fields[foundBtFieldAboIdx].Id
+	if field, ok := findLoincField(bloodTests, models.LoincVwfAntigen, "VWF:Ag"); ok {
+		for key := range mPatientVWFAg {
+			mPatientVWFAg[key].Id = field.BloodTestId
+			mPatientVWFAg[key].FieldId = field.Id
 		}
 	}
 
-	for key := range mPatientFibrinogen {
-		foundBtIdx := slices.IndexFunc(bloodTests, func(bt models.BloodTest) bool {
-			return bt.Name == "Fibrinogen"
-		})
-		if foundBtIdx > -1 {
-			mPatientFibrinogen[key].Id = bloodTests[foundBtIdx].Id
-		}
-		foundBtFieldAboIdx := slices.IndexFunc(bloodTests[foundBtIdx].Fields, func(btf models.BloodTestField) bool {
-			return btf.Name == "Fibrinogen"
-		})
-		if foundBtFieldAboIdx > -1 {
-			mPatientFibrinogen[key].FieldId = bloodTests[foundBtIdx].Fields[foundBtFieldAboIdx].Id
+	if field, ok := findLoincField(bloodTests, models.LoincFibrinogen, "Fibrinogen"); ok {
+		for key := range mPatientFibrinogen {
+			mPatientFibrinogen[key].Id = field.BloodTestId
+			mPatientFibrinogen[key].FieldId = field.Id
 		}
 	}
 
-	for key := range mPatientInhibitors {
-		foundBtIdx := slices.IndexFunc(bloodTests, func(bt models.BloodTest) bool {
-			return bt.Name == "Inhibitors"
-		})
-		if foundBtIdx > -1 {
-			mPatientInhibitors[key].Id = bloodTests[foundBtIdx].Id
-		}
-		foundBtFieldAboIdx := slices.IndexFunc(bloodTests[foundBtIdx].Fields, func(btf models.BloodTestField) bool {
-			return btf.Name == "Inhibitor Screening"
-		})
-		if foundBtFieldAboIdx > -1 {
-			mPatientInhibitors[key].FieldId = bloodTests[foundBtIdx].Fields[foundBtFieldAboIdx].Id
-		}
-		foundBtField2AboIdx := slices.IndexFunc(bloodTests[foundBtIdx].Fields, func(btf models.BloodTestField) bool {
-			return btf.Name == "Inhibitor Titrage"
-		})
-		if foundBtField2AboIdx > -1 {
-			mPatientInhibitors[key].Field2Id = bloodTests[foundBtIdx].Fields[foundBtField2AboIdx].Id
+	titerField, screeningField, ok := findInhibitorFields(bloodTests)
+	if ok {
+		for key := range mPatientInhibitors {
+			mPatientInhibitors[key].Id = titerField.BloodTestId
+			mPatientInhibitors[key].FieldId = screeningField.Id
+			mPatientInhibitors[key].Field2Id = titerField.Id
 		}
 	}
 
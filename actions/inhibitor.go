@@ -0,0 +1,525 @@
+package actions
+
+import (
+	"errors"
+	"shs/app"
+	"shs/app/models"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Names of the inhibitors blood test's fields, where the screening is a
+// positive/negative value, and the titrage is the titer in Bethesda units.
+//
+// The fields are looked up by their LOINC codes, and by their names when they
+// aren't coded yet, where the screening is only used when it's in the
+// titrage's blood test.
+const (
+	inhibitorScreeningFieldName = "Inhibitor Screening"
+	inhibitorTitrageFieldName   = "Inhibitor Titrage"
+)
+
+const (
+	// inhibitorPositiveTiterBu is the lowest titer considered as an inhibitor.
+	inhibitorPositiveTiterBu = 0.6
+	// inhibitorHighResponderTiterBu is the peak titer that makes the patient a
+	// high responder.
+	inhibitorHighResponderTiterBu = 5
+	// inhibitorSurveillanceEds is the number of exposure days where most
+	// inhibitors develop, and the patient is screened regularly.
+	inhibitorSurveillanceEds             = 50
+	defaultInhibitorScreeningIntervalEds = 5
+)
+
+const (
+	InhibitorResponderNone = "none"
+	InhibitorResponderLow  = "low"
+	InhibitorResponderHigh = "high"
+)
+
+type InhibitorTest struct {
+	BloodTestResultId uint      `json:"blood_test_result_id"`
+	TestedAt          time.Time `json:"tested_at"`
+	Screening         string    `json:"screening"`
+	TiterBu           float64   `json:"titer_bu"`
+	HasTiter          bool      `json:"has_titer"`
+	Positive          bool      `json:"positive"`
+	// ExposureDays is the number of exposure days up to the test.
+	ExposureDays int `json:"exposure_days"`
+}
+
+type InhibitorSurveillance struct {
+	Id                   uint      `json:"id"`
+	ScreeningIntervalEds int       `json:"screening_interval_eds"`
+	ItiStatus            string    `json:"iti_status"`
+	ItiStartedAt         time.Time `json:"iti_started_at"`
+	ItiEndedAt           time.Time `json:"iti_ended_at"`
+	Notes                string    `json:"notes"`
+
+	Tests           []InhibitorTest `json:"tests"`
+	PeakTiterBu     float64         `json:"peak_titer_bu"`
+	Responder       string          `json:"responder"`
+	ExposureDays    int             `json:"exposure_days"`
+	FirstExposureAt time.Time       `json:"first_exposure_at"`
+	// NextScreeningAtEds is zero when the patient is past the first 50
+	// exposure days, or when an inhibitor was already detected.
+	NextScreeningAtEds int  `json:"next_screening_at_eds"`
+	ScreeningDue       bool `json:"screening_due"`
+}
+
+func (is *InhibitorSurveillance) FromModel(surveillance models.InhibitorSurveillance) {
+	(*is) = InhibitorSurveillance{
+		Id:                   surveillance.Id,
+		ScreeningIntervalEds: surveillance.ScreeningIntervalEds,
+		ItiStatus:            string(surveillance.ItiStatus),
+		ItiStartedAt:         surveillance.ItiStartedAt,
+		ItiEndedAt:           surveillance.ItiEndedAt,
+		Notes:                surveillance.Notes,
+	}
+}
+
+func (is InhibitorSurveillance) IntoModel() models.InhibitorSurveillance {
+	return models.InhibitorSurveillance{
+		Id:                   is.Id,
+		ScreeningIntervalEds: is.ScreeningIntervalEds,
+		ItiStatus:            models.ItiStatus(is.ItiStatus),
+		ItiStartedAt:         is.ItiStartedAt,
+		ItiEndedAt:           is.ItiEndedAt,
+		Notes:                is.Notes,
+	}
+}
+
+func (is InhibitorSurveillance) Validate() error {
+	if is.ScreeningIntervalEds < 1 || is.ScreeningIntervalEds > inhibitorSurveillanceEds {
+		return ErrValidation{Field: "screening_interval_eds"}
+	}
+
+	switch models.ItiStatus(is.ItiStatus) {
+	case models.ItiStatusNone, models.ItiStatusPlanned, models.ItiStatusOngoing,
+		models.ItiStatusSuccess, models.ItiStatusPartialSuccess, models.ItiStatusFailure:
+	default:
+		return ErrValidation{Field: "iti_status"}
+	}
+
+	if !is.ItiStartedAt.IsZero() && !is.ItiEndedAt.IsZero() && is.ItiEndedAt.Before(is.ItiStartedAt) {
+		return ErrValidation{Field: "iti_ended_at"}
+	}
+
+	return nil
+}
+
+func defaultInhibitorSurveillance() models.InhibitorSurveillance {
+	return models.InhibitorSurveillance{
+		ScreeningIntervalEds: defaultInhibitorScreeningIntervalEds,
+		ItiStatus:            models.ItiStatusNone,
+	}
+}
+
+// parseBethesdaTiter parses a titer as written by the lab, e.g. "1.2", "1.2 BU"
+// or "<0.6 BU/ml", where a titer below the detection limit is parsed as 0.
+func parseBethesdaTiter(value string) (float64, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	value = strings.TrimSuffix(value, "/ml")
+	value = strings.TrimSpace(strings.TrimSuffix(value, "bu"))
+	if strings.HasPrefix(value, "<") {
+		_, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimPrefix(value, "<")), 64)
+		return 0, err == nil
+	}
+
+	titer, err := strconv.ParseFloat(value, 64)
+	if err != nil || titer < 0 {
+		return 0, false
+	}
+
+	return titer, true
+}
+
+// parseInhibitorScreening parses a screening value, and reports false when
+// the value is neither positive nor negative.
+func parseInhibitorScreening(value string) (positive bool, ok bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch {
+	case value == "":
+		return false, false
+	case strings.HasPrefix(value, "pos"), value == "+", strings.Contains(value, "ايجاب"), strings.Contains(value, "إيجاب"):
+		return true, true
+	case strings.HasPrefix(value, "neg"), value == "-", strings.Contains(value, "سلب"):
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// findInhibitorFields finds the inhibitors blood test's titrage and screening
+// fields, where the screening field is zero when the blood test doesn't have it.
+func findInhibitorFields(bloodTests []models.BloodTest) (titer, screening models.BloodTestField, ok bool) {
+	titer, ok = findLoincField(bloodTests, models.LoincFactorViiiInhibitorTiter, inhibitorTitrageFieldName)
+	if !ok {
+		return models.BloodTestField{}, models.BloodTestField{}, false
+	}
+
+	screening, ok = findLoincField(bloodTests, models.LoincFactorViiiInhibitor, inhibitorScreeningFieldName)
+	if !ok || screening.BloodTestId != titer.BloodTestId {
+		screening = models.BloodTestField{}
+	}
+
+	return titer, screening, true
+}
+
+// inhibitorTestsFromResults parses the patient's inhibitors blood test results
+// ordered by when they were tested.
+func inhibitorTestsFromResults(results []models.BloodTestResult, bloodTests []models.BloodTest) []InhibitorTest {
+	titerField, screeningField, ok := findInhibitorFields(bloodTests)
+	if !ok {
+		return []InhibitorTest{}
+	}
+
+	tests := make([]InhibitorTest, 0)
+	for _, btr := range results {
+		if btr.BloodTestId != titerField.BloodTestId || btr.Pending {
+			continue
+		}
+
+		test := InhibitorTest{
+			BloodTestResultId: btr.Id,
+			TestedAt:          btr.TestedAt,
+		}
+		for _, field := range btr.FilledFields {
+			switch field.BloodTestFieldId {
+			case screeningField.Id:
+				test.Screening = strings.TrimSpace(field.ValueString)
+				if positive, ok := parseInhibitorScreening(field.ValueString); ok {
+					test.Positive = test.Positive || positive
+				}
+			case titerField.Id:
+				titer, ok := parseBethesdaTiter(field.ValueString)
+				if !ok && field.ValueNumber > 0 {
+					titer, ok = field.ValueNumber, true
+				}
+				if ok {
+					test.TiterBu = titer
+					test.HasTiter = true
+					test.Positive = test.Positive || titer >= inhibitorPositiveTiterBu
+				}
+			}
+		}
+
+		tests = append(tests, test)
+	}
+
+	slices.SortFunc(tests, func(a, b InhibitorTest) int {
+		return a.TestedAt.Compare(b.TestedAt)
+	})
+
+	return tests
+}
+
+// factorExposureDays lists the days where the patient used a factor VIII or
+// factor IX medicine, ordered and without duplicates.
+func factorExposureDays(usedMeds []models.PrescribedMedicine, medicines map[uint]models.Medicine) []time.Time {
+	days := make([]time.Time, 0)
+	for _, pm := range usedMeds {
+		if pm.UsedAt.IsZero() || medicineFactorType(medicines[pm.MedicineId].Factor) == "" {
+			continue
+		}
+		days = append(days, truncateToClinicDay(pm.UsedAt))
+	}
+
+	slices.SortFunc(days, func(a, b time.Time) int {
+		return a.Compare(b)
+	})
+
+	return slices.CompactFunc(days, func(a, b time.Time) bool {
+		return a.Equal(b)
+	})
+}
+
+// computeInhibitorSurveillance counts the exposure days and classifies the
+// patient's response, and finds when the next screening in the first 50
+// exposure days is due, where it's due every ScreeningIntervalEds exposure
+// days after the last screening.
+func computeInhibitorSurveillance(surveillance models.InhibitorSurveillance, tests []InhibitorTest, exposureDays []time.Time) InhibitorSurveillance {
+	is := new(InhibitorSurveillance)
+	is.FromModel(surveillance)
+	is.Tests = tests
+	is.Responder = InhibitorResponderNone
+	is.ExposureDays = len(exposureDays)
+	if len(exposureDays) > 0 {
+		is.FirstExposureAt = exposureDays[0]
+	}
+
+	for i, test := range is.Tests {
+		is.Tests[i].ExposureDays = len(slices.DeleteFunc(slices.Clone(exposureDays), func(day time.Time) bool {
+			return day.After(test.TestedAt)
+		}))
+		is.PeakTiterBu = max(is.PeakTiterBu, test.TiterBu)
+		if test.Positive && is.Responder == InhibitorResponderNone {
+			is.Responder = InhibitorResponderLow
+		}
+	}
+	if is.PeakTiterBu >= inhibitorHighResponderTiterBu {
+		is.Responder = InhibitorResponderHigh
+	}
+
+	// a patient with an inhibitor is followed by the titers instead.
+	if is.Responder != InhibitorResponderNone || is.ScreeningIntervalEds <= 0 {
+		return *is
+	}
+
+	lastScreeningEds := 0
+	if len(is.Tests) > 0 {
+		lastScreeningEds = is.Tests[len(is.Tests)-1].ExposureDays
+	}
+	nextScreeningEds := (lastScreeningEds/is.ScreeningIntervalEds + 1) * is.ScreeningIntervalEds
+	if nextScreeningEds > inhibitorSurveillanceEds {
+		return *is
+	}
+
+	is.NextScreeningAtEds = nextScreeningEds
+	is.ScreeningDue = is.ExposureDays >= nextScreeningEds
+
+	return *is
+}
+
+func (a *Actions) getInhibitorSurveillance(patientId uint, bloodTests []models.BloodTest, now time.Time) (InhibitorSurveillance, error) {
+	surveillance, err := a.app.GetInhibitorSurveillanceForPatient(patientId)
+	var notFoundErr *app.ErrNotFound
+	if errors.As(err, &notFoundErr) {
+		surveillance = defaultInhibitorSurveillance()
+	} else if err != nil {
+		return InhibitorSurveillance{}, err
+	}
+
+	results, err := a.app.ListPatientBloodTestResults(patientId)
+	if err != nil {
+		return InhibitorSurveillance{}, err
+	}
+
+	usedMeds, err := a.app.ListPatientUsedPrescribedMedicinesOnTimeRange(patientId, time.Time{}, now)
+	if err != nil {
+		return InhibitorSurveillance{}, err
+	}
+
+	medsIds := make([]uint, 0, len(usedMeds))
+	for _, pm := range usedMeds {
+		medsIds = append(medsIds, pm.MedicineId)
+	}
+
+	meds, err := a.app.ListMedicinesByIds(medsIds)
+	if err != nil {
+		return InhibitorSurveillance{}, err
+	}
+
+	medsMapped := make(map[uint]models.Medicine)
+	for _, med := range meds {
+		medsMapped[med.Id] = med
+	}
+
+	return computeInhibitorSurveillance(
+		surveillance,
+		inhibitorTestsFromResults(results, bloodTests),
+		factorExposureDays(usedMeds, medsMapped),
+	), nil
+}
+
+type GetPatientInhibitorSurveillanceParams struct {
+	ActionContext
+	PatientId string
+}
+
+type GetPatientInhibitorSurveillancePayload struct {
+	Data InhibitorSurveillance `json:"data"`
+}
+
+func (a *Actions) GetPatientInhibitorSurveillance(params GetPatientInhibitorSurveillanceParams) (GetPatientInhibitorSurveillancePayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadPatient) {
+		return GetPatientInhibitorSurveillancePayload{}, ErrPermissionDenied{}
+	}
+	if !params.Account.HasPermission(models.AccountPermissionReadBloodTest) {
+		return GetPatientInhibitorSurveillancePayload{}, ErrPermissionDenied{}
+	}
+
+	patient, err := a.app.GetPatientByPublicId(params.PatientId)
+	if err != nil {
+		return GetPatientInhibitorSurveillancePayload{}, err
+	}
+
+	bloodTests, err := a.app.ListAllBloodTests()
+	if err != nil {
+		return GetPatientInhibitorSurveillancePayload{}, err
+	}
+
+	surveillance, err := a.getInhibitorSurveillance(patient.Id, bloodTests, time.Now().UTC())
+	if err != nil {
+		return GetPatientInhibitorSurveillancePayload{}, err
+	}
+
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "GetPatientInhibitorSurveillance",
+		Kind:            models.AuditEventKindRead,
+		PatientPublicId: params.PatientId,
+	})
+
+	return GetPatientInhibitorSurveillancePayload{
+		Data: surveillance,
+	}, nil
+}
+
+type UpdatePatientInhibitorSurveillanceParams struct {
+	ActionContext
+	PatientId    string
+	Surveillance InhibitorSurveillance `json:"inhibitor_surveillance"`
+}
+
+type UpdatePatientInhibitorSurveillancePayload struct {
+}
+
+// UpdatePatientInhibitorSurveillance sets the patient's screening interval and
+// ITI status, where the patient's surveillance is created on the first update.
+func (a *Actions) UpdatePatientInhibitorSurveillance(params UpdatePatientInhibitorSurveillanceParams) (UpdatePatientInhibitorSurveillancePayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionWritePatient) {
+		return UpdatePatientInhibitorSurveillancePayload{}, ErrPermissionDenied{}
+	}
+
+	err := params.Surveillance.Validate()
+	if err != nil {
+		return UpdatePatientInhibitorSurveillancePayload{}, err
+	}
+
+	patient, err := a.app.GetPatientByPublicId(params.PatientId)
+	if err != nil {
+		return UpdatePatientInhibitorSurveillancePayload{}, err
+	}
+
+	surveillance := params.Surveillance.IntoModel()
+	surveillance.PatientId = patient.Id
+
+	existing, err := a.app.GetInhibitorSurveillanceForPatient(patient.Id)
+	var notFoundErr *app.ErrNotFound
+	if errors.As(err, &notFoundErr) {
+		_, err = a.app.CreateInhibitorSurveillance(surveillance)
+		if err != nil {
+			return UpdatePatientInhibitorSurveillancePayload{}, err
+		}
+
+		a.audit(params.ActionContext, auditEntry{
+			Action:          "UpdatePatientInhibitorSurveillance",
+			Kind:            models.AuditEventKindWrite,
+			PatientPublicId: params.PatientId,
+			After:           params.Surveillance,
+		})
+
+		return UpdatePatientInhibitorSurveillancePayload{}, nil
+	}
+	if err != nil {
+		return UpdatePatientInhibitorSurveillancePayload{}, err
+	}
+
+	err = a.app.UpdateInhibitorSurveillance(existing.Id, surveillance)
+	if err != nil {
+		return UpdatePatientInhibitorSurveillancePayload{}, err
+	}
+
+	before := new(InhibitorSurveillance)
+	before.FromModel(existing)
+	after := new(InhibitorSurveillance)
+	after.FromModel(surveillance)
+
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "UpdatePatientInhibitorSurveillance",
+		Kind:            models.AuditEventKindWrite,
+		PatientPublicId: params.PatientId,
+		Before:          before,
+		After:           after,
+	})
+
+	return UpdatePatientInhibitorSurveillancePayload{}, nil
+}
+
+type PatientInhibitorSurveillance struct {
+	Patient      Patient               `json:"patient"`
+	Surveillance InhibitorSurveillance `json:"inhibitor_surveillance"`
+}
+
+type ListInhibitorScreeningsDueParams struct {
+	ActionContext
+}
+
+type ListInhibitorScreeningsDuePayload struct {
+	Data []PatientInhibitorSurveillance `json:"data"`
+}
+
+// ListInhibitorScreeningsDue lists the patients that used factor medicines and
+// are due for an inhibitor screening.
+func (a *Actions) ListInhibitorScreeningsDue(params ListInhibitorScreeningsDueParams) (ListInhibitorScreeningsDuePayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadPatient) {
+		return ListInhibitorScreeningsDuePayload{}, ErrPermissionDenied{}
+	}
+	if !params.Account.HasPermission(models.AccountPermissionReadBloodTest) {
+		return ListInhibitorScreeningsDuePayload{}, ErrPermissionDenied{}
+	}
+
+	prescribedMeds, err := a.app.ListAllPrescribedMedicines()
+	if err != nil {
+		return ListInhibitorScreeningsDuePayload{}, err
+	}
+
+	patientsIds := make([]uint, 0)
+	seenPatientsIds := make(map[uint]struct{})
+	for _, pm := range prescribedMeds {
+		if pm.UsedAt.IsZero() {
+			continue
+		}
+		if _, ok := seenPatientsIds[pm.PatientId]; ok {
+			continue
+		}
+		seenPatientsIds[pm.PatientId] = struct{}{}
+		patientsIds = append(patientsIds, pm.PatientId)
+	}
+
+	bloodTests, err := a.app.ListAllBloodTests()
+	if err != nil {
+		return ListInhibitorScreeningsDuePayload{}, err
+	}
+
+	now := time.Now().UTC()
+	outPatients := make([]PatientInhibitorSurveillance, 0)
+	for _, patientId := range patientsIds {
+		surveillance, err := a.getInhibitorSurveillance(patientId, bloodTests, now)
+		if err != nil {
+			return ListInhibitorScreeningsDuePayload{}, err
+		}
+		if !surveillance.ScreeningDue {
+			continue
+		}
+
+		// the prescribed medicines that were left of a deleted patient are
+		// skipped, so that they don't fail the other patients' list.
+		patient, err := a.app.GetPatientById(patientId)
+		var notFoundErr *app.ErrNotFound
+		if errors.As(err, &notFoundErr) {
+			continue
+		}
+		if err != nil {
+			return ListInhibitorScreeningsDuePayload{}, err
+		}
+
+		outPatient := new(Patient)
+		outPatient.FromModel(patient)
+		outPatients = append(outPatients, PatientInhibitorSurveillance{
+			Patient:      *outPatient,
+			Surveillance: surveillance,
+		})
+	}
+
+	a.audit(params.ActionContext, auditEntry{
+		Action: "ListInhibitorScreeningsDue",
+		Kind:   models.AuditEventKindRead,
+	})
+
+	return ListInhibitorScreeningsDuePayload{
+		Data: outPatients,
+	}, nil
+}
@@ -2,19 +2,109 @@ package actions
 
 import (
 	"shs/app/models"
+	"slices"
 	"time"
 )
 
+// HjhsItem is a per joint item of the HJHS 2.1 score sheet, scored from 0 to Max.
+type HjhsItem struct {
+	Key string
+	Max int
+}
+
+// HjhsJointItems are ordered as HjhsJointScore.Items.
+var HjhsJointItems = []HjhsItem{
+	{Key: "swelling", Max: 3},
+	{Key: "swelling_duration", Max: 1},
+	{Key: "muscle_atrophy", Max: 2},
+	{Key: "crepitus", Max: 2},
+	{Key: "flexion_loss", Max: 3},
+	{Key: "extension_loss", Max: 3},
+	{Key: "joint_pain", Max: 2},
+	{Key: "strength", Max: 4},
+}
+
+const HjhsGlobalGaitMax = 4
+
+type HjhsJointScore struct {
+	Joint            string `json:"joint"`
+	Swelling         int    `json:"swelling"`
+	SwellingDuration int    `json:"swelling_duration"`
+	MuscleAtrophy    int    `json:"muscle_atrophy"`
+	Crepitus         int    `json:"crepitus"`
+	FlexionLoss      int    `json:"flexion_loss"`
+	ExtensionLoss    int    `json:"extension_loss"`
+	JointPain        int    `json:"joint_pain"`
+	Strength         int    `json:"strength"`
+	Total            int    `json:"total"`
+}
+
+// Items returns the score's items ordered as HjhsJointItems.
+func (s *HjhsJointScore) Items() []*int {
+	return []*int{
+		&s.Swelling,
+		&s.SwellingDuration,
+		&s.MuscleAtrophy,
+		&s.Crepitus,
+		&s.FlexionLoss,
+		&s.ExtensionLoss,
+		&s.JointPain,
+		&s.Strength,
+	}
+}
+
+func (s *HjhsJointScore) FromModel(score models.HjhsJointScore) {
+	(*s) = HjhsJointScore{
+		Joint:            string(score.Joint),
+		Swelling:         score.Swelling,
+		SwellingDuration: score.SwellingDuration,
+		MuscleAtrophy:    score.MuscleAtrophy,
+		Crepitus:         score.Crepitus,
+		FlexionLoss:      score.FlexionLoss,
+		ExtensionLoss:    score.ExtensionLoss,
+		JointPain:        score.JointPain,
+		Strength:         score.Strength,
+		Total:            score.Total(),
+	}
+}
+
+func (s HjhsJointScore) IntoModel() models.HjhsJointScore {
+	return models.HjhsJointScore{
+		Joint:            models.Joint(s.Joint),
+		Swelling:         s.Swelling,
+		SwellingDuration: s.SwellingDuration,
+		MuscleAtrophy:    s.MuscleAtrophy,
+		Crepitus:         s.Crepitus,
+		FlexionLoss:      s.FlexionLoss,
+		ExtensionLoss:    s.ExtensionLoss,
+		JointPain:        s.JointPain,
+		Strength:         s.Strength,
+	}
+}
+
+func (s HjhsJointScore) Validate() error {
+	for i, item := range s.Items() {
+		if *item < 0 || *item > HjhsJointItems[i].Max {
+			return ErrValidation{Field: s.Joint + "_" + HjhsJointItems[i].Key}
+		}
+	}
+
+	return nil
+}
+
 type JointsEvaluation struct {
-	Id         uint      `json:"id"`
-	RightAnkle int       `json:"right_ankle"`
-	LeftAnkle  int       `json:"left_ankle"`
-	RightKnee  int       `json:"right_knee"`
-	LeftKnee   int       `json:"left_knee"`
-	RightElbow int       `json:"right_elbow"`
-	LeftElbow  int       `json:"left_elbow"`
-	Result     int       `json:"result"`
-	CreatedAt  time.Time `json:"created_at"`
+	Id         uint             `json:"id"`
+	RightAnkle int              `json:"right_ankle"`
+	LeftAnkle  int              `json:"left_ankle"`
+	RightKnee  int              `json:"right_knee"`
+	LeftKnee   int              `json:"left_knee"`
+	RightElbow int              `json:"right_elbow"`
+	LeftElbow  int              `json:"left_elbow"`
+	GlobalGait int              `json:"global_gait"`
+	Scores     []HjhsJointScore `json:"scores"`
+	// Result is the HJHS total, from 0 to 124.
+	Result    int       `json:"result"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 func (j *JointsEvaluation) FromModel(je models.JointsEvaluation) {
@@ -25,22 +115,69 @@ func (j *JointsEvaluation) FromModel(je models.JointsEvaluation) {
 	(*j).LeftKnee = je.LeftKnee
 	(*j).RightElbow = je.RightElbow
 	(*j).LeftElbow = je.LeftElbow
+	(*j).GlobalGait = je.GlobalGait
 	(*j).CreatedAt = je.CreatedAt
+	(*j).Scores = make([]HjhsJointScore, 0, len(je.Scores))
+	for _, score := range je.Scores {
+		outScore := new(HjhsJointScore)
+		outScore.FromModel(score)
+		(*j).Scores = append((*j).Scores, *outScore)
+	}
+
+	(*j).Result = je.Total()
+}
 
-	(*j).Result = je.RightAnkle + je.LeftAnkle +
-		je.RightKnee + je.LeftKnee +
-		je.RightElbow + je.LeftElbow
+// JointTotal returns the joint's total score, where joint is one of models.HjhsJoints.
+func (j JointsEvaluation) JointTotal(joint string) int {
+	return j.IntoModel().JointTotal(models.Joint(joint))
 }
 
+// IntoModel sets the joints' totals from the item scores, and ignores the
+// totals that are set on the evaluation.
 func (j JointsEvaluation) IntoModel() models.JointsEvaluation {
-	return models.JointsEvaluation{
+	je := models.JointsEvaluation{
 		RightAnkle: j.RightAnkle,
 		LeftAnkle:  j.LeftAnkle,
 		RightKnee:  j.RightKnee,
 		LeftKnee:   j.LeftKnee,
 		RightElbow: j.RightElbow,
 		LeftElbow:  j.LeftElbow,
+		GlobalGait: j.GlobalGait,
+		Scores:     make([]models.HjhsJointScore, 0, len(j.Scores)),
+	}
+	for _, score := range j.Scores {
+		je.Scores = append(je.Scores, score.IntoModel())
+	}
+	je.SetJointsTotals()
+
+	return je
+}
+
+// Validate checks that every HJHS joint is scored exactly once, and that
+// every item is in its range.
+func (j JointsEvaluation) Validate() error {
+	if len(j.Scores) != len(models.HjhsJoints) {
+		return ErrValidation{Field: "scores"}
+	}
+
+	scoredJoints := make(map[string]bool)
+	for _, score := range j.Scores {
+		if !slices.Contains(models.HjhsJoints, models.Joint(score.Joint)) || scoredJoints[score.Joint] {
+			return ErrValidation{Field: "scores"}
+		}
+		scoredJoints[score.Joint] = true
+
+		err := score.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	if j.GlobalGait < 0 || j.GlobalGait > HjhsGlobalGaitMax {
+		return ErrValidation{Field: "global_gait"}
 	}
+
+	return nil
 }
 
 type CreatePatientJointsEvaluationParams struct {
@@ -62,14 +199,26 @@ func (a *Actions) CreatePatientJointsEvaluation(params CreatePatientJointsEvalua
 		return CreatePatientJointsEvaluationPayload{}, err
 	}
 
+	err = params.JointsEvaluation.Validate()
+	if err != nil {
+		return CreatePatientJointsEvaluationPayload{}, err
+	}
+
 	je := params.JointsEvaluation.IntoModel()
 	je.PatientId = patient.Id
 
-	_, err = a.app.CreateJointsEvaluation(je)
+	je, err = a.app.CreateJointsEvaluation(je)
 	if err != nil {
 		return CreatePatientJointsEvaluationPayload{}, err
 	}
 
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "CreatePatientJointsEvaluation",
+		Kind:            models.AuditEventKindWrite,
+		PatientPublicId: params.PatientId,
+		ResourceIds:     map[string]uint{"joints_evaluation_id": je.Id},
+	})
+
 	return CreatePatientJointsEvaluationPayload{}, nil
 }
 
@@ -104,6 +253,12 @@ func (a *Actions) ListPatientJointsEvaluations(params ListPatientJointsEvaluatio
 		outJoints = append(outJoints, *outJoint)
 	}
 
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "ListPatientJointsEvaluations",
+		Kind:            models.AuditEventKindRead,
+		PatientPublicId: params.PatientId,
+	})
+
 	return ListPatientJointsEvaluationsPayload{
 		Data: outJoints,
 	}, nil
@@ -12,6 +12,8 @@ type Subject = string
 const (
 	// JwtSessionToken used to verify that the user is logged in correctly and can access the good stuff.
 	JwtSessionToken Subject = "SESSION_TOKEN"
+	// JwtCalendarFeedToken used to access a patient's calendar feed from calendar apps that can't log in.
+	JwtCalendarFeedToken Subject = "CALENDAR_FEED_TOKEN"
 )
 
 // JwtClaims is iondsa, it's just JWT claims blyat!
@@ -0,0 +1,526 @@
+package actions
+
+import (
+	"errors"
+	"shs/app"
+	"shs/app/models"
+	"shs/hl7"
+	"shs/unitconv"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type LabCodeMapping struct {
+	Id uint `json:"id"`
+	// CodingSystem is the code's coding system, e.g. LN for LOINC, or the lab's
+	// local one, where an empty one matches the code in any coding system.
+	CodingSystem     string `json:"coding_system"`
+	Code             string `json:"code"`
+	BloodTestFieldId uint   `json:"blood_test_field_id"`
+}
+
+func (m *LabCodeMapping) FromModel(mapping models.LabCodeMapping) {
+	(*m) = LabCodeMapping{
+		Id:               mapping.Id,
+		CodingSystem:     mapping.CodingSystem,
+		Code:             mapping.Code,
+		BloodTestFieldId: mapping.BloodTestFieldId,
+	}
+}
+
+func (m LabCodeMapping) IntoModel() models.LabCodeMapping {
+	return models.LabCodeMapping{
+		CodingSystem:     strings.TrimSpace(m.CodingSystem),
+		Code:             strings.TrimSpace(m.Code),
+		BloodTestFieldId: m.BloodTestFieldId,
+	}
+}
+
+type CreateLabCodeMappingParams struct {
+	ActionContext
+	LabCodeMapping LabCodeMapping `json:"lab_code_mapping"`
+}
+
+type CreateLabCodeMappingPayload struct {
+	Data LabCodeMapping `json:"data"`
+}
+
+func (a *Actions) CreateLabCodeMapping(params CreateLabCodeMappingParams) (CreateLabCodeMappingPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionWriteBloodTest) {
+		return CreateLabCodeMappingPayload{}, ErrPermissionDenied{}
+	}
+
+	if strings.TrimSpace(params.LabCodeMapping.Code) == "" {
+		return CreateLabCodeMappingPayload{}, ErrValidation{Field: "code"}
+	}
+
+	bloodTests, err := a.app.ListAllBloodTests()
+	if err != nil {
+		return CreateLabCodeMappingPayload{}, err
+	}
+	if _, ok := newBloodTestsIndex(bloodTests).fields[params.LabCodeMapping.BloodTestFieldId]; !ok {
+		return CreateLabCodeMappingPayload{}, ErrValidation{Field: "blood_test_field_id"}
+	}
+
+	mapping, err := a.app.CreateLabCodeMapping(params.LabCodeMapping.IntoModel())
+	if err != nil {
+		return CreateLabCodeMappingPayload{}, err
+	}
+
+	outMapping := new(LabCodeMapping)
+	outMapping.FromModel(mapping)
+
+	return CreateLabCodeMappingPayload{
+		Data: *outMapping,
+	}, nil
+}
+
+type ListLabCodeMappingsParams struct {
+	ActionContext
+}
+
+type ListLabCodeMappingsPayload struct {
+	Data []LabCodeMapping `json:"data"`
+}
+
+func (a *Actions) ListLabCodeMappings(params ListLabCodeMappingsParams) (ListLabCodeMappingsPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadBloodTest) {
+		return ListLabCodeMappingsPayload{}, ErrPermissionDenied{}
+	}
+
+	mappings, err := a.app.ListLabCodeMappings()
+	if err != nil {
+		return ListLabCodeMappingsPayload{}, err
+	}
+
+	outMappings := make([]LabCodeMapping, 0, len(mappings))
+	for _, mapping := range mappings {
+		outMapping := new(LabCodeMapping)
+		outMapping.FromModel(mapping)
+		outMappings = append(outMappings, *outMapping)
+	}
+
+	return ListLabCodeMappingsPayload{
+		Data: outMappings,
+	}, nil
+}
+
+type DeleteLabCodeMappingParams struct {
+	ActionContext
+	LabCodeMappingId uint `json:"lab_code_mapping_id"`
+}
+
+type DeleteLabCodeMappingPayload struct {
+}
+
+func (a *Actions) DeleteLabCodeMapping(params DeleteLabCodeMappingParams) (DeleteLabCodeMappingPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionWriteBloodTest) {
+		return DeleteLabCodeMappingPayload{}, ErrPermissionDenied{}
+	}
+
+	err := a.app.DeleteLabCodeMapping(params.LabCodeMappingId)
+	if err != nil {
+		return DeleteLabCodeMappingPayload{}, err
+	}
+
+	return DeleteLabCodeMappingPayload{}, nil
+}
+
+// Reasons of the lab results that weren't imported.
+const (
+	LabResultSkipReasonNotOruR01        = "not-oru-r01"
+	LabResultSkipReasonInvalidMessage   = "invalid-message"
+	LabResultSkipReasonPatientNotFound  = "patient-not-found"
+	LabResultSkipReasonAmbiguousPatient = "ambiguous-patient"
+	LabResultSkipReasonPatientMismatch  = "patient-mismatch"
+	LabResultSkipReasonUnmappedCode     = "unmapped-code"
+	LabResultSkipReasonEmptyValue       = "empty-value"
+	LabResultSkipReasonNonNumericValue  = "non-numeric-value"
+	LabResultSkipReasonUnknownUnit      = "unknown-unit"
+	LabResultSkipReasonIncompatibleUnit = "incompatible-unit"
+	LabResultSkipReasonAlreadyImported  = "already-imported"
+	LabResultSkipReasonWrongPatient     = "wrong-patient"
+	LabResultSkipReasonDeletedResult    = "deleted-result"
+)
+
+type ImportedLabResult struct {
+	MessageControlId  string `json:"message_control_id"`
+	PatientPublicId   string `json:"patient_public_id"`
+	BloodTestResultId uint   `json:"blood_test_result_id"`
+	BloodTestName     string `json:"blood_test_name"`
+	FieldsCount       int    `json:"fields_count"`
+}
+
+// SkippedLabResult is a message or an observation that wasn't imported, where
+// the code is empty when the whole message was skipped.
+type SkippedLabResult struct {
+	MessageControlId string `json:"message_control_id"`
+	Code             string `json:"code"`
+	Reason           string `json:"reason"`
+}
+
+type ImportHl7LabResultsParams struct {
+	// Data is a file's content, which can have many messages.
+	Data []byte
+	// PatientIdAuthority is the assigning authority of the patients' public
+	// ids, where the ids that other authorities assigned aren't taken as
+	// public ids, and it's not checked when it's empty.
+	PatientIdAuthority string
+}
+
+type ImportHl7LabResultsPayload struct {
+	Imported []ImportedLabResult `json:"imported"`
+	Skipped  []SkippedLabResult  `json:"skipped"`
+}
+
+// ImportHl7LabResults creates pending blood test results from the ORU^R01
+// messages, to be reviewed before they're final, where the patients are
+// matched by their public or national ids, and cross-checked by their last
+// names and dates of birth, and the observations are mapped to
+// the blood tests' fields by the lab code mappings, or by the fields' LOINC
+// codes when they're not mapped, and each order's observations of the same
+// blood test make a single result. The results keep their message's sending
+// facility and control id, so that a message that was already imported, e.g.
+// when a file that failed midway is imported again, is skipped.
+//
+// It's run by the lab results importer, and not by an account, so it doesn't
+// check for permissions.
+func (a *Actions) ImportHl7LabResults(params ImportHl7LabResultsParams) (ImportHl7LabResultsPayload, error) {
+	messages, err := hl7.ParseMessages(params.Data)
+	if err != nil {
+		return ImportHl7LabResultsPayload{}, err
+	}
+
+	mappings, err := a.app.ListLabCodeMappings()
+	if err != nil {
+		return ImportHl7LabResultsPayload{}, err
+	}
+	mappingsIdx := make(map[string]uint, len(mappings))
+	for _, mapping := range mappings {
+		mappingsIdx[labCodeKey(mapping.CodingSystem, mapping.Code)] = mapping.BloodTestFieldId
+	}
+
+	bloodTests, err := a.app.ListAllBloodTests()
+	if err != nil {
+		return ImportHl7LabResultsPayload{}, err
+	}
+	fieldsBloodTests := make(map[uint]models.BloodTest)
+	loincFields := make(map[string]uint)
+	for _, bt := range bloodTests {
+		for _, field := range bt.Fields {
+			fieldsBloodTests[field.Id] = bt
+			if field.LoincCode != "" {
+				loincFields[field.LoincCode] = field.Id
+			}
+		}
+	}
+
+	payload := ImportHl7LabResultsPayload{
+		Imported: make([]ImportedLabResult, 0),
+		Skipped:  make([]SkippedLabResult, 0),
+	}
+	for _, message := range messages {
+		oru, err := hl7.ParseOruR01(message)
+		if errors.Is(err, hl7.ErrNotOruR01) {
+			payload.Skipped = append(payload.Skipped, SkippedLabResult{
+				MessageControlId: message.ControlId(),
+				Reason:           LabResultSkipReasonNotOruR01,
+			})
+			continue
+		}
+		if err != nil {
+			payload.Skipped = append(payload.Skipped, SkippedLabResult{
+				MessageControlId: message.ControlId(),
+				Reason:           LabResultSkipReasonInvalidMessage,
+			})
+			continue
+		}
+
+		if oru.ControlId != "" {
+			importedResults, err := a.app.ListBloodTestResultsForLabMessage(oru.SendingFacility, oru.ControlId)
+			if err != nil {
+				return ImportHl7LabResultsPayload{}, err
+			}
+			if len(importedResults) > 0 {
+				payload.Skipped = append(payload.Skipped, SkippedLabResult{
+					MessageControlId: oru.ControlId,
+					Reason:           LabResultSkipReasonAlreadyImported,
+				})
+				continue
+			}
+		}
+
+		patient, reason, err := a.matchLabResultPatient(oru.Patient, params.PatientIdAuthority)
+		if err != nil {
+			return ImportHl7LabResultsPayload{}, err
+		}
+		if reason != "" {
+			payload.Skipped = append(payload.Skipped, SkippedLabResult{
+				MessageControlId: oru.ControlId,
+				Reason:           reason,
+			})
+			continue
+		}
+
+		results := make([]models.BloodTestResult, 0)
+		for _, order := range oru.Orders {
+			orderResults := make(map[uint]int)
+			for _, observation := range order.Observations {
+				// the withdrawn results aren't imported.
+				statusReason := ""
+				switch observation.ResultStatus {
+				case hl7.ResultStatusWrongPatient:
+					statusReason = LabResultSkipReasonWrongPatient
+				case hl7.ResultStatusDeleted:
+					statusReason = LabResultSkipReasonDeletedResult
+				}
+				if statusReason != "" {
+					payload.Skipped = append(payload.Skipped, SkippedLabResult{
+						MessageControlId: oru.ControlId,
+						Code:             observation.Identifier.Code,
+						Reason:           statusReason,
+					})
+					continue
+				}
+
+				fieldId, ok := mappingsIdx[labCodeKey(observation.Identifier.System, observation.Identifier.Code)]
+				if !ok {
+					fieldId, ok = mappingsIdx[labCodeKey("", observation.Identifier.Code)]
+				}
+				if !ok && observation.Identifier.AlternateCode != "" {
+					fieldId, ok = mappingsIdx[labCodeKey(observation.Identifier.AlternateSystem, observation.Identifier.AlternateCode)]
+				}
+				if !ok {
+					fieldId, ok = labObservationLoincField(observation.Identifier, loincFields)
+				}
+				bloodTest, hasBloodTest := fieldsBloodTests[fieldId]
+				if !ok || !hasBloodTest {
+					payload.Skipped = append(payload.Skipped, SkippedLabResult{
+						MessageControlId: oru.ControlId,
+						Code:             observation.Identifier.Code,
+						Reason:           LabResultSkipReasonUnmappedCode,
+					})
+					continue
+				}
+
+				filledField, reason := labObservationIntoFilledField(bloodTest, fieldId, observation)
+				if reason != "" {
+					payload.Skipped = append(payload.Skipped, SkippedLabResult{
+						MessageControlId: oru.ControlId,
+						Code:             observation.Identifier.Code,
+						Reason:           reason,
+					})
+					continue
+				}
+
+				testedAt := firstNonZeroTime(observation.ObservedAt, order.ObservedAt, time.Now().UTC())
+				filledField.TestedAt = testedAt
+
+				idx, ok := orderResults[bloodTest.Id]
+				if !ok {
+					idx = len(results)
+					orderResults[bloodTest.Id] = idx
+					results = append(results, models.BloodTestResult{
+						BloodTestId:         bloodTest.Id,
+						PatientId:           patient.Id,
+						FilledFields:        make([]models.BloodTestFilledField, 0),
+						Pending:             true,
+						TestedAt:            testedAt,
+						LabSendingFacility:  oru.SendingFacility,
+						LabMessageControlId: oru.ControlId,
+					})
+				}
+				results[idx].FilledFields = append(results[idx].FilledFields, filledField)
+			}
+		}
+
+		// a message's results are imported all together, or none is.
+		imported := make([]ImportedLabResult, 0, len(results))
+		err = a.app.WithTransaction(func(tx *app.App) error {
+			for _, result := range results {
+				bloodTest := fieldsBloodTests[result.FilledFields[0].BloodTestFieldId]
+				result.FilledFields = computeBloodTestFormulaFields(bloodTest, result.FilledFields)
+				btr, err := tx.CreateBloodTestResult(result)
+				if err != nil {
+					return err
+				}
+				imported = append(imported, ImportedLabResult{
+					MessageControlId:  oru.ControlId,
+					PatientPublicId:   patient.PublicId,
+					BloodTestResultId: btr.Id,
+					BloodTestName:     bloodTest.Name,
+					FieldsCount:       len(result.FilledFields),
+				})
+			}
+
+			return nil
+		})
+		if err != nil {
+			return ImportHl7LabResultsPayload{}, err
+		}
+		payload.Imported = append(payload.Imported, imported...)
+
+		for _, result := range imported {
+			a.audit(ActionContext{}, auditEntry{
+				Action:          "ImportHl7LabResults",
+				Kind:            models.AuditEventKindWrite,
+				PatientPublicId: result.PatientPublicId,
+				ResourceIds:     map[string]uint{"blood_test_result_id": result.BloodTestResultId},
+			})
+		}
+	}
+
+	return payload, nil
+}
+
+// labObservationLoincField finds the field that's coded with the observation's
+// LOINC code, which is either its code or its alternate code.
+func labObservationLoincField(identifier hl7.Coded, loincFields map[string]uint) (uint, bool) {
+	for _, coded := range [][2]string{
+		{identifier.System, identifier.Code},
+		{identifier.AlternateSystem, identifier.AlternateCode},
+	} {
+		if !strings.EqualFold(strings.TrimSpace(coded[0]), hl7.CodingSystemLoinc) {
+			continue
+		}
+		fieldId, ok := loincFields[strings.TrimSpace(coded[1])]
+		if ok {
+			return fieldId, true
+		}
+	}
+
+	return 0, false
+}
+
+func labCodeKey(codingSystem, code string) string {
+	return strings.ToUpper(strings.TrimSpace(codingSystem)) + "|" + strings.TrimSpace(code)
+}
+
+func firstNonZeroTime(times ...time.Time) time.Time {
+	for _, t := range times {
+		if !t.IsZero() {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// matchLabResultPatient finds the patient of a message by its identifiers,
+// where the clinic's ids, i.e. the untyped ones and the ones typed as the
+// patient's internal or external ids, are tried as public ids, the national
+// ones are tried as national ids, and the rest, e.g. a lab's MRN, are ignored.
+// A patient only matches when the message's last name and date of birth are
+// the patient's, and the reason is set when the patient can't be told.
+func (a *Actions) matchLabResultPatient(hl7Patient hl7.Patient, publicIdAuthority string) (patient models.Patient, reason string, err error) {
+	mismatched := false
+	for _, identifier := range hl7Patient.Identifiers {
+		var notFoundErr *app.ErrNotFound
+		switch identifier.TypeCode {
+		case "", hl7.IdentifierTypePatientInternal, hl7.IdentifierTypePatientExternal:
+			if publicIdAuthority != "" && identifier.AssigningAuthority != "" &&
+				!strings.EqualFold(identifier.AssigningAuthority, publicIdAuthority) {
+				continue
+			}
+			patient, err = a.app.GetPatientByPublicId(identifier.Id)
+			if errors.As(err, &notFoundErr) {
+				continue
+			}
+			if err != nil {
+				return models.Patient{}, "", err
+			}
+		case hl7.IdentifierTypeNational:
+			patients, err := a.app.FindPatientsByIndexFields(models.PatientIndexFields{
+				NationalId: identifier.Id,
+			})
+			if err != nil && !errors.As(err, &notFoundErr) {
+				return models.Patient{}, "", err
+			}
+			switch len(patients) {
+			case 0:
+				continue
+			case 1:
+				patient = patients[0]
+			default:
+				return models.Patient{}, LabResultSkipReasonAmbiguousPatient, nil
+			}
+		default:
+			continue
+		}
+
+		if !labPatientDemographicsMatch(hl7Patient, patient) {
+			mismatched = true
+			continue
+		}
+		return patient, "", nil
+	}
+
+	if mismatched {
+		return models.Patient{}, LabResultSkipReasonPatientMismatch, nil
+	}
+	return models.Patient{}, LabResultSkipReasonPatientNotFound, nil
+}
+
+// labPatientDemographicsMatch cross-checks the message's last name and date of
+// birth with the patient's, where a message without either can't be checked,
+// so it doesn't match.
+func labPatientDemographicsMatch(hl7Patient hl7.Patient, patient models.Patient) bool {
+	lastName := strings.TrimSpace(hl7Patient.LastName)
+	if lastName == "" && hl7Patient.DateOfBirth.IsZero() {
+		return false
+	}
+	if lastName != "" && !strings.EqualFold(lastName, strings.TrimSpace(patient.LastName)) {
+		return false
+	}
+	// the date of birth is a date, which is in the clinic's location in the
+	// message, and at midnight UTC in the patient's record.
+	if !hl7Patient.DateOfBirth.IsZero() &&
+		hl7Patient.DateOfBirth.In(time.Local).Format(time.DateOnly) != patient.DateOfBirth.UTC().Format(time.DateOnly) {
+		return false
+	}
+
+	return true
+}
+
+// labObservationIntoFilledField converts an observation into its field's
+// value, in the field's unit, where the reason is set when it can't be.
+func labObservationIntoFilledField(bloodTest models.BloodTest, fieldId uint, observation hl7.Observation) (models.BloodTestFilledField, string) {
+	if observation.Value == "" {
+		return models.BloodTestFilledField{}, LabResultSkipReasonEmptyValue
+	}
+
+	var unit models.BlootTestUnit
+	if observation.Units != "" {
+		var ok bool
+		unit, ok = unitconv.ParseUnit(observation.Units)
+		if !ok {
+			return models.BloodTestFilledField{}, LabResultSkipReasonUnknownUnit
+		}
+	}
+
+	valueNumber, err := strconv.ParseFloat(observation.Value, 64)
+	// a text value, e.g. "<5", can't be converted, so it's only taken in its
+	// field's unit.
+	fieldIdx := slices.IndexFunc(bloodTest.Fields, func(field models.BloodTestField) bool {
+		return field.Id == fieldId
+	})
+	if err != nil && unit != "" && fieldIdx != -1 && unit != bloodTest.Fields[fieldIdx].Unit {
+		return models.BloodTestFilledField{}, LabResultSkipReasonNonNumericValue
+	}
+
+	filledFields, err := normalizeBloodTestFilledFields(bloodTest, []BloodTestFilledField{
+		{
+			BloodTestFieldId: fieldId,
+			Unit:             unit,
+			ValueNumber:      valueNumber,
+			ValueString:      observation.Value,
+		},
+	})
+	if err != nil {
+		return models.BloodTestFilledField{}, LabResultSkipReasonIncompatibleUnit
+	}
+
+	return filledFields[0], ""
+}
@@ -2,6 +2,7 @@ package actions
 
 import (
 	"fmt"
+	"shs/app"
 	"shs/app/models"
 	"shs/log"
 	"time"
@@ -25,6 +26,10 @@ func (m Medicine) DoseUnit() string {
 	return fmt.Sprintf("%d %s", m.Dose, m.Unit)
 }
 
+func (m Medicine) IsExpired() bool {
+	return m.IntoModel().IsExpiredAt(time.Now())
+}
+
 type CreateMedicineParams struct {
 	ActionContext
 	NewMedicine Medicine `json:"new_medicine"`
@@ -69,7 +74,26 @@ func (a *Actions) CreateMedicine(params CreateMedicineParams) (CreateMedicinePay
 		return CreateMedicinePayload{}, ErrPermissionDenied{}
 	}
 
-	_, err := a.app.CreateMedicine(params.NewMedicine.IntoModel())
+	err := a.app.WithTransaction(func(tx *app.App) error {
+		med := params.NewMedicine.IntoModel()
+		openingAmount := med.Amount
+		med.Amount = 0
+
+		med, err := tx.CreateMedicine(med)
+		if err != nil {
+			return err
+		}
+
+		if openingAmount == 0 {
+			return nil
+		}
+
+		return recordStockMovement(tx, med, models.StockMovement{
+			Kind:      models.StockMovementKindReceivedDonation,
+			Delta:     openingAmount,
+			AccountId: params.Account.Id,
+		})
+	})
 
 	return CreateMedicinePayload{}, err
 }
@@ -77,7 +101,10 @@ func (a *Actions) CreateMedicine(params CreateMedicineParams) (CreateMedicinePay
 type UpdateMedicineParams struct {
 	ActionContext
 	MedicineId uint `json:"medicine_id"`
-	Amount     int  `json:"amount"`
+	// Amount is the counted amount of packages, and the difference from the
+	// recorded amount is logged as a manual correction.
+	Amount int    `json:"amount"`
+	Note   string `json:"note"`
 }
 
 type UpdateMedicinePayload struct {
@@ -88,7 +115,33 @@ func (a *Actions) UpdateMedicine(params UpdateMedicineParams) (UpdateMedicinePay
 		return UpdateMedicinePayload{}, ErrPermissionDenied{}
 	}
 
-	err := a.app.UpdateMedicineAmount(params.MedicineId, params.Amount)
+	if params.Amount < 0 {
+		return UpdateMedicinePayload{}, ErrValidation{Field: "amount"}
+	}
+
+	err := a.app.WithTransaction(func(tx *app.App) error {
+		meds, err := tx.LockMedicinesByIds([]uint{params.MedicineId})
+		if err != nil {
+			return err
+		}
+		if len(meds) == 0 {
+			return app.ErrNotFound{
+				ResourceName: "medicine",
+			}
+		}
+		med := meds[0]
+
+		if params.Amount == med.Amount {
+			return nil
+		}
+
+		return recordStockMovement(tx, med, models.StockMovement{
+			Kind:      models.StockMovementKindManualCorrection,
+			Delta:     params.Amount - med.Amount,
+			AccountId: params.Account.Id,
+			Note:      params.Note,
+		})
+	})
 
 	return UpdateMedicinePayload{}, err
 }
@@ -224,6 +277,11 @@ func (a *Actions) ListAllPrescribedMedicine(params ListAllPrescribedMedicinePara
 		})
 	}
 
+	a.audit(params.ActionContext, auditEntry{
+		Action: "ListAllPrescribedMedicine",
+		Kind:   models.AuditEventKindRead,
+	})
+
 	return ListAllPrescribedMedicinePayload{
 		Data: outData,
 	}, nil
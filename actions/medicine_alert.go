@@ -0,0 +1,187 @@
+package actions
+
+import (
+	"shs/app"
+	"shs/app/models"
+	"slices"
+	"time"
+)
+
+type MedicineAlert struct {
+	Id         uint      `json:"id"`
+	Kind       string    `json:"kind"`
+	WindowDays int       `json:"window_days"`
+	Amount     int       `json:"amount"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	RaisedAt   time.Time `json:"raised_at"`
+	Medicine   Medicine  `json:"medicine"`
+}
+
+func (ma *MedicineAlert) FromModel(alert models.MedicineAlert, medicine models.Medicine) {
+	outMedicine := new(Medicine)
+	outMedicine.FromModel(medicine)
+
+	(*ma) = MedicineAlert{
+		Id:         alert.Id,
+		Kind:       string(alert.Kind),
+		WindowDays: alert.WindowDays,
+		Amount:     alert.Amount,
+		ExpiresAt:  alert.ExpiresAt,
+		RaisedAt:   alert.CreatedAt,
+		Medicine:   *outMedicine,
+	}
+}
+
+type ScanMedicineAlertsParams struct {
+	Now               time.Time
+	ExpiryWindowsDays []int
+	ReorderThreshold  int
+}
+
+type ScanMedicineAlertsPayload struct {
+	AlertsCount int
+}
+
+// ScanMedicineAlerts is run by the daily job, it replaces the medicine alerts
+// with batches that are expired or about to, and medicines whose valid batches
+// went below the reorder threshold.
+func (a *Actions) ScanMedicineAlerts(params ScanMedicineAlertsParams) (ScanMedicineAlertsPayload, error) {
+	medicines, err := a.app.ListAllMedicines()
+	if err != nil {
+		return ScanMedicineAlertsPayload{}, err
+	}
+
+	windows := slices.Clone(params.ExpiryWindowsDays)
+	slices.Sort(windows)
+
+	alerts := make([]models.MedicineAlert, 0)
+	// a medicine is made of several batches, so the stock is checked against
+	// the sum of all of its valid batches.
+	type stockKey struct {
+		name string
+		dose int
+		unit string
+	}
+	stock := make(map[stockKey]models.MedicineAlert)
+	stockOrder := make([]stockKey, 0)
+
+	for _, med := range medicines {
+		if med.IsExpiredAt(params.Now) {
+			if med.Amount > 0 {
+				alerts = append(alerts, models.MedicineAlert{
+					MedicineId: med.Id,
+					Kind:       models.MedicineAlertKindExpired,
+					Amount:     med.Amount,
+					ExpiresAt:  med.ExpiresAt,
+				})
+			}
+			continue
+		}
+
+		key := stockKey{name: med.Name, dose: med.Dose, unit: med.Unit}
+		stockAlert, ok := stock[key]
+		if !ok {
+			stockOrder = append(stockOrder, key)
+		}
+		stockAlert.Amount += med.Amount
+		// the low stock alert points to the batch that expires the latest,
+		// since it's the one that lasts till the reorder arrives.
+		if !med.ExpiresAt.Before(stockAlert.ExpiresAt) {
+			stockAlert.MedicineId = med.Id
+			stockAlert.ExpiresAt = med.ExpiresAt
+		}
+		stock[key] = stockAlert
+
+		if med.Amount == 0 {
+			continue
+		}
+		for _, window := range windows {
+			if med.IsExpiredAt(params.Now.AddDate(0, 0, window)) {
+				alerts = append(alerts, models.MedicineAlert{
+					MedicineId: med.Id,
+					Kind:       models.MedicineAlertKindExpiring,
+					WindowDays: window,
+					Amount:     med.Amount,
+					ExpiresAt:  med.ExpiresAt,
+				})
+				break
+			}
+		}
+	}
+
+	for _, key := range stockOrder {
+		stockAlert := stock[key]
+		if stockAlert.Amount >= params.ReorderThreshold {
+			continue
+		}
+		stockAlert.Kind = models.MedicineAlertKindLowStock
+		alerts = append(alerts, stockAlert)
+	}
+
+	err = a.app.WithTransaction(func(tx *app.App) error {
+		err := tx.DeleteAllMedicineAlerts()
+		if err != nil {
+			return err
+		}
+
+		return tx.CreateMedicineAlerts(alerts)
+	})
+	if err != nil {
+		return ScanMedicineAlertsPayload{}, err
+	}
+
+	return ScanMedicineAlertsPayload{
+		AlertsCount: len(alerts),
+	}, nil
+}
+
+type ListMedicineAlertsParams struct {
+	ActionContext
+}
+
+type ListMedicineAlertsPayload struct {
+	Data []MedicineAlert `json:"data"`
+}
+
+func (a *Actions) ListMedicineAlerts(params ListMedicineAlertsParams) (ListMedicineAlertsPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadMedicine) {
+		return ListMedicineAlertsPayload{}, ErrPermissionDenied{}
+	}
+
+	alerts, err := a.app.ListAllMedicineAlerts()
+	if err != nil {
+		return ListMedicineAlertsPayload{}, err
+	}
+
+	medsIds := make([]uint, 0, len(alerts))
+	for _, alert := range alerts {
+		medsIds = append(medsIds, alert.MedicineId)
+	}
+
+	meds, err := a.app.ListMedicinesByIds(medsIds)
+	if err != nil {
+		return ListMedicineAlertsPayload{}, err
+	}
+
+	medsMapped := make(map[uint]models.Medicine)
+	for _, med := range meds {
+		medsMapped[med.Id] = med
+	}
+
+	outAlerts := make([]MedicineAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		med, ok := medsMapped[alert.MedicineId]
+		if !ok {
+			// the medicine was deleted after the last scan.
+			continue
+		}
+
+		outAlert := new(MedicineAlert)
+		outAlert.FromModel(alert, med)
+		outAlerts = append(outAlerts, *outAlert)
+	}
+
+	return ListMedicineAlertsPayload{
+		Data: outAlerts,
+	}, nil
+}
@@ -8,6 +8,7 @@ import (
 	"shs/app/models"
 	"shs/cardgen"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -22,16 +23,37 @@ type DiagnosisResult struct {
 }
 
 type BloodTestFilledField struct {
-	BloodTestFieldId uint                 `json:"blood_test_field_id"`
-	Name             string               `json:"name"`
-	Unit             models.BlootTestUnit `json:"unit"`
-	ValueNumber      float64              `json:"value_number"`
-	ValueString      string               `json:"value_string"`
+	BloodTestFieldId uint   `json:"blood_test_field_id"`
+	Name             string `json:"name"`
+	LoincCode        string `json:"loinc_code"`
+	// Unit is the field's unit, and when a result is entered, it's the unit
+	// that the value is entered in, where an empty unit is the field's unit.
+	Unit              models.BlootTestUnit `json:"unit"`
+	ValueNumber       float64              `json:"value_number"`
+	ValueString       string               `json:"value_string"`
+	Flag              string               `json:"flag"`
+	HasReferenceRange bool                 `json:"has_reference_range"`
+	ReferenceLow      float64              `json:"reference_low"`
+	ReferenceHigh     float64              `json:"reference_high"`
+	// Computed is a field that's computed from the result's other fields.
+	Computed bool `json:"computed"`
+	// OriginalValue and OriginalUnit are the value as it was entered, when it
+	// was entered in another unit than the field's.
+	OriginalValue string               `json:"original_value"`
+	OriginalUnit  models.BlootTestUnit `json:"original_unit"`
+}
+
+// Converted reports whether the value was entered in another unit, and was
+// converted to the field's unit.
+func (field BloodTestFilledField) Converted() bool {
+	return field.OriginalUnit != ""
 }
 
 func (field BloodTestFilledField) ValueUnit() string {
 	valueUnit := new(strings.Builder)
-	if field.ValueNumber != 0.0 {
+	if field.Computed {
+		valueUnit.WriteString(strconv.FormatFloat(field.ValueNumber, 'f', -1, 64))
+	} else if field.ValueNumber != 0.0 {
 		fmt.Fprintf(valueUnit, "%2.f", field.ValueNumber)
 	} else {
 		valueUnit.WriteString(field.ValueString)
@@ -43,17 +65,37 @@ func (field BloodTestFilledField) ValueUnit() string {
 
 }
 
+func (field BloodTestFilledField) Number() (float64, bool) {
+	return models.BloodTestFilledField{
+		ValueNumber: field.ValueNumber,
+		ValueString: field.ValueString,
+	}.Number()
+}
+
 type BloodTestResult struct {
 	Id             uint                   `json:"id"`
 	Name           string                 `json:"name"`
 	BloodTestId    uint                   `json:"blood_test_id"`
 	FilledFields   []BloodTestFilledField `json:"filled_fields"`
 	Pending        bool                   `json:"pending"`
+	Abnormal       bool                   `json:"abnormal"`
 	DisplayInBrief bool                   `json:"display_in_brief"`
 	TestedAt       time.Time              `json:"tested_at"`
 	CreatedAt      time.Time              `json:"created_at"`
 }
 
+// FieldValue returns the field's entered value, or an empty string when the
+// field wasn't filled.
+func (btr BloodTestResult) FieldValue(fieldId uint) string {
+	for _, field := range btr.FilledFields {
+		if field.BloodTestFieldId == fieldId {
+			return field.ValueString
+		}
+	}
+
+	return ""
+}
+
 type Address struct {
 	Id          uint   `json:"id"`
 	Governorate string `json:"governorate"`
@@ -94,6 +136,9 @@ type Patient struct {
 	JointsEvaluations      []JointsEvaluation `json:"joints_evaluations"`
 	Diagnoses              []DiagnosisResult  `json:"diagnoses"`
 	Prophylaxes            []Prophylaxis      `json:"prophylaxes"`
+	BleedingEpisodes       []BleedingEpisode  `json:"bleeding_episodes"`
+	TargetJoints           []TargetJoint      `json:"target_joints"`
+	Severity               HemophiliaSeverity `json:"severity"`
 }
 
 func (p Patient) FullName() string {
@@ -164,44 +209,14 @@ func (p *Patient) FromModel(patient models.Patient) {
 	}
 }
 
+// WithBloodTestResults flags the results' values using the patient's age and
+// gender, so it must be called after setting the patient's details.
 func (p *Patient) WithBloodTestResults(patientBloodTestResults []models.BloodTestResult, bloodTests []models.BloodTest) {
-	bloodTestNames := make(map[uint]string)
-	bloodTestFlash := make(map[uint]bool)
-	bloodTestFieldNames := make(map[uint]string)
-	bloodTestFieldUnits := make(map[uint]models.BlootTestUnit)
-
-	for _, bt := range bloodTests {
-		bloodTestNames[bt.Id] = bt.Name
-		bloodTestFlash[bt.Id] = bt.DisplayInBrief
-		for _, field := range bt.Fields {
-			bloodTestFieldNames[field.Id] = field.Name
-			bloodTestFieldUnits[field.Id] = field.Unit
-		}
-	}
+	idx := newBloodTestsIndex(bloodTests)
 
 	(*p).BloodTestResults = make([]BloodTestResult, 0, len(patientBloodTestResults))
 	for _, btr := range patientBloodTestResults {
-		fields := make([]BloodTestFilledField, 0, len(btr.FilledFields))
-		for _, field := range btr.FilledFields {
-			fields = append(fields, BloodTestFilledField{
-				BloodTestFieldId: field.BloodTestFieldId,
-				Name:             bloodTestFieldNames[field.BloodTestFieldId],
-				Unit:             bloodTestFieldUnits[field.BloodTestFieldId],
-				ValueNumber:      field.ValueNumber,
-				ValueString:      field.ValueString,
-			})
-		}
-
-		(*p).BloodTestResults = append((*p).BloodTestResults, BloodTestResult{
-			Id:             btr.Id,
-			BloodTestId:    btr.BloodTestId,
-			Name:           bloodTestNames[btr.BloodTestId],
-			FilledFields:   fields,
-			Pending:        btr.Pending,
-			DisplayInBrief: bloodTestFlash[btr.BloodTestId],
-			CreatedAt:      btr.CreatedAt,
-			TestedAt:       btr.TestedAt,
-		})
+		(*p).BloodTestResults = append((*p).BloodTestResults, idx.result(btr, p.DateOfBirth, p.Gender))
 	}
 }
 
@@ -234,6 +249,14 @@ func (p *Patient) WithProphylaxis(pp []models.Prophylaxis) {
 	}
 }
 
+func (p *Patient) WithBleedingEpisodes(episodes []models.BleedingEpisode) {
+	for _, be := range episodes {
+		outEpisode := new(BleedingEpisode)
+		outEpisode.FromModel(be)
+		(*p).BleedingEpisodes = append((*p).BleedingEpisodes, *outEpisode)
+	}
+}
+
 func (p *Patient) WithDiagnoses(diagnosesResults []models.DiagnosisResult, diagnoses []models.Diagnosis) {
 	diagnosisMapped := make(map[uint]Diagnosis)
 
@@ -341,6 +364,12 @@ func (a *Actions) CreatePatient(params CreatePatientParams) (CreatePatientPayloa
 		return CreatePatientPayload{}, err
 	}
 
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "CreatePatient",
+		Kind:            models.AuditEventKindWrite,
+		PatientPublicId: newPatient.PublicId,
+	})
+
 	return CreatePatientPayload{
 		PatientPublicId: newPatient.PublicId,
 	}, nil
@@ -361,7 +390,7 @@ func (a *Actions) UpdatePatient(params UpdatePatientParams) (UpdatePatientPayloa
 		return UpdatePatientPayload{}, ErrPermissionDenied{}
 	}
 
-	oldPatient, err := a.getFullPatientByPublicId(params.PatientPublicId)
+	oldPatient, err := a.app.GetPatientByPublicId(params.PatientPublicId)
 	if err != nil {
 		return UpdatePatientPayload{}, err
 	}
@@ -406,7 +435,12 @@ func (a *Actions) UpdatePatient(params UpdatePatientParams) (UpdatePatientPayloa
 		newPatient.PlaceOfBirthId = placeOfBirth.Id
 	}
 
-	newPatient, err = a.app.UpdatePatient(oldPatient.Id, newPatient)
+	newPatient.PublicId = oldPatient.PublicId
+	err = a.app.WithTransaction(func(tx *app.App) error {
+		var err error
+		newPatient, err = updatePatientWithRevision(tx, oldPatient, newPatient, params.Account, 0)
+		return err
+	})
 	if err != nil {
 		return UpdatePatientPayload{}, err
 	}
@@ -417,6 +451,19 @@ func (a *Actions) UpdatePatient(params UpdatePatientParams) (UpdatePatientPayloa
 		password = cleanPhoneNumberCountryCode(params.NewPatient.PhoneNumber)
 	}
 
+	before := new(Patient)
+	before.FromModel(oldPatient)
+	after := new(Patient)
+	after.FromModel(newPatient)
+
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "UpdatePatient",
+		Kind:            models.AuditEventKindWrite,
+		PatientPublicId: oldPatient.PublicId,
+		Before:          before,
+		After:           after,
+	})
+
 	return UpdatePatientPayload{
 		PatientPublicId: newPatient.PublicId,
 	}, nil
@@ -429,6 +476,8 @@ type CreatePatientBloodTestResultParams struct {
 }
 
 type CreatePatientBloodTestResultPayload struct {
+	// SeverityWarning is set when the result contradicts the recorded diagnosis.
+	SeverityWarning *HemophiliaSeverity `json:"severity_warning"`
 }
 
 func (a *Actions) CreatePatientBloodTestResult(params CreatePatientBloodTestResultParams) (CreatePatientBloodTestResultPayload, error) {
@@ -444,26 +493,41 @@ func (a *Actions) CreatePatientBloodTestResult(params CreatePatientBloodTestResu
 		return CreatePatientBloodTestResultPayload{}, err
 	}
 
-	bloodTestResultFields := make([]models.BloodTestFilledField, 0, len(params.BloodTest.FilledFields))
-	for _, field := range params.BloodTest.FilledFields {
-		bloodTestResultFields = append(bloodTestResultFields, models.BloodTestFilledField{
-			BloodTestFieldId: field.BloodTestFieldId,
-			ValueNumber:      field.ValueNumber,
-			ValueString:      field.ValueString,
-		})
+	bloodTest, err := a.app.GetBloodTest(params.BloodTest.BloodTestId)
+	if err != nil {
+		return CreatePatientBloodTestResultPayload{}, err
 	}
 
-	_, err = a.app.CreateBloodTestResult(models.BloodTestResult{
+	bloodTestResultFields, err := normalizeBloodTestFilledFields(bloodTest, params.BloodTest.FilledFields)
+	if err != nil {
+		return CreatePatientBloodTestResultPayload{}, err
+	}
+
+	btr, err := a.app.CreateBloodTestResult(models.BloodTestResult{
 		BloodTestId:  params.BloodTest.BloodTestId,
 		PatientId:    patient.Id,
-		FilledFields: bloodTestResultFields,
+		FilledFields: computeBloodTestFormulaFields(bloodTest, bloodTestResultFields),
 		Pending:      params.BloodTest.Pending,
 	})
 	if err != nil {
 		return CreatePatientBloodTestResultPayload{}, err
 	}
 
-	return CreatePatientBloodTestResultPayload{}, nil
+	severityWarning, err := a.getSeverityWarning(params.PatientPublicId, btr.Id)
+	if err != nil {
+		return CreatePatientBloodTestResultPayload{}, err
+	}
+
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "CreatePatientBloodTestResult",
+		Kind:            models.AuditEventKindWrite,
+		PatientPublicId: params.PatientPublicId,
+		ResourceIds:     map[string]uint{"blood_test_result_id": btr.Id},
+	})
+
+	return CreatePatientBloodTestResultPayload{
+		SeverityWarning: severityWarning,
+	}, nil
 }
 
 type CreatePatientDiagnosisResultParams struct {
@@ -497,6 +561,13 @@ func (a *Actions) CreatePatientDiagnosisResult(params CreatePatientDiagnosisResu
 		return CreatePatientDiagnosisResultPayload{}, err
 	}
 
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "CreatePatientDiagnosisResult",
+		Kind:            models.AuditEventKindWrite,
+		PatientPublicId: params.PatientPublicId,
+		ResourceIds:     map[string]uint{"diagnosis_id": params.Diagnosis.DiagnosisId},
+	})
+
 	return CreatePatientDiagnosisResultPayload{}, nil
 }
 
@@ -508,6 +579,8 @@ type UpdatePatientPendingBloodTestResultParams struct {
 }
 
 type UpdatePatientPendingBloodTestResultPayload struct {
+	// SeverityWarning is set when the result contradicts the recorded diagnosis.
+	SeverityWarning *HemophiliaSeverity `json:"severity_warning"`
 }
 
 func (a *Actions) UpdatePatientPendingBloodTestResult(params UpdatePatientPendingBloodTestResultParams) (UpdatePatientPendingBloodTestResultPayload, error) {
@@ -523,29 +596,47 @@ func (a *Actions) UpdatePatientPendingBloodTestResult(params UpdatePatientPendin
 		return UpdatePatientPendingBloodTestResultPayload{}, err
 	}
 
-	if !slices.ContainsFunc(patient.BloodTestResults, func(btr BloodTestResult) bool {
+	btrIdx := slices.IndexFunc(patient.BloodTestResults, func(btr BloodTestResult) bool {
 		return btr.Id == params.BloodTestResultId
-	}) {
+	})
+	if btrIdx == -1 {
 		return UpdatePatientPendingBloodTestResultPayload{}, app.ErrNotFound{
 			ResourceName: "blood_test_result",
 		}
 	}
 
-	bloodTestResultFields := make([]models.BloodTestFilledField, 0, len(params.FilledFields))
-	for _, field := range params.FilledFields {
-		bloodTestResultFields = append(bloodTestResultFields, models.BloodTestFilledField{
-			BloodTestFieldId: field.BloodTestFieldId,
-			ValueNumber:      field.ValueNumber,
-			ValueString:      field.ValueString,
-		})
+	bloodTest, err := a.app.GetBloodTest(patient.BloodTestResults[btrIdx].BloodTestId)
+	if err != nil {
+		return UpdatePatientPendingBloodTestResultPayload{}, err
 	}
 
-	err = a.app.UpdatePatientPendingBloodTestResultFields(params.BloodTestResultId, bloodTestResultFields)
+	bloodTestResultFields, err := normalizeBloodTestFilledFields(bloodTest, params.FilledFields)
 	if err != nil {
 		return UpdatePatientPendingBloodTestResultPayload{}, err
 	}
 
-	return UpdatePatientPendingBloodTestResultPayload{}, nil
+	err = a.app.UpdatePatientPendingBloodTestResultFields(params.BloodTestResultId, computeBloodTestFormulaFields(bloodTest, bloodTestResultFields))
+	if err != nil {
+		return UpdatePatientPendingBloodTestResultPayload{}, err
+	}
+
+	severityWarning, err := a.getSeverityWarning(params.PatientPublicId, params.BloodTestResultId)
+	if err != nil {
+		return UpdatePatientPendingBloodTestResultPayload{}, err
+	}
+
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "UpdatePatientPendingBloodTestResult",
+		Kind:            models.AuditEventKindWrite,
+		PatientPublicId: params.PatientPublicId,
+		ResourceIds:     map[string]uint{"blood_test_result_id": params.BloodTestResultId},
+		Before:          map[string]any{"filled_fields": patient.BloodTestResults[btrIdx].FilledFields},
+		After:           map[string]any{"filled_fields": params.FilledFields},
+	})
+
+	return UpdatePatientPendingBloodTestResultPayload{
+		SeverityWarning: severityWarning,
+	}, nil
 }
 
 type FindPatientsParams struct {
@@ -617,6 +708,11 @@ func (a *Actions) FindPatients(params FindPatientsParams) (FindPatientsPayload,
 		outPatients = append(outPatients, *outPatient)
 	}
 
+	a.audit(params.ActionContext, auditEntry{
+		Action: "FindPatients",
+		Kind:   models.AuditEventKindRead,
+	})
+
 	return FindPatientsPayload{
 		Data: outPatients,
 	}, nil
@@ -647,6 +743,11 @@ func (a *Actions) ListLastPatients(params ListLastPatientsParams) (ListLastPatie
 		outPatients = append(outPatients, *outPatient)
 	}
 
+	a.audit(params.ActionContext, auditEntry{
+		Action: "ListLastPatients",
+		Kind:   models.AuditEventKindRead,
+	})
+
 	return ListLastPatientsPayload{
 		Data: outPatients,
 	}, nil
@@ -671,6 +772,12 @@ func (a *Actions) GetPatient(params GetPatientParams) (GetPatientPayload, error)
 		return GetPatientPayload{}, err
 	}
 
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "GetPatient",
+		Kind:            models.AuditEventKindRead,
+		PatientPublicId: params.PublicId,
+	})
+
 	return GetPatientPayload{
 		Data: patient,
 	}, nil
@@ -699,6 +806,12 @@ func (a *Actions) DeletePatient(params DeletePatientParams) (DeletePatientPayloa
 		return DeletePatientPayload{}, err
 	}
 
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "DeletePatient",
+		Kind:            models.AuditEventKindWrite,
+		PatientPublicId: params.PublicId,
+	})
+
 	return DeletePatientPayload{}, nil
 }
 
@@ -716,16 +829,19 @@ func (a *Actions) GeneratePatientCard(params GeneratePatientCardParams) (Generat
 		return GeneratePatientCardPayload{}, ErrPermissionDenied{}
 	}
 
-	patient, err := a.app.GetPatientByPublicId(params.PatientId)
+	patient, err := a.getFullPatientByPublicId(params.PatientId)
 	if err != nil {
 		return GeneratePatientCardPayload{}, err
 	}
 
 	patientCard := cardgen.NewBuffer(nil)
-	generator, err := cardgen.New(patientCard, patient)
+	generator, err := cardgen.New(patientCard, patient.IntoModel())
 	if err != nil {
 		return GeneratePatientCardPayload{}, err
 	}
+	if patient.Severity.Classified() {
+		generator.WithSeverity(patient.Severity.String())
+	}
 
 	err = generator.Generate(false)
 	if err != nil {
@@ -738,6 +854,12 @@ func (a *Actions) GeneratePatientCard(params GeneratePatientCardParams) (Generat
 
 	b64Img := base64.StdEncoding.EncodeToString(patientCard.Bytes())
 
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "GeneratePatientCard",
+		Kind:            models.AuditEventKindRead,
+		PatientPublicId: params.PatientId,
+	})
+
 	return GeneratePatientCardPayload{
 		ImageBase64: b64Img,
 	}, nil
@@ -784,13 +906,21 @@ func (a *Actions) getFullPatientByPublicId(patientId string) (Patient, error) {
 		return Patient{}, err
 	}
 
+	bleedingEpisodes, err := a.app.ListPatientBleedingEpisodes(patient.Id)
+	if err != nil {
+		return Patient{}, err
+	}
+
 	outPatient := new(Patient)
 	outPatient.FromModel(patient)
 	outPatient.WithViruses(viruses)
 	outPatient.WithBloodTestResults(bloodTestResults, bloodTests)
 	outPatient.WithJointsEvaluations(jointsEvaluations)
 	outPatient.WithDiagnoses(diagnosesResults, diagnoses)
+	outPatient.WithSeverity()
 	outPatient.WithProphylaxis(prophylaxes)
+	outPatient.WithBleedingEpisodes(bleedingEpisodes)
+	outPatient.WithTargetJoints(bleedingEpisodes, jointsEvaluations)
 
 	return *outPatient, nil
 }
@@ -0,0 +1,249 @@
+package actions
+
+import (
+	"shs/app"
+	"shs/app/models"
+	"strings"
+	"time"
+)
+
+type PatientRevision struct {
+	Id              uint   `json:"id"`
+	AccountId       uint   `json:"account_id"`
+	AccountUsername string `json:"account_username"`
+	// RestoredFromRevisionId is set when the revision restored an older one.
+	RestoredFromRevisionId uint                   `json:"restored_from_revision_id"`
+	Details                PatientRevisionDetails `json:"details"`
+	// Changes are the fields that the revision changed from the previous one,
+	// where the first revision has no changes.
+	Changes   map[string]Change `json:"changes"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// PatientRevisionDetails is a revision's copy of the patient's details, where
+// the addresses are formatted as in "Governorate, Suburb, Street", so that the
+// changes read as they're shown.
+type PatientRevisionDetails struct {
+	NationalId             string `json:"national_id"`
+	Nationality            string `json:"nationality"`
+	FirstName              string `json:"first_name"`
+	LastName               string `json:"last_name"`
+	FatherName             string `json:"father_name"`
+	MotherName             string `json:"mother_name"`
+	PlaceOfBirth           string `json:"place_of_birth"`
+	DateOfBirth            string `json:"date_of_birth"`
+	Residency              string `json:"residency"`
+	Gender                 bool   `json:"gender"`
+	PhoneNumber            string `json:"phone_number"`
+	PhoneNumberCountryCode string `json:"phone_number_country_code"`
+	BATScore               uint   `json:"bat_score"`
+	FamilyHistoryExists    bool   `json:"family_history_exists"`
+	FirstVisitReason       string `json:"first_visit_reason"`
+	WBDR                   string `json:"wbdr"`
+}
+
+func (d *PatientRevisionDetails) FromModel(revision models.PatientRevision) {
+	(*d) = PatientRevisionDetails{
+		NationalId:             revision.NationalId,
+		Nationality:            revision.Nationality,
+		FirstName:              revision.FirstName,
+		LastName:               revision.LastName,
+		FatherName:             revision.FatherName,
+		MotherName:             revision.MotherName,
+		PlaceOfBirth:           formatAddress(revision.PlaceOfBirth),
+		DateOfBirth:            revision.DateOfBirth.Format(time.DateOnly),
+		Residency:              formatAddress(revision.Residency),
+		Gender:                 revision.Gender,
+		PhoneNumber:            revision.PhoneNumber,
+		PhoneNumberCountryCode: revision.PhoneNumberCountryCode,
+		BATScore:               revision.BATScore,
+		FamilyHistoryExists:    revision.FamilyHistoryExists,
+		FirstVisitReason:       string(revision.FirstVisitReason),
+		WBDR:                   revision.WBDR,
+	}
+}
+
+func (r *PatientRevision) FromModel(revision models.PatientRevision) {
+	(*r) = PatientRevision{
+		Id:                     revision.Id,
+		AccountId:              revision.AccountId,
+		AccountUsername:        revision.AccountUsername,
+		RestoredFromRevisionId: revision.RestoredFromRevisionId,
+		CreatedAt:              revision.CreatedAt,
+	}
+	r.Details.FromModel(revision)
+}
+
+func formatAddress(address models.Address) string {
+	parts := make([]string, 0, 3)
+	for _, part := range []string{address.Governorate, address.Suburb, address.Street} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// updatePatientWithRevision updates the patient's details and records the
+// updated details as the patient's latest revision, where a patient that has
+// no revisions, i.e. was last updated before the revisions were recorded, gets
+// its old details recorded first, so that the first update can be restored
+// too.
+//
+// It's expected to be run in a transaction.
+func updatePatientWithRevision(tx *app.App, oldPatient, newPatient models.Patient, account Account, restoredFromRevisionId uint) (models.Patient, error) {
+	revisions, err := tx.ListPatientRevisions(oldPatient.Id)
+	if err != nil {
+		return models.Patient{}, err
+	}
+	if len(revisions) == 0 {
+		baseline := models.NewPatientRevision(oldPatient)
+		baseline.CreatedAt = oldPatient.UpdatedAt
+		if baseline.CreatedAt.IsZero() {
+			baseline.CreatedAt = oldPatient.CreatedAt
+		}
+		_, err = tx.CreatePatientRevision(baseline)
+		if err != nil {
+			return models.Patient{}, err
+		}
+	}
+
+	_, err = tx.UpdatePatient(oldPatient.Id, newPatient)
+	if err != nil {
+		return models.Patient{}, err
+	}
+
+	// INFO: the updated patient is read back, since the update fills some of
+	// the empty fields, e.g. the national id.
+	updatedPatient, err := tx.GetPatientById(oldPatient.Id)
+	if err != nil {
+		return models.Patient{}, err
+	}
+
+	revision := models.NewPatientRevision(updatedPatient)
+	revision.AccountId = account.Id
+	revision.AccountUsername = account.Username
+	revision.RestoredFromRevisionId = restoredFromRevisionId
+	_, err = tx.CreatePatientRevision(revision)
+	if err != nil {
+		return models.Patient{}, err
+	}
+
+	return updatedPatient, nil
+}
+
+type ListPatientRevisionsParams struct {
+	ActionContext
+	PatientPublicId string
+}
+
+type ListPatientRevisionsPayload struct {
+	Data []PatientRevision `json:"data"`
+}
+
+// ListPatientRevisions lists the patient's revisions from the latest to the
+// first, each with its changes from the revision before it.
+func (a *Actions) ListPatientRevisions(params ListPatientRevisionsParams) (ListPatientRevisionsPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadPatient) {
+		return ListPatientRevisionsPayload{}, ErrPermissionDenied{}
+	}
+
+	patient, err := a.app.GetPatientByPublicId(params.PatientPublicId)
+	if err != nil {
+		return ListPatientRevisionsPayload{}, err
+	}
+
+	revisions, err := a.app.ListPatientRevisions(patient.Id)
+	if err != nil {
+		return ListPatientRevisionsPayload{}, err
+	}
+
+	outRevisions := make([]PatientRevision, len(revisions))
+	for i, revision := range revisions {
+		outRevision := new(PatientRevision)
+		outRevision.FromModel(revision)
+		if i > 0 {
+			previous := outRevisions[len(revisions)-i]
+			outRevision.Changes, err = diff(previous.Details, outRevision.Details)
+			if err != nil {
+				return ListPatientRevisionsPayload{}, err
+			}
+		}
+		outRevisions[len(revisions)-1-i] = *outRevision
+	}
+
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "ListPatientRevisions",
+		Kind:            models.AuditEventKindRead,
+		PatientPublicId: patient.PublicId,
+	})
+
+	return ListPatientRevisionsPayload{
+		Data: outRevisions,
+	}, nil
+}
+
+type RestorePatientRevisionParams struct {
+	ActionContext
+	PatientPublicId string
+	RevisionId      uint
+}
+
+type RestorePatientRevisionPayload struct {
+	PatientPublicId string `json:"id"`
+}
+
+// RestorePatientRevision sets the patient's details back to the revision's,
+// which is recorded as a new revision, so that the restore can be undone too.
+func (a *Actions) RestorePatientRevision(params RestorePatientRevisionParams) (RestorePatientRevisionPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionWritePatient) {
+		return RestorePatientRevisionPayload{}, ErrPermissionDenied{}
+	}
+
+	oldPatient, err := a.app.GetPatientByPublicId(params.PatientPublicId)
+	if err != nil {
+		return RestorePatientRevisionPayload{}, err
+	}
+
+	revision, err := a.app.GetPatientRevision(params.RevisionId)
+	if err != nil {
+		return RestorePatientRevisionPayload{}, err
+	}
+	if revision.PatientId != oldPatient.Id {
+		return RestorePatientRevisionPayload{}, app.ErrNotFound{
+			ResourceName: "patient_revision",
+		}
+	}
+
+	newPatient := revision.Patient()
+	newPatient.PublicId = oldPatient.PublicId
+
+	var updatedPatient models.Patient
+	err = a.app.WithTransaction(func(tx *app.App) error {
+		var err error
+		updatedPatient, err = updatePatientWithRevision(tx, oldPatient, newPatient, params.Account, revision.Id)
+		return err
+	})
+	if err != nil {
+		return RestorePatientRevisionPayload{}, err
+	}
+
+	before := new(Patient)
+	before.FromModel(oldPatient)
+	after := new(Patient)
+	after.FromModel(updatedPatient)
+
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "RestorePatientRevision",
+		Kind:            models.AuditEventKindWrite,
+		PatientPublicId: oldPatient.PublicId,
+		ResourceIds:     map[string]uint{"revision_id": revision.Id},
+		Before:          before,
+		After:           after,
+	})
+
+	return RestorePatientRevisionPayload{
+		PatientPublicId: oldPatient.PublicId,
+	}, nil
+}
@@ -0,0 +1,270 @@
+package actions
+
+import (
+	"math"
+	"shs/app/models"
+	"strings"
+	"time"
+)
+
+const (
+	ProphylaxisInfusionStatusTaken    = "taken"
+	ProphylaxisInfusionStatusMissed   = "missed"
+	ProphylaxisInfusionStatusUpcoming = "upcoming"
+)
+
+// daysBetween is the number of the clinic's calendar days from one day to
+// the other, where a day around a DST change isn't 24 hours long.
+func daysBetween(from, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
+
+// prophylaxisExpectedInfusions returns the days of the prophylaxis' expected
+// infusions in [from, to), where the schedule runs from its start date through
+// its end date.
+func prophylaxisExpectedInfusions(pp models.Prophylaxis, from, to time.Time) []time.Time {
+	scheduleKind, weekdays, intervalDays := pp.Schedule()
+	if scheduleKind == "" {
+		return nil
+	}
+
+	start := truncateToClinicDay(pp.StartsAt())
+	end := truncateToClinicDay(to)
+	if !pp.EndDate.IsZero() && pp.EndDate.Before(end) {
+		end = truncateToClinicDay(pp.EndDate).AddDate(0, 0, 1)
+	}
+	day := truncateToClinicDay(from)
+	if day.Before(start) {
+		day = start
+	}
+
+	infusions := make([]time.Time, 0)
+	for ; day.Before(end); day = day.AddDate(0, 0, 1) {
+		switch scheduleKind {
+		case models.ProphylaxisScheduleKindWeekdays:
+			if weekdays.Has(day.Weekday()) {
+				infusions = append(infusions, day)
+			}
+		case models.ProphylaxisScheduleKindEveryNDays:
+			daysSinceStart := daysBetween(start, day)
+			if daysSinceStart%intervalDays == 0 {
+				infusions = append(infusions, day)
+			}
+		}
+	}
+
+	return infusions
+}
+
+type ProphylaxisInfusion struct {
+	ExpectedAt time.Time `json:"expected_at"`
+	// TakenAt is the time of the first package used for the infusion, and it's
+	// zero if the infusion wasn't taken.
+	TakenAt time.Time `json:"taken_at"`
+	TakenIu int       `json:"taken_iu"`
+	Status  string    `json:"status"`
+}
+
+type ProphylaxisAdherence struct {
+	Prophylaxis Prophylaxis `json:"prophylaxis"`
+	Expected    int         `json:"expected"`
+	Taken       int         `json:"taken"`
+	Missed      int         `json:"missed"`
+	// AdherencePercent is the percent of taken infusions out of the ones that
+	// were due so far.
+	AdherencePercent float64               `json:"adherence_percent"`
+	Infusions        []ProphylaxisInfusion `json:"infusions"`
+}
+
+type PatientProphylaxisAdherence struct {
+	From        time.Time              `json:"from"`
+	To          time.Time              `json:"to"`
+	Prophylaxes []ProphylaxisAdherence `json:"prophylaxes"`
+}
+
+// takenInfusion is a day where the patient used packages of the prophylaxis'
+// factor, where several packages on the same day make a single infusion.
+type takenInfusion struct {
+	day     time.Time
+	takenAt time.Time
+	iu      int
+	matched bool
+}
+
+// computeProphylaxisAdherence matches each expected infusion with an unmatched
+// taken infusion on the same day, or a day before or after, as long as the
+// schedule's infusions aren't on consecutive days.
+func computeProphylaxisAdherence(expected []time.Time, taken []*takenInfusion, now time.Time) ([]ProphylaxisInfusion, int, int) {
+	toleranceDays := 1
+	for i := 1; i < len(expected); i++ {
+		if daysBetween(expected[i-1], expected[i]) <= 1 {
+			toleranceDays = 0
+			break
+		}
+	}
+
+	today := truncateToClinicDay(now)
+	infusions := make([]ProphylaxisInfusion, 0, len(expected))
+	takenCount, missedCount := 0, 0
+	for _, expectedAt := range expected {
+		infusion := ProphylaxisInfusion{
+			ExpectedAt: expectedAt,
+		}
+		for _, ti := range taken {
+			if ti.matched {
+				continue
+			}
+			diff := daysBetween(expectedAt, ti.day)
+			if diff < -toleranceDays || diff > toleranceDays {
+				continue
+			}
+			ti.matched = true
+			infusion.TakenAt = ti.takenAt
+			infusion.TakenIu = ti.iu
+			break
+		}
+
+		switch {
+		case !infusion.TakenAt.IsZero():
+			infusion.Status = ProphylaxisInfusionStatusTaken
+			takenCount++
+		case expectedAt.AddDate(0, 0, toleranceDays).Before(today):
+			infusion.Status = ProphylaxisInfusionStatusMissed
+			missedCount++
+		default:
+			infusion.Status = ProphylaxisInfusionStatusUpcoming
+		}
+		infusions = append(infusions, infusion)
+	}
+
+	return infusions, takenCount, missedCount
+}
+
+type GetPatientProphylaxisAdherenceParams struct {
+	ActionContext
+	PatientId string
+	// From defaults to 90 days before now.
+	From time.Time
+	// To defaults to 28 days after now, so that upcoming infusions are listed.
+	To time.Time
+}
+
+type GetPatientProphylaxisAdherencePayload struct {
+	Data PatientProphylaxisAdherence `json:"data"`
+}
+
+// GetPatientProphylaxisAdherence generates the expected infusions of each of
+// the patient's prophylaxes, and compares them with the packages the patient
+// marked as used.
+func (a *Actions) GetPatientProphylaxisAdherence(params GetPatientProphylaxisAdherenceParams) (GetPatientProphylaxisAdherencePayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadPatient) {
+		return GetPatientProphylaxisAdherencePayload{}, ErrPermissionDenied{}
+	}
+	if !params.Account.HasPermission(models.AccountPermissionReadProphylaxes) {
+		return GetPatientProphylaxisAdherencePayload{}, ErrPermissionDenied{}
+	}
+
+	patient, err := a.app.GetPatientByPublicId(params.PatientId)
+	if err != nil {
+		return GetPatientProphylaxisAdherencePayload{}, err
+	}
+
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "GetPatientProphylaxisAdherence",
+		Kind:            models.AuditEventKindRead,
+		PatientPublicId: params.PatientId,
+	})
+
+	return a.getProphylaxisAdherence(patient, params.From, params.To)
+}
+
+func (a *Actions) getProphylaxisAdherence(patient models.Patient, from, to time.Time) (GetPatientProphylaxisAdherencePayload, error) {
+	now := time.Now().UTC()
+	if from.IsZero() {
+		from = truncateToClinicDay(now).AddDate(0, 0, -90)
+	}
+	if to.IsZero() {
+		to = truncateToClinicDay(now).AddDate(0, 0, 28)
+	}
+	if !to.After(from) {
+		return GetPatientProphylaxisAdherencePayload{}, ErrValidation{Field: "to"}
+	}
+
+	prophylaxes, err := a.app.ListProphylaxesForPatient(patient.Id)
+	if err != nil {
+		return GetPatientProphylaxisAdherencePayload{}, err
+	}
+
+	// a day of slack on both ends for the infusions that were taken a day
+	// earlier or later than expected.
+	usedMeds, err := a.app.ListPatientUsedPrescribedMedicinesOnTimeRange(patient.Id, from.AddDate(0, 0, -1).UTC(), to.AddDate(0, 0, 1).UTC())
+	if err != nil {
+		return GetPatientProphylaxisAdherencePayload{}, err
+	}
+
+	medsIds := make([]uint, 0, len(usedMeds))
+	for _, pm := range usedMeds {
+		medsIds = append(medsIds, pm.MedicineId)
+	}
+
+	meds, err := a.app.ListMedicinesByIds(medsIds)
+	if err != nil {
+		return GetPatientProphylaxisAdherencePayload{}, err
+	}
+
+	medsMapped := make(map[uint]models.Medicine)
+	for _, med := range meds {
+		medsMapped[med.Id] = med
+	}
+
+	outProphylaxes := make([]ProphylaxisAdherence, 0, len(prophylaxes))
+	for _, pp := range prophylaxes {
+		// the used packages are matched by the factor and not by the medicine,
+		// since the prophylaxis' medicine is a single batch, and the patient
+		// gets packages from whichever batch is in stock.
+		taken := make([]*takenInfusion, 0)
+		for _, pm := range usedMeds {
+			med := medsMapped[pm.MedicineId]
+			if !strings.EqualFold(strings.TrimSpace(med.Factor), strings.TrimSpace(pp.Medicine.Factor)) {
+				continue
+			}
+
+			day := truncateToClinicDay(pm.UsedAt)
+			if len(taken) > 0 && taken[len(taken)-1].day.Equal(day) {
+				taken[len(taken)-1].iu += med.Dose
+				continue
+			}
+			taken = append(taken, &takenInfusion{
+				day:     day,
+				takenAt: pm.UsedAt,
+				iu:      med.Dose,
+			})
+		}
+
+		expected := prophylaxisExpectedInfusions(pp, from, to)
+		infusions, takenCount, missedCount := computeProphylaxisAdherence(expected, taken, now)
+
+		outProphylaxis := new(Prophylaxis)
+		outProphylaxis.FromModel(pp)
+		adherence := ProphylaxisAdherence{
+			Prophylaxis: *outProphylaxis,
+			Expected:    len(expected),
+			Taken:       takenCount,
+			Missed:      missedCount,
+			Infusions:   infusions,
+		}
+		if due := takenCount + missedCount; due > 0 {
+			adherence.AdherencePercent = float64(takenCount) / float64(due) * 100
+		}
+
+		outProphylaxes = append(outProphylaxes, adherence)
+	}
+
+	return GetPatientProphylaxisAdherencePayload{
+		Data: PatientProphylaxisAdherence{
+			From:        from,
+			To:          to,
+			Prophylaxes: outProphylaxes,
+		},
+	}, nil
+}
@@ -41,20 +41,39 @@ func ProphylaxisFrequencyNumberToString(num float32) string {
 }
 
 type Prophylaxis struct {
-	Id                 uint      `json:"id"`
-	Title              string    `json:"title"`
-	FrequencyPerDays   string    `json:"frequency"`
-	EndDate            time.Time `json:"end_date"`
-	MedicineId         uint      `json:"medicine_id,omitempty"`
-	PrescribedMedicine Medicine  `json:"prescribed_medicine"`
-	MedicineAmount     int       `json:"medicine_amount"`
-	Chosen             bool      `json:"chosen"`
+	Id    uint   `json:"id"`
+	Title string `json:"title"`
+	// FrequencyPerDays is only set for prophylaxes that were created before the
+	// explicit schedules.
+	FrequencyPerDays   string         `json:"frequency"`
+	ScheduleKind       string         `json:"schedule_kind"`
+	Weekdays           []time.Weekday `json:"weekdays"`
+	IntervalDays       int            `json:"interval_days"`
+	DoseIu             int            `json:"dose_iu"`
+	StartDate          time.Time      `json:"start_date"`
+	EndDate            time.Time      `json:"end_date"`
+	MedicineId         uint           `json:"medicine_id,omitempty"`
+	PrescribedMedicine Medicine       `json:"prescribed_medicine"`
+	MedicineAmount     int            `json:"medicine_amount"`
+	Chosen             bool           `json:"chosen"`
 }
 
 func (pp *Prophylaxis) FromModel(p models.Prophylaxis) {
+	scheduleKind, weekdays, intervalDays := p.Schedule()
+
 	(*pp).Id = p.Id
 	(*pp).Title = p.Title
 	(*pp).FrequencyPerDays = prophylaxisFrequencyMapperHuh[p.FrequencyPerDays]
+	(*pp).ScheduleKind = string(scheduleKind)
+	(*pp).Weekdays = make([]time.Weekday, 0, 7)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if weekdays.Has(day) {
+			(*pp).Weekdays = append((*pp).Weekdays, day)
+		}
+	}
+	(*pp).IntervalDays = intervalDays
+	(*pp).DoseIu = p.DoseIu
+	(*pp).StartDate = p.StartsAt()
 	(*pp).EndDate = p.EndDate
 	(*pp).MedicineAmount = p.MedicineAmount
 	med := new(Medicine)
@@ -64,14 +83,65 @@ func (pp *Prophylaxis) FromModel(p models.Prophylaxis) {
 }
 
 func (pp Prophylaxis) IntoModel() models.Prophylaxis {
-	return models.Prophylaxis{
+	p := models.Prophylaxis{
 		Id:               pp.Id,
 		Title:            pp.Title,
 		FrequencyPerDays: prophylaxisFrequencyMapper[pp.FrequencyPerDays],
+		ScheduleKind:     models.ProphylaxisScheduleKind(pp.ScheduleKind),
+		Weekdays:         models.NewProphylaxisWeekdays(pp.Weekdays...),
+		IntervalDays:     pp.IntervalDays,
+		DoseIu:           pp.DoseIu,
+		StartDate:        pp.StartDate,
 		EndDate:          pp.EndDate,
 		MedicineAmount:   pp.MedicineAmount,
 		MedicineId:       pp.MedicineId,
 	}
+
+	// the frequency is still set for explicit schedules, so that the old
+	// readers of it don't break.
+	switch p.ScheduleKind {
+	case models.ProphylaxisScheduleKindWeekdays:
+		p.FrequencyPerDays = float32(len(pp.Weekdays)) / 7
+	case models.ProphylaxisScheduleKindEveryNDays:
+		if pp.IntervalDays > 0 {
+			p.FrequencyPerDays = 1 / float32(pp.IntervalDays)
+		}
+	}
+
+	return p
+}
+
+func (pp Prophylaxis) Validate() error {
+	switch models.ProphylaxisScheduleKind(pp.ScheduleKind) {
+	case models.ProphylaxisScheduleKindWeekdays:
+		if len(pp.Weekdays) == 0 {
+			return ErrValidation{Field: "weekdays"}
+		}
+		for _, day := range pp.Weekdays {
+			if day < time.Sunday || day > time.Saturday {
+				return ErrValidation{Field: "weekdays"}
+			}
+		}
+	case models.ProphylaxisScheduleKindEveryNDays:
+		if pp.IntervalDays < 1 {
+			return ErrValidation{Field: "interval_days"}
+		}
+	case "":
+		if _, ok := prophylaxisFrequencyMapper[pp.FrequencyPerDays]; !ok {
+			return ErrValidation{Field: "schedule_kind"}
+		}
+	default:
+		return ErrValidation{Field: "schedule_kind"}
+	}
+
+	if pp.DoseIu < 0 {
+		return ErrValidation{Field: "dose_iu"}
+	}
+	if !pp.StartDate.IsZero() && !pp.EndDate.IsZero() && pp.EndDate.Before(pp.StartDate) {
+		return ErrValidation{Field: "end_date"}
+	}
+
+	return nil
 }
 
 type CreatePatientProphylaxisParams struct {
@@ -96,14 +166,29 @@ func (a *Actions) CreatePatientProphylaxis(params CreatePatientProphylaxisParams
 		return CreatePatientProphylaxisPayload{}, err
 	}
 
+	err = params.Prophylaxis.Validate()
+	if err != nil {
+		return CreatePatientProphylaxisPayload{}, err
+	}
+
 	je := params.Prophylaxis.IntoModel()
 	je.PatientId = patient.Id
+	if je.StartDate.IsZero() {
+		je.StartDate = time.Now().UTC()
+	}
 
-	_, err = a.app.CreateProphylaxis(je)
+	je, err = a.app.CreateProphylaxis(je)
 	if err != nil {
 		return CreatePatientProphylaxisPayload{}, err
 	}
 
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "CreatePatientProphylaxis",
+		Kind:            models.AuditEventKindWrite,
+		PatientPublicId: params.PatientId,
+		ResourceIds:     map[string]uint{"prophylaxis_id": je.Id},
+	})
+
 	return CreatePatientProphylaxisPayload{}, nil
 }
 
@@ -141,6 +226,12 @@ func (a *Actions) ListPatientProphylaxes(params ListPatientProphylaxesParams) (L
 		outProphylaxes = append(outProphylaxes, *outPP)
 	}
 
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "ListPatientProphylaxes",
+		Kind:            models.AuditEventKindRead,
+		PatientPublicId: params.PatientId,
+	})
+
 	return ListPatientProphylaxesPayload{
 		Data: outProphylaxes,
 	}, nil
@@ -177,6 +268,13 @@ func (a *Actions) EndPatientProphylaxis(params EndPatientProphylaxisParams) (End
 	outUpdated := new(Prophylaxis)
 	outUpdated.FromModel(updated)
 
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "EndPatientProphylaxis",
+		Kind:            models.AuditEventKindWrite,
+		PatientPublicId: params.PatientId,
+		ResourceIds:     map[string]uint{"prophylaxis_id": params.ProphylaxisId},
+	})
+
 	return EndPatientProphylaxisPayload{
 		Updated: *outUpdated,
 	}, nil
@@ -213,6 +311,13 @@ func (a *Actions) MarkPatientProphylaxisAsChosen(params MarkPatientProphylaxisAs
 	outUpdated := new(Prophylaxis)
 	outUpdated.FromModel(updated)
 
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "MarkPatientProphylaxisAsChosen",
+		Kind:            models.AuditEventKindWrite,
+		PatientPublicId: params.PatientId,
+		ResourceIds:     map[string]uint{"prophylaxis_id": params.ProphylaxisId},
+	})
+
 	return MarkPatientProphylaxisAsChosenPayload{
 		Updated: *outUpdated,
 	}, nil
@@ -245,5 +350,12 @@ func (a *Actions) DeletePatientPropylaxis(params DeletePatientPropylaxisParams)
 		return DeletePatientPropylaxisPayload{}, err
 	}
 
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "DeletePatientPropylaxis",
+		Kind:            models.AuditEventKindWrite,
+		PatientPublicId: params.PatientId,
+		ResourceIds:     map[string]uint{"prophylaxis_id": params.ProphylaxisId},
+	})
+
 	return DeletePatientPropylaxisPayload{}, nil
 }
@@ -0,0 +1,380 @@
+package actions
+
+import (
+	"errors"
+	"shs/app"
+	"shs/app/models"
+	"time"
+)
+
+// Flags of a blood test result's field value, where a normal value has no flag.
+const (
+	BloodTestFlagLow          = "low"
+	BloodTestFlagHigh         = "high"
+	BloodTestFlagCriticalLow  = "critical_low"
+	BloodTestFlagCriticalHigh = "critical_high"
+)
+
+type ReferenceRange struct {
+	Id           uint    `json:"id"`
+	Sex          string  `json:"sex"`
+	MinAgeMonths int     `json:"min_age_months"`
+	MaxAgeMonths int     `json:"max_age_months"`
+	Low          float64 `json:"low"`
+	High         float64 `json:"high"`
+	CriticalLow  float64 `json:"critical_low"`
+	CriticalHigh float64 `json:"critical_high"`
+}
+
+func (rr *ReferenceRange) FromModel(referenceRange models.BloodTestReferenceRange) {
+	(*rr) = ReferenceRange{
+		Id:           referenceRange.Id,
+		Sex:          string(referenceRange.Sex),
+		MinAgeMonths: referenceRange.MinAgeMonths,
+		MaxAgeMonths: referenceRange.MaxAgeMonths,
+		Low:          referenceRange.Low,
+		High:         referenceRange.High,
+		CriticalLow:  referenceRange.CriticalLow,
+		CriticalHigh: referenceRange.CriticalHigh,
+	}
+}
+
+func (rr ReferenceRange) IntoModel() models.BloodTestReferenceRange {
+	return models.BloodTestReferenceRange{
+		Sex:          models.ReferenceRangeSex(rr.Sex),
+		MinAgeMonths: rr.MinAgeMonths,
+		MaxAgeMonths: rr.MaxAgeMonths,
+		Low:          rr.Low,
+		High:         rr.High,
+		CriticalLow:  rr.CriticalLow,
+		CriticalHigh: rr.CriticalHigh,
+	}
+}
+
+func (rr ReferenceRange) Validate() error {
+	switch models.ReferenceRangeSex(rr.Sex) {
+	case models.ReferenceRangeSexAny, models.ReferenceRangeSexMale, models.ReferenceRangeSexFemale:
+	default:
+		return ErrValidation{Field: "sex"}
+	}
+
+	if rr.MinAgeMonths < 0 {
+		return ErrValidation{Field: "min_age_months"}
+	}
+	if rr.MaxAgeMonths != 0 && rr.MaxAgeMonths <= rr.MinAgeMonths {
+		return ErrValidation{Field: "max_age_months"}
+	}
+	if rr.High <= rr.Low {
+		return ErrValidation{Field: "high"}
+	}
+	if rr.CriticalLow != 0 && rr.CriticalLow > rr.Low {
+		return ErrValidation{Field: "critical_low"}
+	}
+	if rr.CriticalHigh != 0 && rr.CriticalHigh < rr.High {
+		return ErrValidation{Field: "critical_high"}
+	}
+
+	return nil
+}
+
+// ageInMonths returns the completed months between the date of birth and at.
+func ageInMonths(dateOfBirth, at time.Time) int {
+	months := (at.Year()-dateOfBirth.Year())*12 + int(at.Month()-dateOfBirth.Month())
+	if at.Day() < dateOfBirth.Day() {
+		months--
+	}
+
+	return max(months, 0)
+}
+
+// selectReferenceRange picks the field's reference range for a patient, where
+// a sex specific range is preferred over a range for any sex, and the field's
+// min and max values are used when none of its ranges match the patient.
+func selectReferenceRange(field models.BloodTestField, ageMonths int, male bool) (models.BloodTestReferenceRange, bool) {
+	sex := models.ReferenceRangeSexFemale
+	if male {
+		sex = models.ReferenceRangeSexMale
+	}
+
+	var anySexRange models.BloodTestReferenceRange
+	anySexRangeFound := false
+	for _, rr := range field.ReferenceRanges {
+		if ageMonths < rr.MinAgeMonths || (rr.MaxAgeMonths != 0 && ageMonths >= rr.MaxAgeMonths) {
+			continue
+		}
+		if rr.Sex == sex {
+			return rr, true
+		}
+		if rr.Sex == models.ReferenceRangeSexAny && !anySexRangeFound {
+			anySexRange = rr
+			anySexRangeFound = true
+		}
+	}
+	if anySexRangeFound {
+		return anySexRange, true
+	}
+
+	if field.MaxValueString == "" || field.MaxValueNumber <= field.MinValueNumber {
+		return models.BloodTestReferenceRange{}, false
+	}
+
+	return models.BloodTestReferenceRange{
+		Low:  field.MinValueNumber,
+		High: field.MaxValueNumber,
+	}, true
+}
+
+func bloodTestValueFlag(rr models.BloodTestReferenceRange, value float64) string {
+	switch {
+	case rr.CriticalLow != 0 && value <= rr.CriticalLow:
+		return BloodTestFlagCriticalLow
+	case rr.CriticalHigh != 0 && value >= rr.CriticalHigh:
+		return BloodTestFlagCriticalHigh
+	case value < rr.Low:
+		return BloodTestFlagLow
+	case value > rr.High:
+		return BloodTestFlagHigh
+	default:
+		return ""
+	}
+}
+
+// bloodTestsIndex looks up the blood tests and their fields of the results,
+// so that results are named and flagged without going over the blood tests
+// for every result.
+type bloodTestsIndex struct {
+	bloodTests map[uint]models.BloodTest
+	fields     map[uint]models.BloodTestField
+}
+
+func newBloodTestsIndex(bloodTests []models.BloodTest) bloodTestsIndex {
+	idx := bloodTestsIndex{
+		bloodTests: make(map[uint]models.BloodTest),
+		fields:     make(map[uint]models.BloodTestField),
+	}
+	for _, bt := range bloodTests {
+		idx.bloodTests[bt.Id] = bt
+		for _, field := range bt.Fields {
+			idx.fields[field.Id] = field
+		}
+	}
+
+	return idx
+}
+
+// result converts a patient's blood test result, and flags its fields using
+// the patient's age when the test was done.
+func (idx bloodTestsIndex) result(btr models.BloodTestResult, dateOfBirth time.Time, male bool) BloodTestResult {
+	ageMonths := ageInMonths(dateOfBirth, btr.TestedAt)
+	abnormal := false
+
+	fields := make([]BloodTestFilledField, 0, len(btr.FilledFields))
+	for _, filledField := range btr.FilledFields {
+		field := idx.fields[filledField.BloodTestFieldId]
+		outField := BloodTestFilledField{
+			BloodTestFieldId: filledField.BloodTestFieldId,
+			Name:             field.Name,
+			LoincCode:        field.LoincCode,
+			Unit:             field.Unit,
+			ValueNumber:      filledField.ValueNumber,
+			ValueString:      filledField.ValueString,
+			Computed:         field.Formula != "",
+			OriginalValue:    filledField.OriginalValue,
+			OriginalUnit:     filledField.OriginalUnit,
+		}
+
+		rr, hasRange := selectReferenceRange(field, ageMonths, male)
+		value, isNumber := filledField.Number()
+		if hasRange && isNumber {
+			outField.HasReferenceRange = true
+			outField.ReferenceLow = rr.Low
+			outField.ReferenceHigh = rr.High
+			outField.Flag = bloodTestValueFlag(rr, value)
+			abnormal = abnormal || outField.Flag != ""
+		}
+
+		fields = append(fields, outField)
+	}
+
+	return BloodTestResult{
+		Id:             btr.Id,
+		BloodTestId:    btr.BloodTestId,
+		Name:           idx.bloodTests[btr.BloodTestId].Name,
+		FilledFields:   fields,
+		Pending:        btr.Pending,
+		Abnormal:       abnormal,
+		DisplayInBrief: idx.bloodTests[btr.BloodTestId].DisplayInBrief,
+		CreatedAt:      btr.CreatedAt,
+		TestedAt:       btr.TestedAt,
+	}
+}
+
+type CreateBloodTestReferenceRangeParams struct {
+	ActionContext
+	BloodTestId      uint
+	BloodTestFieldId uint
+	ReferenceRange   ReferenceRange `json:"reference_range"`
+}
+
+type CreateBloodTestReferenceRangePayload struct {
+	Data ReferenceRange `json:"data"`
+}
+
+func (a *Actions) CreateBloodTestReferenceRange(params CreateBloodTestReferenceRangeParams) (CreateBloodTestReferenceRangePayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionWriteBloodTest) {
+		return CreateBloodTestReferenceRangePayload{}, ErrPermissionDenied{}
+	}
+
+	err := params.ReferenceRange.Validate()
+	if err != nil {
+		return CreateBloodTestReferenceRangePayload{}, err
+	}
+
+	bloodTest, err := a.app.GetBloodTest(params.BloodTestId)
+	if err != nil {
+		return CreateBloodTestReferenceRangePayload{}, err
+	}
+
+	fieldFound := false
+	for _, field := range bloodTest.Fields {
+		fieldFound = fieldFound || field.Id == params.BloodTestFieldId
+	}
+	if !fieldFound {
+		return CreateBloodTestReferenceRangePayload{}, ErrValidation{Field: "blood_test_field_id"}
+	}
+
+	rr := params.ReferenceRange.IntoModel()
+	rr.BloodTestFieldId = params.BloodTestFieldId
+
+	rr, err = a.app.CreateBloodTestReferenceRange(rr)
+	if err != nil {
+		return CreateBloodTestReferenceRangePayload{}, err
+	}
+
+	outRange := new(ReferenceRange)
+	outRange.FromModel(rr)
+
+	return CreateBloodTestReferenceRangePayload{
+		Data: *outRange,
+	}, nil
+}
+
+type DeleteBloodTestReferenceRangeParams struct {
+	ActionContext
+	ReferenceRangeId uint
+}
+
+type DeleteBloodTestReferenceRangePayload struct {
+}
+
+func (a *Actions) DeleteBloodTestReferenceRange(params DeleteBloodTestReferenceRangeParams) (DeleteBloodTestReferenceRangePayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionWriteBloodTest) {
+		return DeleteBloodTestReferenceRangePayload{}, ErrPermissionDenied{}
+	}
+
+	err := a.app.DeleteBloodTestReferenceRange(params.ReferenceRangeId)
+	if err != nil {
+		return DeleteBloodTestReferenceRangePayload{}, err
+	}
+
+	return DeleteBloodTestReferenceRangePayload{}, nil
+}
+
+type OutOfRangeBloodTestResult struct {
+	Patient Patient `json:"patient"`
+	// Result has only the result's fields that are out of their reference ranges.
+	Result BloodTestResult `json:"result"`
+}
+
+type ListOutOfRangeBloodTestResultsParams struct {
+	ActionContext
+	// Days is how many days back to look for results, and it defaults to 30.
+	Days int
+}
+
+type ListOutOfRangeBloodTestResultsPayload struct {
+	Data []OutOfRangeBloodTestResult `json:"data"`
+}
+
+// ListOutOfRangeBloodTestResults lists the results that were tested in the
+// last given days and have values out of their reference ranges, ordered
+// by their test time.
+func (a *Actions) ListOutOfRangeBloodTestResults(params ListOutOfRangeBloodTestResultsParams) (ListOutOfRangeBloodTestResultsPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadPatient) {
+		return ListOutOfRangeBloodTestResultsPayload{}, ErrPermissionDenied{}
+	}
+	if !params.Account.HasPermission(models.AccountPermissionReadBloodTest) {
+		return ListOutOfRangeBloodTestResultsPayload{}, ErrPermissionDenied{}
+	}
+
+	if params.Days == 0 {
+		params.Days = 30
+	}
+	if params.Days < 0 {
+		return ListOutOfRangeBloodTestResultsPayload{}, ErrValidation{Field: "days"}
+	}
+
+	now := time.Now().UTC()
+	results, err := a.app.ListBloodTestResultsOnTimeRange(now.AddDate(0, 0, -params.Days), now)
+	if err != nil {
+		return ListOutOfRangeBloodTestResultsPayload{}, err
+	}
+
+	bloodTests, err := a.app.ListAllBloodTests()
+	if err != nil {
+		return ListOutOfRangeBloodTestResultsPayload{}, err
+	}
+	idx := newBloodTestsIndex(bloodTests)
+
+	patients := make(map[uint]models.Patient)
+	outResults := make([]OutOfRangeBloodTestResult, 0)
+	for _, btr := range results {
+		if btr.Pending {
+			continue
+		}
+
+		patient, ok := patients[btr.PatientId]
+		if !ok {
+			// the results that were left of a deleted patient are skipped,
+			// so that they don't fail the other patients' list.
+			patient, err = a.app.GetPatientById(btr.PatientId)
+			var notFoundErr *app.ErrNotFound
+			if errors.As(err, &notFoundErr) {
+				continue
+			}
+			if err != nil {
+				return ListOutOfRangeBloodTestResultsPayload{}, err
+			}
+			patients[btr.PatientId] = patient
+		}
+
+		outResult := idx.result(btr, patient.DateOfBirth, patient.Gender)
+		if !outResult.Abnormal {
+			continue
+		}
+
+		flaggedFields := make([]BloodTestFilledField, 0, len(outResult.FilledFields))
+		for _, field := range outResult.FilledFields {
+			if field.Flag != "" {
+				flaggedFields = append(flaggedFields, field)
+			}
+		}
+		outResult.FilledFields = flaggedFields
+
+		outPatient := new(Patient)
+		outPatient.FromModel(patient)
+		outResults = append(outResults, OutOfRangeBloodTestResult{
+			Patient: *outPatient,
+			Result:  outResult,
+		})
+	}
+
+	a.audit(params.ActionContext, auditEntry{
+		Action: "ListOutOfRangeBloodTestResults",
+		Kind:   models.AuditEventKindRead,
+	})
+
+	return ListOutOfRangeBloodTestResultsPayload{
+		Data: outResults,
+	}, nil
+}
@@ -3,4 +3,6 @@ package actions
 type ActionContext struct {
 	Account      Account
 	SessionToken string
+	ClientIp     string
+	UserAgent    string
 }
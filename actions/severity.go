@@ -0,0 +1,215 @@
+package actions
+
+import (
+	"shs/app/models"
+	"strings"
+	"time"
+)
+
+const (
+	HemophiliaTypeA = "A"
+	HemophiliaTypeB = "B"
+)
+
+const (
+	HemophiliaSeveritySevere   = "severe"
+	HemophiliaSeverityModerate = "moderate"
+	HemophiliaSeverityMild     = "mild"
+)
+
+// Names of the factors' fields, which are looked up by their LOINC codes, and
+// by these names when they aren't coded yet, where the values are in
+// percentage of the normal activity.
+const (
+	factorVIIIFieldName = "Factor - VIII"
+	factorIXFieldName   = "Factor - IX"
+)
+
+// HemophiliaSeverity is the severity derived from the patient's factor levels,
+// with the factor result used as evidence, where Severity is empty when the
+// patient has no factor result below the mild hemophilia limit.
+type HemophiliaSeverity struct {
+	Type              string    `json:"type"`
+	Severity          string    `json:"severity"`
+	FactorLevel       float64   `json:"factor_level"`
+	BloodTestResultId uint      `json:"blood_test_result_id"`
+	BloodTestName     string    `json:"blood_test_name"`
+	TestedAt          time.Time `json:"tested_at"`
+	// Contradicts is set when the latest diagnosis that states a severity or a
+	// hemophilia type doesn't match the derived one.
+	Contradicts       bool   `json:"contradicts"`
+	RecordedDiagnosis string `json:"recorded_diagnosis"`
+}
+
+func (hs HemophiliaSeverity) Classified() bool {
+	return hs.Severity != ""
+}
+
+// String formats the severity as in "Severe hemophilia A".
+func (hs HemophiliaSeverity) String() string {
+	if !hs.Classified() {
+		return ""
+	}
+
+	return strings.ToUpper(hs.Severity[:1]) + hs.Severity[1:] + " hemophilia " + hs.Type
+}
+
+// classifyFactorLevel follows the ISTH classification, where a factor level
+// below 1% is severe, 1-5% is moderate, and above 5% and below 40% is mild.
+func classifyFactorLevel(level float64) (string, bool) {
+	switch {
+	case level < 0:
+		return "", false
+	case level < 1:
+		return HemophiliaSeveritySevere, true
+	case level <= 5:
+		return HemophiliaSeverityModerate, true
+	case level < 40:
+		return HemophiliaSeverityMild, true
+	default:
+		return "", false
+	}
+}
+
+// latestFactorLevel finds the latest non pending result of the factor's field
+// that has a numeric value.
+func latestFactorLevel(results []BloodTestResult, loincCode, legacyFieldName string) (BloodTestResult, float64, bool) {
+	var latest BloodTestResult
+	var level float64
+	found := false
+	for _, btr := range results {
+		if btr.Pending {
+			continue
+		}
+		if found && !btr.TestedAt.After(latest.TestedAt) {
+			continue
+		}
+
+		for _, field := range btr.FilledFields {
+			if !isLoincField(loincCode, legacyFieldName, field.LoincCode, field.Name) {
+				continue
+			}
+			value, ok := field.Number()
+			if !ok {
+				continue
+			}
+			latest, level, found = btr, value, true
+		}
+	}
+
+	return latest, level, found
+}
+
+// classifyHemophiliaSeverity derives the severity from the latest factor VIII
+// and factor IX results, where the lower factor level is used when both are
+// low, and checks it against the recorded diagnoses.
+func classifyHemophiliaSeverity(results []BloodTestResult, diagnoses []DiagnosisResult) HemophiliaSeverity {
+	var hs HemophiliaSeverity
+	for _, factor := range []struct {
+		hemophiliaType string
+		loincCode      string
+		fieldName      string
+	}{
+		{HemophiliaTypeA, models.LoincFactorViiiActivity, factorVIIIFieldName},
+		{HemophiliaTypeB, models.LoincFactorIxActivity, factorIXFieldName},
+	} {
+		btr, level, ok := latestFactorLevel(results, factor.loincCode, factor.fieldName)
+		if !ok {
+			continue
+		}
+		severity, ok := classifyFactorLevel(level)
+		if !ok || (hs.Classified() && hs.FactorLevel <= level) {
+			continue
+		}
+
+		hs = HemophiliaSeverity{
+			Type:              factor.hemophiliaType,
+			Severity:          severity,
+			FactorLevel:       level,
+			BloodTestResultId: btr.Id,
+			BloodTestName:     btr.Name,
+			TestedAt:          btr.TestedAt,
+		}
+	}
+
+	if !hs.Classified() {
+		return hs
+	}
+
+	var recorded DiagnosisResult
+	var recordedType, recordedSeverity string
+	for _, dr := range diagnoses {
+		diagnosisType, diagnosisSeverity := diagnosisHemophilia(dr.Diagnosis)
+		if diagnosisType == "" && diagnosisSeverity == "" {
+			continue
+		}
+		if recorded.Id != 0 && !dr.DiagnosedAt.After(recorded.DiagnosedAt) {
+			continue
+		}
+		recorded, recordedType, recordedSeverity = dr, diagnosisType, diagnosisSeverity
+	}
+	if recorded.Id == 0 {
+		return hs
+	}
+
+	hs.RecordedDiagnosis = recorded.GroupName + " - " + recorded.Title
+	hs.Contradicts = (recordedType != "" && recordedType != hs.Type) ||
+		(recordedSeverity != "" && recordedSeverity != hs.Severity)
+
+	return hs
+}
+
+// diagnosisHemophilia reads the hemophilia type and severity a diagnosis
+// states, using its ICD-11 code for the type, or its names when the code is
+// missing, where either of them is empty when the diagnosis doesn't state it.
+func diagnosisHemophilia(d Diagnosis) (hemophiliaType, severity string) {
+	switch icd11 := strings.ToUpper(strings.TrimSpace(d.ICD11)); {
+	case strings.HasPrefix(icd11, "3B10"):
+		hemophiliaType = HemophiliaTypeA
+	case strings.HasPrefix(icd11, "3B11"):
+		hemophiliaType = HemophiliaTypeB
+	}
+
+	names := strings.ToLower(strings.Join([]string{d.GroupName, d.Title, d.AKA}, " "))
+	if hemophiliaType == "" {
+		switch {
+		case strings.Contains(names, "hemophilia a"), strings.Contains(names, "haemophilia a"):
+			hemophiliaType = HemophiliaTypeA
+		case strings.Contains(names, "hemophilia b"), strings.Contains(names, "haemophilia b"):
+			hemophiliaType = HemophiliaTypeB
+		}
+	}
+
+	switch {
+	case strings.Contains(names, "severe"), strings.Contains(names, "شديد"):
+		severity = HemophiliaSeveritySevere
+	case strings.Contains(names, "moderate"), strings.Contains(names, "متوسط"):
+		severity = HemophiliaSeverityModerate
+	case strings.Contains(names, "mild"), strings.Contains(names, "خفيف"):
+		severity = HemophiliaSeverityMild
+	}
+
+	return hemophiliaType, severity
+}
+
+// WithSeverity must be called after the patient's blood test results and
+// diagnoses are set.
+func (p *Patient) WithSeverity() {
+	(*p).Severity = classifyHemophiliaSeverity(p.BloodTestResults, p.Diagnoses)
+}
+
+// getSeverityWarning reclassifies the patient's severity after a blood test
+// result was added, and returns the severity only when that result is the
+// evidence and it contradicts the recorded diagnosis.
+func (a *Actions) getSeverityWarning(patientPublicId string, bloodTestResultId uint) (*HemophiliaSeverity, error) {
+	patient, err := a.getFullPatientByPublicId(patientPublicId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !patient.Severity.Contradicts || patient.Severity.BloodTestResultId != bloodTestResultId {
+		return nil, nil
+	}
+
+	return &patient.Severity, nil
+}
@@ -0,0 +1,140 @@
+package actions
+
+import (
+	"shs/app/models"
+	"slices"
+	"time"
+)
+
+type StatisticsCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func (s *StatisticsCount) FromModel(count models.StatisticsCount) {
+	(*s) = StatisticsCount{
+		Name:  count.Name,
+		Count: count.Count,
+	}
+}
+
+type Statistics struct {
+	StartDate       time.Time         `json:"start_date"`
+	EndDate         time.Time         `json:"end_date"`
+	TotalPatients   int               `json:"total_patients"`
+	TotalVisits     int               `json:"total_visits"`
+	DiagnosisGroups []StatisticsCount `json:"diagnosis_groups"`
+	Genders         []StatisticsCount `json:"genders"`
+	AgeBands        []StatisticsCount `json:"age_bands"`
+	Governorates    []StatisticsCount `json:"governorates"`
+	Viruses         []StatisticsCount `json:"viruses"`
+	VisitReasons    []StatisticsCount `json:"visit_reasons"`
+}
+
+type GetStatisticsParams struct {
+	ActionContext
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
+
+type GetStatisticsPayload struct {
+	Data Statistics `json:"data"`
+}
+
+// GetStatistics aggregates patients who were registered or visited in the given
+// date range, a zero StartDate means since forever and a zero EndDate means now.
+func (a *Actions) GetStatistics(params GetStatisticsParams) (GetStatisticsPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadPatient) {
+		return GetStatisticsPayload{}, ErrPermissionDenied{}
+	}
+
+	if params.EndDate.IsZero() {
+		params.EndDate = time.Now().UTC()
+	}
+	if params.StartDate.After(params.EndDate) {
+		return GetStatisticsPayload{}, ErrValidation{Field: "start_date"}
+	}
+
+	totalPatients, err := a.app.CountPatientsOnTimeRange(params.StartDate, params.EndDate)
+	if err != nil {
+		return GetStatisticsPayload{}, err
+	}
+
+	diagnosisGroups, err := a.app.CountPatientsByDiagnosisGroup(params.StartDate, params.EndDate)
+	if err != nil {
+		return GetStatisticsPayload{}, err
+	}
+
+	genders, err := a.app.CountPatientsByGender(params.StartDate, params.EndDate)
+	if err != nil {
+		return GetStatisticsPayload{}, err
+	}
+
+	ageBands, err := a.app.CountPatientsByAgeBand(params.StartDate, params.EndDate)
+	if err != nil {
+		return GetStatisticsPayload{}, err
+	}
+
+	governorates, err := a.app.CountPatientsByResidencyGovernorate(params.StartDate, params.EndDate)
+	if err != nil {
+		return GetStatisticsPayload{}, err
+	}
+
+	viruses, err := a.app.CountPatientsByVirus(params.StartDate, params.EndDate)
+	if err != nil {
+		return GetStatisticsPayload{}, err
+	}
+
+	visitReasons, err := a.app.CountVisitsByReason(params.StartDate, params.EndDate)
+	if err != nil {
+		return GetStatisticsPayload{}, err
+	}
+
+	totalVisits := 0
+	for _, vr := range visitReasons {
+		totalVisits += vr.Count
+	}
+
+	outAgeBands := make([]StatisticsCount, 0, 5)
+	for _, band := range []string{models.AgeBand0To4, models.AgeBand5To13, models.AgeBand14To18, models.AgeBand19To44, models.AgeBand45Plus} {
+		outAgeBand := StatisticsCount{Name: band}
+		for _, ab := range ageBands {
+			if ab.Name == band {
+				outAgeBand.Count = ab.Count
+				break
+			}
+		}
+		outAgeBands = append(outAgeBands, outAgeBand)
+	}
+
+	return GetStatisticsPayload{
+		Data: Statistics{
+			StartDate:       params.StartDate,
+			EndDate:         params.EndDate,
+			TotalPatients:   totalPatients,
+			TotalVisits:     totalVisits,
+			DiagnosisGroups: mapStatisticsCounts(diagnosisGroups),
+			Genders:         mapStatisticsCounts(genders),
+			AgeBands:        outAgeBands,
+			Governorates:    mapStatisticsCounts(governorates),
+			Viruses:         mapStatisticsCounts(viruses),
+			VisitReasons:    mapStatisticsCounts(visitReasons),
+		},
+	}, nil
+}
+
+// mapStatisticsCounts converts the counts and sorts them by count descending.
+func mapStatisticsCounts(counts []models.StatisticsCount) []StatisticsCount {
+	outCounts := make([]StatisticsCount, 0, len(counts))
+	for _, c := range counts {
+		outCount := new(StatisticsCount)
+		outCount.FromModel(c)
+		outCounts = append(outCounts, *outCount)
+	}
+
+	slices.SortFunc(outCounts, func(ci, cj StatisticsCount) int {
+		return cj.Count - ci.Count
+	})
+
+	return outCounts
+}
@@ -0,0 +1,174 @@
+package actions
+
+import (
+	"shs/app"
+	"shs/app/models"
+	"slices"
+	"time"
+)
+
+type StockMovement struct {
+	Id                 uint      `json:"id"`
+	MedicineId         uint      `json:"medicine_id"`
+	Kind               string    `json:"kind"`
+	Delta              int       `json:"delta"`
+	AmountAfter        int       `json:"amount_after"`
+	AccountId          uint      `json:"account_id"`
+	AccountDisplayName string    `json:"account_display_name"`
+	VisitId            uint      `json:"visit_id"`
+	Note               string    `json:"note"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+func (sm *StockMovement) FromModel(movement models.StockMovement, account models.Account) {
+	(*sm) = StockMovement{
+		Id:                 movement.Id,
+		MedicineId:         movement.MedicineId,
+		Kind:               string(movement.Kind),
+		Delta:              movement.Delta,
+		AmountAfter:        movement.AmountAfter,
+		AccountId:          movement.AccountId,
+		AccountDisplayName: account.DisplayName,
+		VisitId:            movement.VisitId,
+		Note:               movement.Note,
+		CreatedAt:          movement.CreatedAt,
+	}
+}
+
+// recordStockMovement applies the movement's delta to the medicine's amount, and
+// appends the movement to the medicine's ledger, the medicine is expected to be
+// freshly read (preferably locked) in the same transaction as tx.
+func recordStockMovement(tx *app.App, medicine models.Medicine, movement models.StockMovement) error {
+	movement.MedicineId = medicine.Id
+	movement.AmountAfter = medicine.Amount + movement.Delta
+
+	err := tx.UpdateMedicineAmount(medicine.Id, movement.AmountAfter)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.CreateStockMovement(movement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type CreateMedicineStockMovementParams struct {
+	ActionContext
+	MedicineId uint   `json:"medicine_id"`
+	Kind       string `json:"kind"`
+	// Amount is the number of packages moved, and it's always positive, where
+	// its direction is decided by the movement's kind.
+	Amount int    `json:"amount"`
+	Note   string `json:"note"`
+}
+
+type CreateMedicineStockMovementPayload struct {
+}
+
+// CreateMedicineStockMovement records stock movements that don't have a
+// dedicated flow, i.e. received donations for an existing batch, returned
+// packages and expired or destroyed packages.
+//
+// Dispensing is done through check-ups, and corrections through UpdateMedicine.
+func (a *Actions) CreateMedicineStockMovement(params CreateMedicineStockMovementParams) (CreateMedicineStockMovementPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionWriteMedicine) {
+		return CreateMedicineStockMovementPayload{}, ErrPermissionDenied{}
+	}
+
+	if params.Amount <= 0 {
+		return CreateMedicineStockMovementPayload{}, ErrValidation{Field: "amount"}
+	}
+
+	var delta int
+	switch models.StockMovementKind(params.Kind) {
+	case models.StockMovementKindReceivedDonation, models.StockMovementKindReturned:
+		delta = params.Amount
+	case models.StockMovementKindExpiredDestroyed:
+		delta = -params.Amount
+	default:
+		return CreateMedicineStockMovementPayload{}, ErrValidation{Field: "kind"}
+	}
+
+	err := a.app.WithTransaction(func(tx *app.App) error {
+		meds, err := tx.LockMedicinesByIds([]uint{params.MedicineId})
+		if err != nil {
+			return err
+		}
+		if len(meds) == 0 {
+			return app.ErrNotFound{
+				ResourceName: "medicine",
+			}
+		}
+		med := meds[0]
+
+		if med.Amount+delta < 0 {
+			return ErrInsufficientMedicine{
+				MedicineName:    med.Name,
+				ExceedingAmount: params.Amount,
+				LeftPackages:    med.Amount,
+			}
+		}
+
+		return recordStockMovement(tx, med, models.StockMovement{
+			Kind:      models.StockMovementKind(params.Kind),
+			Delta:     delta,
+			AccountId: params.Account.Id,
+			Note:      params.Note,
+		})
+	})
+	if err != nil {
+		return CreateMedicineStockMovementPayload{}, err
+	}
+
+	return CreateMedicineStockMovementPayload{}, nil
+}
+
+type ListMedicineStockMovementsParams struct {
+	ActionContext
+	MedicineId uint `json:"medicine_id"`
+}
+
+type ListMedicineStockMovementsPayload struct {
+	Data []StockMovement `json:"data"`
+}
+
+// ListMedicineStockMovements returns the medicine's ledger, newest first.
+func (a *Actions) ListMedicineStockMovements(params ListMedicineStockMovementsParams) (ListMedicineStockMovementsPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadMedicine) {
+		return ListMedicineStockMovementsPayload{}, ErrPermissionDenied{}
+	}
+
+	_, err := a.app.GetMedicine(params.MedicineId)
+	if err != nil {
+		return ListMedicineStockMovementsPayload{}, err
+	}
+
+	movements, err := a.app.ListMedicineStockMovements(params.MedicineId)
+	if err != nil {
+		return ListMedicineStockMovementsPayload{}, err
+	}
+
+	accounts, err := a.app.ListAllAccounts()
+	if err != nil {
+		return ListMedicineStockMovementsPayload{}, err
+	}
+
+	accountsMapped := make(map[uint]models.Account)
+	for _, account := range accounts {
+		accountsMapped[account.Id] = account
+	}
+
+	outMovements := make([]StockMovement, 0, len(movements))
+	for _, movement := range slices.Backward(movements) {
+		outMovement := new(StockMovement)
+		outMovement.FromModel(movement, accountsMapped[movement.AccountId])
+		outMovements = append(outMovements, *outMovement)
+	}
+
+	return ListMedicineStockMovementsPayload{
+		Data: outMovements,
+	}, nil
+}
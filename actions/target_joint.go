@@ -0,0 +1,288 @@
+package actions
+
+import (
+	"errors"
+	"shs/app"
+	"shs/app/models"
+	"slices"
+	"time"
+)
+
+// The ISTH definition of a target joint, where a major joint becomes a target
+// joint after 3 or more spontaneous bleeds within 6 consecutive months, and
+// it stops being one after 12 consecutive months with 2 or less bleeds.
+const (
+	targetJointBleeds               = 3
+	targetJointWindowMonths         = 6
+	targetJointResolvedBleeds       = 2
+	targetJointResolvedWindowMonths = 12
+)
+
+type TargetJoint struct {
+	Joint  string `json:"joint"`
+	Active bool   `json:"active"`
+	// Since is the onset of the bleed that made the joint a target joint.
+	Since time.Time `json:"since"`
+	// ResolvedAt is zero while the joint is still a target joint.
+	ResolvedAt time.Time `json:"resolved_at"`
+	// Bleeds is the count of the joint's bleeds in the last 12 months.
+	Bleeds      int       `json:"bleeds"`
+	LastBleedAt time.Time `json:"last_bleed_at"`
+	// HjhsScore is the joint's total in the latest joints evaluation, where
+	// HjhsEvaluatedAt is zero when the joint wasn't evaluated.
+	HjhsScore       int       `json:"hjhs_score"`
+	HjhsEvaluatedAt time.Time `json:"hjhs_evaluated_at"`
+}
+
+func targetJointSites() []models.BleedingSite {
+	sites := make([]models.BleedingSite, 0, len(models.HjhsJoints))
+	for _, joint := range models.HjhsJoints {
+		sites = append(sites, models.BleedingSite(joint))
+	}
+
+	return sites
+}
+
+// computeTargetJoints replays each major joint's bleeds up to at, and returns
+// the joints that became target joints, where a joint that became a target
+// joint more than once is reported with its latest period.
+func computeTargetJoints(episodes []models.BleedingEpisode, evaluations []models.JointsEvaluation, at time.Time) []TargetJoint {
+	episodes = slices.Clone(episodes)
+	slices.SortFunc(episodes, func(a, b models.BleedingEpisode) int {
+		return a.OnsetAt.Compare(b.OnsetAt)
+	})
+
+	targetJoints := make([]TargetJoint, 0)
+	for _, joint := range models.HjhsJoints {
+		bleeds := make([]models.BleedingEpisode, 0)
+		for _, be := range episodes {
+			if be.Site == models.BleedingSite(joint) && !be.OnsetAt.After(at) {
+				bleeds = append(bleeds, be)
+			}
+		}
+
+		tj, ok := replayTargetJoint(bleeds, at)
+		if !ok {
+			continue
+		}
+
+		tj.Joint = string(joint)
+		tj.LastBleedAt = bleeds[len(bleeds)-1].OnsetAt
+		for _, be := range bleeds {
+			if be.OnsetAt.After(at.AddDate(0, -targetJointResolvedWindowMonths, 0)) {
+				tj.Bleeds++
+			}
+		}
+
+		targetJoints = append(targetJoints, tj)
+	}
+
+	setTargetJointsHjhsScores(targetJoints, evaluations, at)
+
+	return targetJoints
+}
+
+// setTargetJointsHjhsScores sets the joints' totals from the latest joints
+// evaluation up to at.
+func setTargetJointsHjhsScores(targetJoints []TargetJoint, evaluations []models.JointsEvaluation, at time.Time) {
+	var latestEvaluation models.JointsEvaluation
+	for _, je := range evaluations {
+		if !je.CreatedAt.After(at) && je.CreatedAt.After(latestEvaluation.CreatedAt) {
+			latestEvaluation = je
+		}
+	}
+	if latestEvaluation.CreatedAt.IsZero() {
+		return
+	}
+
+	for i := range targetJoints {
+		targetJoints[i].HjhsScore = latestEvaluation.JointTotal(models.Joint(targetJoints[i].Joint))
+		targetJoints[i].HjhsEvaluatedAt = latestEvaluation.CreatedAt
+	}
+}
+
+// replayTargetJoint goes over a joint's bleeds ordered by their onset, and
+// reports false when the joint never became a target joint.
+func replayTargetJoint(bleeds []models.BleedingEpisode, at time.Time) (TargetJoint, bool) {
+	var tj TargetJoint
+
+	// resolvedAt is the earliest time after the joint became a target joint
+	// with 12 months behind it that have 2 or less of the bleeds so far, since
+	// the count in the trailing 12 months doesn't go up until the next bleed.
+	resolvedAt := func(bleedsSoFar []models.BleedingEpisode) time.Time {
+		resolved := tj.Since.AddDate(0, targetJointResolvedWindowMonths, 0)
+		if len(bleedsSoFar) > targetJointResolvedBleeds {
+			lastOutOfWindow := bleedsSoFar[len(bleedsSoFar)-1-targetJointResolvedBleeds].OnsetAt.
+				AddDate(0, targetJointResolvedWindowMonths, 0)
+			if lastOutOfWindow.After(resolved) {
+				resolved = lastOutOfWindow
+			}
+		}
+
+		return resolved
+	}
+
+	for i, be := range bleeds {
+		if tj.Active {
+			resolved := resolvedAt(bleeds[:i])
+			if resolved.Before(be.OnsetAt) {
+				tj.Active = false
+				tj.ResolvedAt = resolved
+			}
+		}
+
+		if tj.Active || be.Cause != models.BleedingCauseSpontaneous {
+			continue
+		}
+
+		spontaneousBleeds := 0
+		windowStart := be.OnsetAt.AddDate(0, -targetJointWindowMonths, 0)
+		for _, prev := range bleeds[:i+1] {
+			if prev.Cause == models.BleedingCauseSpontaneous && prev.OnsetAt.After(windowStart) {
+				spontaneousBleeds++
+			}
+		}
+		if spontaneousBleeds >= targetJointBleeds {
+			tj.Active = true
+			tj.Since = be.OnsetAt
+			tj.ResolvedAt = time.Time{}
+		}
+	}
+
+	if tj.Since.IsZero() {
+		return TargetJoint{}, false
+	}
+
+	if tj.Active {
+		resolved := resolvedAt(bleeds)
+		if !resolved.After(at) {
+			tj.Active = false
+			tj.ResolvedAt = resolved
+		}
+	}
+
+	return tj, true
+}
+
+func (p *Patient) WithTargetJoints(episodes []models.BleedingEpisode, evaluations []models.JointsEvaluation) {
+	(*p).TargetJoints = computeTargetJoints(episodes, evaluations, time.Now().UTC())
+}
+
+type GetPatientTargetJointsParams struct {
+	ActionContext
+	PatientId string
+}
+
+type GetPatientTargetJointsPayload struct {
+	Data []TargetJoint `json:"data"`
+}
+
+func (a *Actions) GetPatientTargetJoints(params GetPatientTargetJointsParams) (GetPatientTargetJointsPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadJoints) {
+		return GetPatientTargetJointsPayload{}, ErrPermissionDenied{}
+	}
+
+	patient, err := a.app.GetPatientByPublicId(params.PatientId)
+	if err != nil {
+		return GetPatientTargetJointsPayload{}, err
+	}
+
+	episodes, err := a.app.ListPatientBleedingEpisodes(patient.Id)
+	if err != nil {
+		return GetPatientTargetJointsPayload{}, err
+	}
+
+	evaluations, err := a.app.ListJointEvaluationsForPatient(patient.Id)
+	if err != nil {
+		return GetPatientTargetJointsPayload{}, err
+	}
+
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "GetPatientTargetJoints",
+		Kind:            models.AuditEventKindRead,
+		PatientPublicId: params.PatientId,
+	})
+
+	return GetPatientTargetJointsPayload{
+		Data: computeTargetJoints(episodes, evaluations, time.Now().UTC()),
+	}, nil
+}
+
+type PatientTargetJoints struct {
+	Patient      Patient       `json:"patient"`
+	TargetJoints []TargetJoint `json:"target_joints"`
+}
+
+type ListPatientsWithTargetJointsParams struct {
+	ActionContext
+}
+
+type ListPatientsWithTargetJointsPayload struct {
+	Data []PatientTargetJoints `json:"data"`
+}
+
+// ListPatientsWithTargetJoints lists the patients that have active target
+// joints, with only the active target joints of each patient.
+func (a *Actions) ListPatientsWithTargetJoints(params ListPatientsWithTargetJointsParams) (ListPatientsWithTargetJointsPayload, error) {
+	if !params.Account.HasPermission(models.AccountPermissionReadJoints) {
+		return ListPatientsWithTargetJointsPayload{}, ErrPermissionDenied{}
+	}
+
+	episodes, err := a.app.ListBleedingEpisodesOnSites(targetJointSites())
+	if err != nil {
+		return ListPatientsWithTargetJointsPayload{}, err
+	}
+
+	patientsIds := make([]uint, 0)
+	patientsEpisodes := make(map[uint][]models.BleedingEpisode)
+	for _, be := range episodes {
+		if _, ok := patientsEpisodes[be.PatientId]; !ok {
+			patientsIds = append(patientsIds, be.PatientId)
+		}
+		patientsEpisodes[be.PatientId] = append(patientsEpisodes[be.PatientId], be)
+	}
+
+	now := time.Now().UTC()
+	outPatients := make([]PatientTargetJoints, 0)
+	for _, patientId := range patientsIds {
+		activeTargetJoints := slices.DeleteFunc(computeTargetJoints(patientsEpisodes[patientId], nil, now), func(tj TargetJoint) bool {
+			return !tj.Active
+		})
+		if len(activeTargetJoints) == 0 {
+			continue
+		}
+
+		evaluations, err := a.app.ListJointEvaluationsForPatient(patientId)
+		if err != nil {
+			return ListPatientsWithTargetJointsPayload{}, err
+		}
+		setTargetJointsHjhsScores(activeTargetJoints, evaluations, now)
+
+		// the episodes that were left of a deleted patient are skipped, so
+		// that they don't fail the other patients' list.
+		patient, err := a.app.GetPatientById(patientId)
+		var notFoundErr *app.ErrNotFound
+		if errors.As(err, &notFoundErr) {
+			continue
+		}
+		if err != nil {
+			return ListPatientsWithTargetJointsPayload{}, err
+		}
+
+		outPatient := new(Patient)
+		outPatient.FromModel(patient)
+		outPatients = append(outPatients, PatientTargetJoints{
+			Patient:      *outPatient,
+			TargetJoints: activeTargetJoints,
+		})
+	}
+
+	a.audit(params.ActionContext, auditEntry{
+		Action: "ListPatientsWithTargetJoints",
+		Kind:   models.AuditEventKindRead,
+	})
+
+	return ListPatientsWithTargetJointsPayload{
+		Data: outPatients,
+	}, nil
+}
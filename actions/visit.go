@@ -1,6 +1,7 @@
 package actions
 
 import (
+	"shs/app"
 	"shs/app/models"
 	"slices"
 	"time"
@@ -64,6 +65,12 @@ type CreatePatientVisitParams struct {
 	PatientWeight       float64    `json:"patient_weight"`
 	PatientHeight       float64    `json:"patient_height"`
 	PrescribedMedicines []Medicine `json:"prescribed_medicines"`
+	// BleedingEpisode is optional, and is set when the visit was for a bleed.
+	BleedingEpisode *BleedingEpisode `json:"bleeding_episode"`
+	// AppointmentId is optional, and is set when the patient checked in for a
+	// booked appointment, where the appointment is marked as attended with the
+	// created visit.
+	AppointmentId uint `json:"appointment_id"`
 }
 
 type CreatePatientVisitPayload struct {
@@ -79,59 +86,148 @@ func (a *Actions) CreatePatientVisit(params CreatePatientVisitParams) (CreatePat
 		return CreatePatientVisitPayload{}, err
 	}
 
-	medIds := make([]uint, 0, len(params.PrescribedMedicines))
-	for _, med := range params.PrescribedMedicines {
-		medIds = append(medIds, med.Id)
-	}
-
-	meds, err := a.app.ListMedicinesByIds(medIds)
-	if err != nil {
-		return CreatePatientVisitPayload{}, err
+	if params.BleedingEpisode != nil {
+		err = params.BleedingEpisode.Validate()
+		if err != nil {
+			return CreatePatientVisitPayload{}, err
+		}
 	}
 
+	medIds := make([]uint, 0, len(params.PrescribedMedicines))
 	prescribedMedicinesAmount := make(map[uint]int)
 	for _, med := range params.PrescribedMedicines {
+		if med.Amount <= 0 {
+			return CreatePatientVisitPayload{}, ErrValidation{Field: "amount"}
+		}
+		if _, ok := prescribedMedicinesAmount[med.Id]; !ok {
+			medIds = append(medIds, med.Id)
+		}
 		prescribedMedicinesAmount[med.Id] += med.Amount
 	}
 
-	for _, med := range meds {
-		if prescribedMedicinesAmount[med.Id] > med.Amount {
-			return CreatePatientVisitPayload{}, ErrInsufficientMedicine{
-				MedicineName:    med.Name,
-				ExceedingAmount: prescribedMedicinesAmount[med.Id],
-				LeftPackages:    med.Amount,
+	// the whole check-up is a single unit, so that a failure in the middle
+	// doesn't leave a visit without its medicines, or a stock that was
+	// decremented for a visit that was never created.
+	var visitId uint
+	err = a.app.WithTransaction(func(tx *app.App) error {
+		var appointment models.Appointment
+		if params.AppointmentId != 0 {
+			appointment, err = tx.LockAppointment(params.AppointmentId)
+			if err != nil {
+				return err
+			}
+			if appointment.PatientId != patient.Id || appointment.Status != models.AppointmentStatusBooked {
+				return ErrValidation{Field: "appointment_id"}
+			}
+			if params.VisitReason == "" {
+				params.VisitReason = string(appointment.Reason)
 			}
 		}
-	}
 
-	visit, err := a.app.CreatePatientVisit(models.Visit{
-		PatientId:     patient.Id,
-		Reason:        models.VisitReason(params.VisitReason),
-		Notes:         params.VisitExtraDetails,
-		PatientWeight: params.PatientWeight,
-		PatientHeight: params.PatientHeight,
-	})
-	if err != nil {
-		return CreatePatientVisitPayload{}, err
-	}
+		// the medicine rows are locked until the transaction ends, so that
+		// concurrent check-ups can't both pass the stock check below.
+		meds, err := tx.LockMedicinesByIds(medIds)
+		if err != nil {
+			return err
+		}
+		if len(meds) != len(medIds) {
+			return app.ErrNotFound{
+				ResourceName: "medicine",
+			}
+		}
 
-	for _, med := range params.PrescribedMedicines {
-		for range med.Amount {
-			_, err = a.app.CreatePrescribedMedicine(models.PrescribedMedicine{
-				VisitId:    visit.Id,
-				PatientId:  patient.Id,
-				MedicineId: med.Id,
-			})
+		now := time.Now()
+		for _, med := range meds {
+			if med.IsExpiredAt(now) {
+				return ErrExpiredMedicine{
+					MedicineName: med.Name,
+					BatchNumber:  med.BatchNumber,
+					ExpiresAt:    med.ExpiresAt,
+				}
+			}
+			if prescribedMedicinesAmount[med.Id] > med.Amount {
+				return ErrInsufficientMedicine{
+					MedicineName:    med.Name,
+					ExceedingAmount: prescribedMedicinesAmount[med.Id],
+					LeftPackages:    med.Amount,
+				}
+			}
 		}
+
+		visit, err := tx.CreatePatientVisit(models.Visit{
+			PatientId:     patient.Id,
+			Reason:        models.VisitReason(params.VisitReason),
+			Notes:         params.VisitExtraDetails,
+			PatientWeight: params.PatientWeight,
+			PatientHeight: params.PatientHeight,
+		})
 		if err != nil {
-			return CreatePatientVisitPayload{}, err
+			return err
 		}
-		err = a.app.DecrementMedicineAmount(med.Id, med.Amount)
-		if err != nil {
-			return CreatePatientVisitPayload{}, err
+		visitId = visit.Id
+
+		var firstPrescribedMedicineId uint
+		for _, med := range params.PrescribedMedicines {
+			for range med.Amount {
+				pm, err := tx.CreatePrescribedMedicine(models.PrescribedMedicine{
+					VisitId:    visit.Id,
+					PatientId:  patient.Id,
+					MedicineId: med.Id,
+				})
+				if err != nil {
+					return err
+				}
+				if firstPrescribedMedicineId == 0 {
+					firstPrescribedMedicineId = pm.Id
+				}
+			}
+		}
+
+		for _, med := range meds {
+			err = recordStockMovement(tx, med, models.StockMovement{
+				Kind:      models.StockMovementKindDispensedToVisit,
+				Delta:     -prescribedMedicinesAmount[med.Id],
+				AccountId: params.Account.Id,
+				VisitId:   visit.Id,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if params.BleedingEpisode != nil {
+			be := params.BleedingEpisode.IntoModel()
+			be.PatientId = patient.Id
+			be.VisitId = visit.Id
+			be.PrescribedMedicineId = firstPrescribedMedicineId
+			be.ReportedByPatient = false
+
+			_, err = tx.CreateBleedingEpisode(be)
+			if err != nil {
+				return err
+			}
+		}
+
+		if appointment.Id != 0 {
+			err = tx.UpdateAppointmentStatus(appointment.Id, models.AppointmentStatusAttended, visit.Id)
+			if err != nil {
+				return err
+			}
 		}
+
+		return nil
+	})
+	if err != nil {
+		return CreatePatientVisitPayload{}, err
 	}
 
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "CreatePatientVisit",
+		Kind:            models.AuditEventKindWrite,
+		PatientPublicId: params.PatientId,
+		ResourceIds:     map[string]uint{"visit_id": visitId},
+	})
+
 	return CreatePatientVisitPayload{}, nil
 }
 
@@ -239,6 +335,13 @@ func (a *Actions) GetPatientLastVisit(params GetPatientLastVisitParams) (GetPati
 		outTreatments = append(outTreatments, *outTreatment)
 	}
 
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "GetPatientLastVisit",
+		Kind:            models.AuditEventKindRead,
+		PatientPublicId: params.Account.Username,
+		ResourceIds:     map[string]uint{"visit_id": lastVisit.Id},
+	})
+
 	return GetPatientLastVisitPayload{
 		Patient:             *outPatient,
 		PrescribedMedicine:  outMeds,
@@ -270,6 +373,13 @@ func (a *Actions) UseMedicineForVisit(params UseMedicineForVisitParams) (UseMedi
 		return UseMedicineForVisitPayload{}, err
 	}
 
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "UseMedicineForVisit",
+		Kind:            models.AuditEventKindWrite,
+		PatientPublicId: params.Account.Username,
+		ResourceIds:     map[string]uint{"visit_id": params.VisitId, "prescribed_medicine_id": params.PrescribedMedicineId},
+	})
+
 	return UseMedicineForVisitPayload{}, nil
 }
 
@@ -341,6 +451,12 @@ func (a *Actions) ListPatientVisits(params ListPatientVisitsParams) (ListPatient
 		return vj.VisitedAt.Compare(vi.VisitedAt)
 	})
 
+	a.audit(params.ActionContext, auditEntry{
+		Action:          "ListPatientVisits",
+		Kind:            models.AuditEventKindRead,
+		PatientPublicId: params.PatientId,
+	})
+
 	return ListPatientVisitsPayload{
 		Data: outVisits,
 	}, nil
@@ -439,6 +555,11 @@ func (a *Actions) ListAllVisits(params ListAllVisitsParams) (ListAllVisitsPayloa
 		})
 	}
 
+	a.audit(params.ActionContext, auditEntry{
+		Action: "ListAllVisits",
+		Kind:   models.AuditEventKindRead,
+	})
+
 	return ListAllVisitsPayload{
 		Data: outVisits,
 	}, nil
@@ -72,6 +72,10 @@ func (a *App) UpdateAccount(id uint, newAccount models.Account) error {
 	return nil
 }
 
+func (a *App) UpdateAccountCalendarFeedVersion(id uint, version uint) error {
+	return a.repo.UpdateAccountCalendarFeedVersion(id, version)
+}
+
 func (a *App) DeleteAccount(id uint) error {
 	return a.repo.DeleteAccount(id)
 }
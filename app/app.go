@@ -11,3 +11,14 @@ func New(repo Repository, cache Cache) *App {
 		cache: cache,
 	}
 }
+
+// WithTransaction runs fn with an App whose repository calls all go through a
+// single transaction, so either all of them are applied or none is.
+func (a *App) WithTransaction(fn func(tx *App) error) error {
+	return a.repo.WithTransaction(func(tx Repository) error {
+		return fn(&App{
+			repo:  tx,
+			cache: a.cache,
+		})
+	})
+}
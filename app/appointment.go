@@ -0,0 +1,30 @@
+package app
+
+import (
+	"shs/app/models"
+	"time"
+)
+
+func (a *App) CreateAppointment(appointment models.Appointment) (models.Appointment, error) {
+	return a.repo.CreateAppointment(appointment)
+}
+
+func (a *App) GetAppointment(id uint) (models.Appointment, error) {
+	return a.repo.GetAppointment(id)
+}
+
+func (a *App) LockAppointment(id uint) (models.Appointment, error) {
+	return a.repo.LockAppointment(id)
+}
+
+func (a *App) ListAppointmentsOnTimeRange(from, to time.Time) ([]models.Appointment, error) {
+	return a.repo.ListAppointmentsOnTimeRange(from, to)
+}
+
+func (a *App) ListPatientAppointments(patientId uint) ([]models.Appointment, error) {
+	return a.repo.ListPatientAppointments(patientId)
+}
+
+func (a *App) UpdateAppointmentStatus(id uint, status models.AppointmentStatus, visitId uint) error {
+	return a.repo.UpdateAppointmentStatus(id, status, visitId)
+}
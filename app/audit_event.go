@@ -0,0 +1,11 @@
+package app
+
+import "shs/app/models"
+
+func (a *App) CreateAuditEvent(event models.AuditEvent) (models.AuditEvent, error) {
+	return a.repo.CreateAuditEvent(event)
+}
+
+func (a *App) ListAuditEvents(filter models.AuditEventsFilter) ([]models.AuditEvent, error) {
+	return a.repo.ListAuditEvents(filter)
+}
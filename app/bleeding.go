@@ -0,0 +1,19 @@
+package app
+
+import "shs/app/models"
+
+func (a *App) CreateBleedingEpisode(be models.BleedingEpisode) (models.BleedingEpisode, error) {
+	return a.repo.CreateBleedingEpisode(be)
+}
+
+func (a *App) ListPatientBleedingEpisodes(patientId uint) ([]models.BleedingEpisode, error) {
+	return a.repo.ListPatientBleedingEpisodes(patientId)
+}
+
+func (a *App) ListBleedingEpisodesOnSites(sites []models.BleedingSite) ([]models.BleedingEpisode, error) {
+	return a.repo.ListBleedingEpisodesOnSites(sites)
+}
+
+func (a *App) DeleteBleedingEpisodeForPatient(id, patientId uint) error {
+	return a.repo.DeleteBleedingEpisodeForPatient(id, patientId)
+}
@@ -44,9 +44,36 @@ func (a *App) UpdatePatientPendingBloodTestResultFields(btrId uint, fields []mod
 		return err
 	}
 
+	// the result's fields can be already filled when it's imported for review,
+	// where the reviewed fields replace them.
+	err = a.repo.DeleteBloodTestResultFilledFields(btrId)
+	if err != nil {
+		return err
+	}
+
 	for i := range fields {
 		fields[i].BloodTestResultId = btrId
 	}
 
 	return a.repo.CreateBloodTestResultFilledFields(fields)
 }
+
+func (a *App) ListBloodTestResultsOnTimeRange(from, to time.Time) ([]models.BloodTestResult, error) {
+	return a.repo.ListBloodTestResultsOnTimeRange(from, to)
+}
+
+func (a *App) ListBloodTestResultsForLabMessage(sendingFacility, controlId string) ([]models.BloodTestResult, error) {
+	return a.repo.ListBloodTestResultsForLabMessage(sendingFacility, controlId)
+}
+
+func (a *App) UpdateBloodTestFieldLoincCode(id uint, loincCode string) error {
+	return a.repo.UpdateBloodTestFieldLoincCode(id, loincCode)
+}
+
+func (a *App) CreateBloodTestReferenceRange(rr models.BloodTestReferenceRange) (models.BloodTestReferenceRange, error) {
+	return a.repo.CreateBloodTestReferenceRange(rr)
+}
+
+func (a *App) DeleteBloodTestReferenceRange(id uint) error {
+	return a.repo.DeleteBloodTestReferenceRange(id)
+}
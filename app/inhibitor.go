@@ -0,0 +1,15 @@
+package app
+
+import "shs/app/models"
+
+func (a *App) CreateInhibitorSurveillance(is models.InhibitorSurveillance) (models.InhibitorSurveillance, error) {
+	return a.repo.CreateInhibitorSurveillance(is)
+}
+
+func (a *App) GetInhibitorSurveillanceForPatient(patientId uint) (models.InhibitorSurveillance, error) {
+	return a.repo.GetInhibitorSurveillanceForPatient(patientId)
+}
+
+func (a *App) UpdateInhibitorSurveillance(id uint, is models.InhibitorSurveillance) error {
+	return a.repo.UpdateInhibitorSurveillance(id, is)
+}
@@ -0,0 +1,15 @@
+package app
+
+import "shs/app/models"
+
+func (a *App) CreateLabCodeMapping(mapping models.LabCodeMapping) (models.LabCodeMapping, error) {
+	return a.repo.CreateLabCodeMapping(mapping)
+}
+
+func (a *App) ListLabCodeMappings() ([]models.LabCodeMapping, error) {
+	return a.repo.ListLabCodeMappings()
+}
+
+func (a *App) DeleteLabCodeMapping(id uint) error {
+	return a.repo.DeleteLabCodeMapping(id)
+}
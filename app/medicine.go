@@ -18,12 +18,12 @@ func (a *App) ListMedicinesByIds(ids []uint) ([]models.Medicine, error) {
 	return a.repo.ListMedicinesByIds(ids)
 }
 
-func (a *App) UpdateMedicineAmount(id uint, newAmount int) error {
-	return a.repo.UpdateMedicineAmount(id, newAmount)
+func (a *App) LockMedicinesByIds(ids []uint) ([]models.Medicine, error) {
+	return a.repo.LockMedicinesByIds(ids)
 }
 
-func (a *App) DecrementMedicineAmount(id uint, amount int) error {
-	return a.repo.DecrementMedicineAmount(id, amount)
+func (a *App) UpdateMedicineAmount(id uint, newAmount int) error {
+	return a.repo.UpdateMedicineAmount(id, newAmount)
 }
 
 func (a *App) GetMedicine(id uint) (models.Medicine, error) {
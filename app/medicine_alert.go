@@ -0,0 +1,15 @@
+package app
+
+import "shs/app/models"
+
+func (a *App) CreateMedicineAlerts(alerts []models.MedicineAlert) error {
+	return a.repo.CreateMedicineAlerts(alerts)
+}
+
+func (a *App) ListAllMedicineAlerts() ([]models.MedicineAlert, error) {
+	return a.repo.ListAllMedicineAlerts()
+}
+
+func (a *App) DeleteAllMedicineAlerts() error {
+	return a.repo.DeleteAllMedicineAlerts()
+}
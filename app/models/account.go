@@ -39,6 +39,7 @@ const (
 	AccountPermissionWriteJoints
 	AccountPermissionReadProphylaxes
 	AccountPermissionWriteProphylaxes
+	AccountPermissionReadAuditEvents
 )
 
 type Account struct {
@@ -48,6 +49,10 @@ type Account struct {
 	Password    string             `gorm:"not null"`
 	Type        AccountType        `gorm:"not null"`
 	Permissions AccountPermissions `gorm:"not null"`
+	// CalendarFeedVersion is the version of the calendar feed's token, which
+	// is incremented when a new token is issued, so that the older tokens
+	// stop working.
+	CalendarFeedVersion uint `gorm:"not null;default:0"`
 
 	CreatedAt time.Time `gorm:"index;not null"`
 	UpdatedAt time.Time
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+type AppointmentStatus string
+
+const (
+	AppointmentStatusBooked    AppointmentStatus = "booked"
+	AppointmentStatusAttended  AppointmentStatus = "attended"
+	AppointmentStatusNoShow    AppointmentStatus = "no_show"
+	AppointmentStatusCancelled AppointmentStatus = "cancelled"
+)
+
+type Appointment struct {
+	Id          uint              `gorm:"primaryKey;autoIncrement"`
+	PatientId   uint              `gorm:"index;not null"`
+	Reason      VisitReason       `gorm:"not null"`
+	ScheduledAt time.Time         `gorm:"index;not null"`
+	AccountId   uint              `gorm:"index"`
+	Status      AppointmentStatus `gorm:"index;not null"`
+	Notes       string
+	// VisitId is the visit the appointment was converted into when the patient
+	// checked in, and it's zero otherwise.
+	VisitId uint
+
+	CreatedAt time.Time `gorm:"index;not null"`
+	UpdatedAt time.Time
+}
+
+func (Appointment) TableName() string {
+	return "appointments"
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+type AuditEventKind string
+
+const (
+	AuditEventKindRead  AuditEventKind = "read"
+	AuditEventKindWrite AuditEventKind = "write"
+)
+
+// AuditEvent is an append-only entry of the audit trail, where every read and
+// write of a patient's data has a matching event.
+type AuditEvent struct {
+	Id              uint           `gorm:"primaryKey;autoIncrement"`
+	AccountId       uint           `gorm:"index;not null"`
+	AccountUsername string         `gorm:"not null"`
+	Action          string         `gorm:"index;size:64;not null"`
+	Kind            AuditEventKind `gorm:"size:16;not null"`
+	// PatientPublicId is empty for the actions that read many patients.
+	PatientPublicId string `gorm:"index;size:32"`
+	// ResourceIds is a JSON object of the action's other resources' ids, as in
+	// {"visit_id":12}.
+	ResourceIds string
+	// Diff is a JSON object of an update's changed fields, as in
+	// {"first_name":{"before":"a","after":"b"}}.
+	Diff      string
+	ClientIp  string `gorm:"size:64"`
+	UserAgent string
+
+	CreatedAt time.Time `gorm:"index;not null"`
+}
+
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
+
+// AuditEventsFilter filters the listed events, where the zero fields match
+// all of the events.
+type AuditEventsFilter struct {
+	AccountId       uint
+	PatientPublicId string
+	From            time.Time
+	To              time.Time
+	// Limit is the maximum number of the latest events, where zero is no
+	// limit.
+	Limit int
+}
@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+type BleedingSite string
+
+const (
+	BleedingSiteRightAnkle   BleedingSite = "right_ankle"
+	BleedingSiteLeftAnkle    BleedingSite = "left_ankle"
+	BleedingSiteRightKnee    BleedingSite = "right_knee"
+	BleedingSiteLeftKnee     BleedingSite = "left_knee"
+	BleedingSiteRightElbow   BleedingSite = "right_elbow"
+	BleedingSiteLeftElbow    BleedingSite = "left_elbow"
+	BleedingSiteOtherJoint   BleedingSite = "other_joint"
+	BleedingSiteMuscle       BleedingSite = "muscle"
+	BleedingSiteMucosal      BleedingSite = "mucosal"
+	BleedingSiteIntracranial BleedingSite = "intracranial"
+	BleedingSiteOther        BleedingSite = "other"
+)
+
+func (s BleedingSite) IsJoint() bool {
+	switch s {
+	case BleedingSiteRightAnkle, BleedingSiteLeftAnkle,
+		BleedingSiteRightKnee, BleedingSiteLeftKnee,
+		BleedingSiteRightElbow, BleedingSiteLeftElbow,
+		BleedingSiteOtherJoint:
+		return true
+	default:
+		return false
+	}
+}
+
+type BleedingCause string
+
+const (
+	BleedingCauseSpontaneous BleedingCause = "spontaneous"
+	BleedingCauseTraumatic   BleedingCause = "traumatic"
+)
+
+type BleedingSeverity string
+
+const (
+	BleedingSeverityMild     BleedingSeverity = "mild"
+	BleedingSeverityModerate BleedingSeverity = "moderate"
+	BleedingSeveritySevere   BleedingSeverity = "severe"
+)
+
+type BleedingEpisode struct {
+	Id        uint             `gorm:"primaryKey;autoIncrement"`
+	PatientId uint             `gorm:"index;not null"`
+	Site      BleedingSite     `gorm:"not null"`
+	Cause     BleedingCause    `gorm:"not null"`
+	Severity  BleedingSeverity `gorm:"not null"`
+	OnsetAt   time.Time        `gorm:"index;not null"`
+	Notes     string
+	// VisitId is zero when the episode was reported by the patient from the portal.
+	VisitId uint `gorm:"index"`
+	// PrescribedMedicineId is the prescribed medicine the episode was treated with, zero when not treated.
+	PrescribedMedicineId uint
+	ReportedByPatient    bool `gorm:"not null"`
+
+	CreatedAt time.Time `gorm:"index;not null"`
+	UpdatedAt time.Time
+}
+
+func (BleedingEpisode) TableName() string {
+	return "bleeding_episodes"
+}
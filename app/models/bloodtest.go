@@ -1,6 +1,8 @@
 package models
 
 import (
+	"strconv"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -27,15 +29,23 @@ const (
 	BlootTestUnitNanoGramPerDeciLiter   BlootTestUnit = "ng/dL"
 	BlootTestUnitPicoGramPerDeciLiter   BlootTestUnit = "pg/dL"
 
+	BlootTestUnitMilliMolePerLiter BlootTestUnit = "mmol/L"
+	BlootTestUnitMicroMolePerLiter BlootTestUnit = "umol/L"
+
 	BlootTestUnitML         BlootTestUnit = "mL"
 	BlootTestUnitFemtoLiter BlootTestUnit = "fL"
 
-	BlootTestUnitInternationalUnitPerDeciLiter BlootTestUnit = "IU/dL"
-	BlootTestUnitUnitPerLiter                  BlootTestUnit = "U/L"
+	BlootTestUnitInternationalUnitPerDeciLiter  BlootTestUnit = "IU/dL"
+	BlootTestUnitUnitPerLiter                   BlootTestUnit = "U/L"
+	BlootTestUnitInternationalUnitPerMilliLiter BlootTestUnit = "IU/mL"
 
 	BlootTestUnitCellPerCubicMilliLiter         BlootTestUnit = "cell/mm^3"
 	BlootTestUnitThousandCellPerCubicMillimeter BlootTestUnit = "10^3 cell/mm^3"
 	BlootTestUnitMillionCellPerCubicMillimeter  BlootTestUnit = "10^6 cell/mm^3"
+	BlootTestUnitBillionCellPerLiter            BlootTestUnit = "10^9/L"
+	BlootTestUnitTrillionCellPerLiter           BlootTestUnit = "10^12/L"
+
+	BlootTestUnitLiterPerLiter BlootTestUnit = "L/L"
 
 	BlootTestUnitRatioOrIndex BlootTestUnit = "-"
 
@@ -59,18 +69,67 @@ func BloodTestUnits() []BlootTestUnit {
 		BlootTestUnitMicroGramPerDeciLiter,
 		BlootTestUnitNanoGramPerDeciLiter,
 		BlootTestUnitPicoGramPerDeciLiter,
+		BlootTestUnitMilliMolePerLiter,
+		BlootTestUnitMicroMolePerLiter,
 		BlootTestUnitML,
 		BlootTestUnitFemtoLiter,
 		BlootTestUnitInternationalUnitPerDeciLiter,
 		BlootTestUnitUnitPerLiter,
+		BlootTestUnitInternationalUnitPerMilliLiter,
 		BlootTestUnitCellPerCubicMilliLiter,
 		BlootTestUnitThousandCellPerCubicMillimeter,
 		BlootTestUnitMillionCellPerCubicMillimeter,
+		BlootTestUnitBillionCellPerLiter,
+		BlootTestUnitTrillionCellPerLiter,
+		BlootTestUnitLiterPerLiter,
 		BlootTestUnitRatioOrIndex,
 		BlootTestUnitNoUnit,
 	}
 }
 
+// LOINC codes of the fields that are looked up by the app, e.g. when the
+// patients are imported or the hemophilia severity is classified.
+const (
+	LoincAboGroup                 = "883-9"
+	LoincRhType                   = "10331-7"
+	LoincFactorVActivity          = "3193-0"
+	LoincFactorViiActivity        = "3198-9"
+	LoincFactorViiiActivity       = "3209-4"
+	LoincFactorViiiInhibitorTiter = "3204-5"
+	LoincFactorViiiInhibitor      = "3205-2"
+	LoincFactorIxActivity         = "3187-2"
+	LoincFactorXActivity          = "3218-5"
+	LoincVwfAntigen               = "27816-8"
+	LoincFibrinogen               = "3255-7"
+)
+
+// ValidLoincCode reports whether the code is a LOINC code, i.e. digits, a
+// hyphen and the digits' mod 10 check digit.
+func ValidLoincCode(code string) bool {
+	number, checkDigit, ok := strings.Cut(code, "-")
+	if !ok || number == "" || len(number) > 7 || len(checkDigit) != 1 {
+		return false
+	}
+
+	sum := 0
+	for i := range len(number) {
+		digit := number[len(number)-1-i]
+		if digit < '0' || digit > '9' {
+			return false
+		}
+		d := int(digit - '0')
+		if i%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+
+	return checkDigit[0] >= '0' && checkDigit[0] <= '9' && int(checkDigit[0]-'0') == (10-sum%10)%10
+}
+
 type BloodTestField struct {
 	Id             uint          `gorm:"primaryKey;autoIncrement"`
 	BloodTestId    uint          `gorm:"not null"`
@@ -80,6 +139,17 @@ type BloodTestField struct {
 	MinValueString string
 	MaxValueNumber float64
 	MaxValueString string
+	// Formula computes the field's value from its sibling fields, referenced
+	// by their names in braces, e.g. "{Hb} / {Hct} * 100", where an empty
+	// formula is a field that's filled by hand.
+	Formula string
+	// LoincCode is the field's LOINC code, e.g. 3209-4, which identifies the
+	// field regardless of its display name, where an empty code is a field that
+	// isn't coded yet.
+	LoincCode string `gorm:"index;size:16"`
+	// ReferenceRanges override the field's min and max values for the
+	// patients that are in a range's age band and sex.
+	ReferenceRanges []BloodTestReferenceRange `gorm:"foreignKey:BloodTestFieldId"`
 
 	CreatedAt time.Time `gorm:"index;not null"`
 	UpdatedAt time.Time
@@ -89,6 +159,36 @@ func (BloodTestField) TableName() string {
 	return "blood_test_fields"
 }
 
+type ReferenceRangeSex string
+
+const (
+	ReferenceRangeSexAny    ReferenceRangeSex = "any"
+	ReferenceRangeSexMale   ReferenceRangeSex = "male"
+	ReferenceRangeSexFemale ReferenceRangeSex = "female"
+)
+
+// BloodTestReferenceRange is a field's normal range for an age band and sex,
+// where the age band starts at MinAgeMonths and ends before MaxAgeMonths, and
+// a zero MaxAgeMonths has no upper bound, and a zero critical value isn't checked.
+type BloodTestReferenceRange struct {
+	Id               uint              `gorm:"primaryKey;autoIncrement"`
+	BloodTestFieldId uint              `gorm:"index;not null"`
+	Sex              ReferenceRangeSex `gorm:"not null"`
+	MinAgeMonths     int               `gorm:"not null"`
+	MaxAgeMonths     int               `gorm:"not null"`
+	Low              float64           `gorm:"not null"`
+	High             float64           `gorm:"not null"`
+	CriticalLow      float64
+	CriticalHigh     float64
+
+	CreatedAt time.Time `gorm:"index;not null"`
+	UpdatedAt time.Time
+}
+
+func (BloodTestReferenceRange) TableName() string {
+	return "blood_test_reference_ranges"
+}
+
 type BloodTest struct {
 	Id             uint             `gorm:"primaryKey;autoIncrement"`
 	Name           string           `gorm:"not null"`
@@ -123,7 +223,11 @@ type BloodTestFilledField struct {
 	BloodTestFieldId  uint
 	ValueNumber       float64
 	ValueString       string
-	TestedAt          time.Time `gorm:"not null"`
+	// OriginalValue and OriginalUnit are the value as it was entered, when it
+	// was entered in another unit than the field's, and was converted to it.
+	OriginalValue string
+	OriginalUnit  BlootTestUnit
+	TestedAt      time.Time `gorm:"not null"`
 
 	CreatedAt time.Time `gorm:"index;not null"`
 	UpdatedAt time.Time
@@ -133,6 +237,23 @@ func (BloodTestFilledField) TableName() string {
 	return "blood_test_filled_fields"
 }
 
+// Number returns the field's numeric value, where a field that was left empty
+// or filled with a text that isn't a number has no numeric value, since the
+// text is the value as it was entered, and a zero number is a value as well.
+func (f BloodTestFilledField) Number() (float64, bool) {
+	valueString := strings.TrimSpace(f.ValueString)
+	if valueString == "" {
+		return 0, false
+	}
+
+	value, err := strconv.ParseFloat(valueString, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return value, true
+}
+
 type BloodTestResult struct {
 	Id           uint `gorm:"primaryKey;autoIncrement"`
 	BloodTestId  uint `gorm:"not null"`
@@ -141,6 +262,11 @@ type BloodTestResult struct {
 	FilledFields []BloodTestFilledField `gorm:"foreignKey:BloodTestResultId"`
 	Pending      bool                   `gorm:"not null"`
 	TestedAt     time.Time              `gorm:"not null"`
+	// LabSendingFacility and LabMessageControlId identify the HL7 message
+	// that the result was imported from, and they're empty when the result
+	// wasn't imported.
+	LabSendingFacility  string `gorm:"index:idx_blood_test_results_lab_message;size:255"`
+	LabMessageControlId string `gorm:"index:idx_blood_test_results_lab_message;size:255"`
 
 	CreatedAt time.Time `gorm:"index;not null"`
 	UpdatedAt time.Time
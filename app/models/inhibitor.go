@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+type ItiStatus string
+
+const (
+	ItiStatusNone           ItiStatus = "none"
+	ItiStatusPlanned        ItiStatus = "planned"
+	ItiStatusOngoing        ItiStatus = "ongoing"
+	ItiStatusSuccess        ItiStatus = "success"
+	ItiStatusPartialSuccess ItiStatus = "partial_success"
+	ItiStatusFailure        ItiStatus = "failure"
+)
+
+// InhibitorSurveillance holds the patient's inhibitor follow up settings, where
+// the titers and exposure days are derived from the patient's blood test
+// results and used medicines.
+type InhibitorSurveillance struct {
+	Id        uint `gorm:"primaryKey;autoIncrement"`
+	PatientId uint `gorm:"uniqueIndex;not null"`
+	// ScreeningIntervalEds is the number of exposure days between two
+	// inhibitor screenings in the first 50 exposure days.
+	ScreeningIntervalEds int       `gorm:"not null"`
+	ItiStatus            ItiStatus `gorm:"not null"`
+	ItiStartedAt         time.Time
+	ItiEndedAt           time.Time
+	Notes                string
+
+	CreatedAt time.Time `gorm:"index;not null"`
+	UpdatedAt time.Time
+}
+
+func (InhibitorSurveillance) TableName() string {
+	return "inhibitor_surveillances"
+}
@@ -2,6 +2,55 @@ package models
 
 import "time"
 
+type Joint string
+
+const (
+	JointRightElbow Joint = "right_elbow"
+	JointLeftElbow  Joint = "left_elbow"
+	JointRightKnee  Joint = "right_knee"
+	JointLeftKnee   Joint = "left_knee"
+	JointRightAnkle Joint = "right_ankle"
+	JointLeftAnkle  Joint = "left_ankle"
+)
+
+// HjhsJoints are the joints that are scored in HJHS 2.1, in the score sheet's order.
+var HjhsJoints = []Joint{
+	JointRightElbow, JointLeftElbow,
+	JointRightKnee, JointLeftKnee,
+	JointRightAnkle, JointLeftAnkle,
+}
+
+// HjhsJointScore is a joint's item scores in an HJHS 2.1 evaluation, where
+// higher scores mean worse joint health.
+type HjhsJointScore struct {
+	Id                 uint  `gorm:"primaryKey;autoIncrement"`
+	JointsEvaluationId uint  `gorm:"index;not null"`
+	Joint              Joint `gorm:"not null"`
+	Swelling           int   `gorm:"not null"`
+	SwellingDuration   int   `gorm:"not null"`
+	MuscleAtrophy      int   `gorm:"not null"`
+	Crepitus           int   `gorm:"not null"`
+	FlexionLoss        int   `gorm:"not null"`
+	ExtensionLoss      int   `gorm:"not null"`
+	JointPain          int   `gorm:"not null"`
+	Strength           int   `gorm:"not null"`
+
+	CreatedAt time.Time `gorm:"index;not null"`
+	UpdatedAt time.Time
+}
+
+func (HjhsJointScore) TableName() string {
+	return "hjhs_joint_scores"
+}
+
+func (s HjhsJointScore) Total() int {
+	return s.Swelling + s.SwellingDuration + s.MuscleAtrophy + s.Crepitus +
+		s.FlexionLoss + s.ExtensionLoss + s.JointPain + s.Strength
+}
+
+// JointsEvaluation holds each joint's total score, where the totals of the
+// evaluations that were created before HJHS 2.1 scoring are plain numbers
+// without item scores.
 type JointsEvaluation struct {
 	Id         uint `gorm:"primaryKey;autoIncrement"`
 	PatientId  uint `gorm:"index"`
@@ -11,6 +60,8 @@ type JointsEvaluation struct {
 	LeftKnee   int  `gorm:"not null"`
 	RightElbow int  `gorm:"not null"`
 	LeftElbow  int  `gorm:"not null"`
+	GlobalGait int
+	Scores     []HjhsJointScore `gorm:"foreignKey:JointsEvaluationId"`
 
 	CreatedAt time.Time `gorm:"index;not null"`
 	UpdatedAt time.Time
@@ -19,3 +70,50 @@ type JointsEvaluation struct {
 func (JointsEvaluation) TableName() string {
 	return "joints_evaluations"
 }
+
+// SetJointsTotals sets each joint's total from the evaluation's item scores.
+func (je *JointsEvaluation) SetJointsTotals() {
+	for _, score := range je.Scores {
+		switch score.Joint {
+		case JointRightAnkle:
+			je.RightAnkle = score.Total()
+		case JointLeftAnkle:
+			je.LeftAnkle = score.Total()
+		case JointRightKnee:
+			je.RightKnee = score.Total()
+		case JointLeftKnee:
+			je.LeftKnee = score.Total()
+		case JointRightElbow:
+			je.RightElbow = score.Total()
+		case JointLeftElbow:
+			je.LeftElbow = score.Total()
+		}
+	}
+}
+
+func (je JointsEvaluation) JointTotal(joint Joint) int {
+	switch joint {
+	case JointRightAnkle:
+		return je.RightAnkle
+	case JointLeftAnkle:
+		return je.LeftAnkle
+	case JointRightKnee:
+		return je.RightKnee
+	case JointLeftKnee:
+		return je.LeftKnee
+	case JointRightElbow:
+		return je.RightElbow
+	case JointLeftElbow:
+		return je.LeftElbow
+	default:
+		return 0
+	}
+}
+
+// Total is the HJHS total, the sum of the joints' totals and the global gait score.
+func (je JointsEvaluation) Total() int {
+	return je.RightAnkle + je.LeftAnkle +
+		je.RightKnee + je.LeftKnee +
+		je.RightElbow + je.LeftElbow +
+		je.GlobalGait
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// LabCodeMapping maps a lab's observation code, e.g. an HL7 OBX-3 code, to the
+// blood test field that it's recorded in, where an empty coding system matches
+// the code in any coding system.
+type LabCodeMapping struct {
+	Id               uint   `gorm:"primaryKey;autoIncrement"`
+	CodingSystem     string `gorm:"uniqueIndex:idx_lab_code_mapping_code;size:64;not null"`
+	Code             string `gorm:"uniqueIndex:idx_lab_code_mapping_code;size:64;not null"`
+	BloodTestFieldId uint   `gorm:"index;not null"`
+
+	CreatedAt time.Time `gorm:"index;not null"`
+	UpdatedAt time.Time
+}
+
+func (LabCodeMapping) TableName() string {
+	return "lab_code_mappings"
+}
@@ -22,3 +22,8 @@ type Medicine struct {
 func (Medicine) TableName() string {
 	return "medicines"
 }
+
+// IsExpiredAt reports whether the batch can't be used at the given time.
+func (m Medicine) IsExpiredAt(t time.Time) bool {
+	return !m.ExpiresAt.After(t)
+}
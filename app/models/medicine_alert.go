@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+type MedicineAlertKind string
+
+const (
+	MedicineAlertKindExpired  MedicineAlertKind = "expired"
+	MedicineAlertKindExpiring MedicineAlertKind = "expiring"
+	MedicineAlertKindLowStock MedicineAlertKind = "low_stock"
+)
+
+// MedicineAlert is raised by the daily medicines scan, and the whole set is
+// replaced on every scan.
+type MedicineAlert struct {
+	Id         uint              `gorm:"primaryKey;autoIncrement"`
+	MedicineId uint              `gorm:"index;not null"`
+	Kind       MedicineAlertKind `gorm:"not null"`
+	// WindowDays is the expiry window that the batch fell into, and it's only
+	// set for expiring alerts.
+	WindowDays int
+	// Amount is the amount of packages left when the alert was raised, where
+	// for low stock alerts it's the total of all the medicine's valid batches.
+	Amount    int       `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+
+	CreatedAt time.Time `gorm:"index;not null"`
+	UpdatedAt time.Time
+}
+
+func (MedicineAlert) TableName() string {
+	return "medicine_alerts"
+}
@@ -0,0 +1,92 @@
+package models
+
+import "time"
+
+// PatientRevision is an immutable copy of a patient's details, where one is
+// recorded on every update of the patient, so that a mistyped value can be
+// seen and restored.
+type PatientRevision struct {
+	Id        uint `gorm:"primaryKey;autoIncrement"`
+	PatientId uint `gorm:"index;not null"`
+	// AccountId is the account that made the revision, where zero is the
+	// patient's details from before their revisions were recorded.
+	AccountId       uint   `gorm:"not null"`
+	AccountUsername string `gorm:"not null"`
+	// RestoredFromRevisionId is set when the revision restored an older one.
+	RestoredFromRevisionId uint
+
+	NationalId             string                  `gorm:"not null"`
+	Nationality            string                  `gorm:"not null"`
+	FirstName              string                  `gorm:"not null"`
+	LastName               string                  `gorm:"not null"`
+	FatherName             string                  `gorm:"not null"`
+	MotherName             string                  `gorm:"not null"`
+	PlaceOfBirth           Address                 `gorm:"not null"`
+	PlaceOfBirthId         uint                    `gorm:"not null"`
+	DateOfBirth            time.Time               `gorm:"not null"`
+	Residency              Address                 `gorm:"not null"`
+	ResidencyId            uint                    `gorm:"not null"`
+	Gender                 bool                    `gorm:"not null"`
+	PhoneNumberCountryCode string                  `gorm:"not null"`
+	PhoneNumber            string                  `gorm:"not null"`
+	FamilyHistoryExists    bool                    `gorm:"not null"`
+	FirstVisitReason       PatientFirstVisitReason `gorm:"not null"`
+	BATScore               uint                    `gorm:"not null"`
+	WBDR                   string
+
+	CreatedAt time.Time `gorm:"index;not null"`
+}
+
+func (PatientRevision) TableName() string {
+	return "patient_revisions"
+}
+
+// NewPatientRevision copies the patient's details into a revision.
+func NewPatientRevision(patient Patient) PatientRevision {
+	return PatientRevision{
+		PatientId:              patient.Id,
+		NationalId:             patient.NationalId,
+		Nationality:            patient.Nationality,
+		FirstName:              patient.FirstName,
+		LastName:               patient.LastName,
+		FatherName:             patient.FatherName,
+		MotherName:             patient.MotherName,
+		PlaceOfBirth:           patient.PlaceOfBirth,
+		PlaceOfBirthId:         patient.PlaceOfBirthId,
+		DateOfBirth:            patient.DateOfBirth,
+		Residency:              patient.Residency,
+		ResidencyId:            patient.ResidencyId,
+		Gender:                 patient.Gender,
+		PhoneNumberCountryCode: patient.PhoneNumberCountryCode,
+		PhoneNumber:            patient.PhoneNumber,
+		FamilyHistoryExists:    patient.FamilyHistoryExists,
+		FirstVisitReason:       patient.FirstVisitReason,
+		BATScore:               patient.BATScore,
+		WBDR:                   patient.WBDR,
+	}
+}
+
+// Patient is the revision's details as the patient's updated details.
+func (r PatientRevision) Patient() Patient {
+	return Patient{
+		Id:                     r.PatientId,
+		NationalId:             r.NationalId,
+		Nationality:            r.Nationality,
+		FirstName:              r.FirstName,
+		LastName:               r.LastName,
+		FatherName:             r.FatherName,
+		MotherName:             r.MotherName,
+		PlaceOfBirth:           r.PlaceOfBirth,
+		PlaceOfBirthId:         r.PlaceOfBirthId,
+		DateOfBirth:            r.DateOfBirth,
+		Residency:              r.Residency,
+		ResidencyId:            r.ResidencyId,
+		Gender:                 r.Gender,
+		PhoneNumberCountryCode: r.PhoneNumberCountryCode,
+		PhoneNumber:            r.PhoneNumber,
+		FamilyHistoryExists:    r.FamilyHistoryExists,
+		FirstVisitReason:       r.FirstVisitReason,
+		BATScore:               r.BATScore,
+		WBDR:                   r.WBDR,
+	}
+}
@@ -2,14 +2,44 @@ package models
 
 import "time"
 
+type ProphylaxisScheduleKind string
+
+const (
+	ProphylaxisScheduleKindWeekdays   ProphylaxisScheduleKind = "weekdays"
+	ProphylaxisScheduleKindEveryNDays ProphylaxisScheduleKind = "every_n_days"
+)
+
+// ProphylaxisWeekdays is a bitmask of the schedule's weekdays, where the nth
+// bit is set for time.Weekday(n).
+type ProphylaxisWeekdays uint8
+
+func (w ProphylaxisWeekdays) Has(day time.Weekday) bool {
+	return w&(1<<day) != 0
+}
+
+func NewProphylaxisWeekdays(days ...time.Weekday) ProphylaxisWeekdays {
+	var w ProphylaxisWeekdays
+	for _, day := range days {
+		w |= 1 << day
+	}
+	return w
+}
+
 type Prophylaxis struct {
-	Id               uint `gorm:"primaryKey;autoIncrement"`
-	PatientId        uint `gorm:"index;not null"`
-	MedicineId       uint `gorm:"index;not null"`
-	Medicine         Medicine
-	MedicineAmount   int     `gorm:"not null"`
-	Title            string  `gorm:"not null"`
+	Id             uint `gorm:"primaryKey;autoIncrement"`
+	PatientId      uint `gorm:"index;not null"`
+	MedicineId     uint `gorm:"index;not null"`
+	Medicine       Medicine
+	MedicineAmount int    `gorm:"not null"`
+	Title          string `gorm:"not null"`
+	// FrequencyPerDays is kept for prophylaxes that were created before the
+	// explicit schedules, use Schedule instead.
 	FrequencyPerDays float32 `gorm:"not null"`
+	ScheduleKind     ProphylaxisScheduleKind
+	Weekdays         ProphylaxisWeekdays
+	IntervalDays     int
+	DoseIu           int
+	StartDate        time.Time
 	EndDate          time.Time
 	Chosen           bool
 
@@ -20,3 +50,35 @@ type Prophylaxis struct {
 func (Prophylaxis) TableName() string {
 	return "prophylaxes"
 }
+
+// StartsAt returns the schedule's start date, where it's the creation date for
+// prophylaxes that were created before the explicit schedules.
+func (p Prophylaxis) StartsAt() time.Time {
+	if p.StartDate.IsZero() {
+		return p.CreatedAt
+	}
+	return p.StartDate
+}
+
+// Schedule returns the prophylaxis' schedule, and for prophylaxes that were
+// created before the explicit schedules, it's mapped from their frequency.
+func (p Prophylaxis) Schedule() (ProphylaxisScheduleKind, ProphylaxisWeekdays, int) {
+	if p.ScheduleKind != "" {
+		return p.ScheduleKind, p.Weekdays, p.IntervalDays
+	}
+
+	switch p.FrequencyPerDays {
+	case 0.035:
+		return ProphylaxisScheduleKindEveryNDays, 0, 28
+	case 0.071:
+		return ProphylaxisScheduleKindEveryNDays, 0, 14
+	case 0.142:
+		return ProphylaxisScheduleKindEveryNDays, 0, 7
+	case 0.285:
+		return ProphylaxisScheduleKindWeekdays, NewProphylaxisWeekdays(time.Monday, time.Thursday), 0
+	case 0.428:
+		return ProphylaxisScheduleKindWeekdays, NewProphylaxisWeekdays(time.Monday, time.Wednesday, time.Friday), 0
+	default:
+		return "", 0, 0
+	}
+}
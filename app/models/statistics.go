@@ -0,0 +1,17 @@
+package models
+
+// StatisticsCount is a single aggregated row, where Name is the grouping value
+// e.g. a governorate or a visit reason, and Count is how many records fall under it.
+type StatisticsCount struct {
+	Name  string
+	Count int
+}
+
+// Age bands used when grouping patients by their age, as used in the WFH annual global survey.
+const (
+	AgeBand0To4   = "0-4"
+	AgeBand5To13  = "5-13"
+	AgeBand14To18 = "14-18"
+	AgeBand19To44 = "19-44"
+	AgeBand45Plus = "45+"
+)
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+type StockMovementKind string
+
+const (
+	StockMovementKindReceivedDonation StockMovementKind = "received_donation"
+	StockMovementKindDispensedToVisit StockMovementKind = "dispensed_to_visit"
+	StockMovementKindExpiredDestroyed StockMovementKind = "expired_destroyed"
+	StockMovementKindManualCorrection StockMovementKind = "manual_correction"
+	StockMovementKindReturned         StockMovementKind = "returned"
+)
+
+// StockMovement is an append-only entry of a medicine's stock ledger, where
+// every change to Medicine.Amount has a matching movement.
+type StockMovement struct {
+	Id         uint              `gorm:"primaryKey;autoIncrement"`
+	MedicineId uint              `gorm:"index;not null"`
+	Kind       StockMovementKind `gorm:"not null"`
+	// Delta is the signed change in packages.
+	Delta int `gorm:"not null"`
+	// AmountAfter is the medicine's amount right after this movement.
+	AmountAfter int  `gorm:"not null"`
+	AccountId   uint `gorm:"index;not null"`
+	VisitId     uint `gorm:"index"`
+	Note        string
+
+	CreatedAt time.Time `gorm:"index;not null"`
+	UpdatedAt time.Time
+}
+
+func (StockMovement) TableName() string {
+	return "stock_movements"
+}
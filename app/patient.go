@@ -47,6 +47,10 @@ func (a *App) ListPatientVisitPrescribedMedicine(visitId uint) ([]models.Prescri
 	return a.repo.ListPatientVisitPrescribedMedicine(visitId)
 }
 
+func (a *App) ListPatientUsedPrescribedMedicinesOnTimeRange(patientId uint, from, to time.Time) ([]models.PrescribedMedicine, error) {
+	return a.repo.ListPatientUsedPrescribedMedicinesOnTimeRange(patientId, from, to)
+}
+
 func (a *App) ListAllPrescribedMedicines() ([]models.PrescribedMedicine, error) {
 	return a.repo.ListAllPrescribedMedicines()
 }
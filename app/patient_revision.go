@@ -0,0 +1,15 @@
+package app
+
+import "shs/app/models"
+
+func (a *App) CreatePatientRevision(revision models.PatientRevision) (models.PatientRevision, error) {
+	return a.repo.CreatePatientRevision(revision)
+}
+
+func (a *App) GetPatientRevision(id uint) (models.PatientRevision, error) {
+	return a.repo.GetPatientRevision(id)
+}
+
+func (a *App) ListPatientRevisions(patientId uint) ([]models.PatientRevision, error) {
+	return a.repo.ListPatientRevisions(patientId)
+}
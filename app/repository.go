@@ -6,6 +6,11 @@ import (
 )
 
 type Repository interface {
+	// WithTransaction runs fn inside a single database transaction, where tx is a
+	// Repository bound to that transaction, the transaction is committed when fn
+	// returns nil, and is rolled back otherwise.
+	WithTransaction(fn func(tx Repository) error) error
+
 	GetAccount(id uint) (models.Account, error)
 	GetAccountByUsername(username string) (models.Account, error)
 	CreateAccount(account models.Account) (models.Account, error)
@@ -14,6 +19,7 @@ type Repository interface {
 	UpdateAccountPermissions(id uint, permissions models.AccountPermissions) error
 	UpdateAccountDisplayName(id uint, name string) error
 	UpdateAccountPassword(id uint, password string) error
+	UpdateAccountCalendarFeedVersion(id uint, version uint) error
 	UpdateAccountUsername(id uint, username string) error
 
 	CreateBloodTest(bt models.BloodTest) (models.BloodTest, error)
@@ -22,12 +28,18 @@ type Repository interface {
 	UpdateBloodTest(id uint, bt models.BloodTest) (models.BloodTest, error)
 	ListAllBloodTests() ([]models.BloodTest, error)
 	ToggleBloodTestDisplay(id uint) error
+	UpdateBloodTestFieldLoincCode(id uint, loincCode string) error
+	CreateBloodTestReferenceRange(rr models.BloodTestReferenceRange) (models.BloodTestReferenceRange, error)
+	DeleteBloodTestReferenceRange(id uint) error
 
 	CreateBloodTestResult(btResult models.BloodTestResult) (models.BloodTestResult, error)
 	ListPatientBloodTestResults(patientId uint) ([]models.BloodTestResult, error)
+	ListBloodTestResultsOnTimeRange(from, to time.Time) ([]models.BloodTestResult, error)
+	ListBloodTestResultsForLabMessage(sendingFacility, controlId string) ([]models.BloodTestResult, error)
 	SetBloodTestResultPending(id uint, pending bool) error
 	CreateBloodTestResultFilledFields(filledFields []models.BloodTestFilledField) error
 	UpdateBloodTestResultCreatedAt(id uint, ts time.Time) error
+	DeleteBloodTestResultFilledFields(btrId uint) error
 
 	CreateVirus(virus models.Virus) (models.Virus, error)
 	DeleteVirus(id uint) error
@@ -38,8 +50,10 @@ type Repository interface {
 	DeleteMedicine(id uint) error
 	ListAllMedicines() ([]models.Medicine, error)
 	ListMedicinesByIds(ids []uint) ([]models.Medicine, error)
+	// LockMedicinesByIds is like ListMedicinesByIds, but holds a write lock on the
+	// selected rows until the surrounding transaction ends.
+	LockMedicinesByIds(ids []uint) ([]models.Medicine, error)
 	UpdateMedicineAmount(id uint, newAmount int) error
-	DecrementMedicineAmount(id uint, amount int) error
 	GetMedicine(id uint) (models.Medicine, error)
 
 	CreatePatient(patient models.Patient) (models.Patient, error)
@@ -56,11 +70,29 @@ type Repository interface {
 	GetPatientVisit(visitId uint) (models.Visit, error)
 	ListVisitsOnTimeRange(from, to time.Time) ([]models.Visit, error)
 
+	CreateAppointment(appointment models.Appointment) (models.Appointment, error)
+	GetAppointment(id uint) (models.Appointment, error)
+	// LockAppointment is like GetAppointment, but holds a write lock on the
+	// appointment's row until the surrounding transaction ends.
+	LockAppointment(id uint) (models.Appointment, error)
+	ListAppointmentsOnTimeRange(from, to time.Time) ([]models.Appointment, error)
+	ListPatientAppointments(patientId uint) ([]models.Appointment, error)
+	UpdateAppointmentStatus(id uint, status models.AppointmentStatus, visitId uint) error
+
+	CreateInhibitorSurveillance(is models.InhibitorSurveillance) (models.InhibitorSurveillance, error)
+	GetInhibitorSurveillanceForPatient(patientId uint) (models.InhibitorSurveillance, error)
+	UpdateInhibitorSurveillance(id uint, is models.InhibitorSurveillance) error
+
+	CreateLabCodeMapping(mapping models.LabCodeMapping) (models.LabCodeMapping, error)
+	ListLabCodeMappings() ([]models.LabCodeMapping, error)
+	DeleteLabCodeMapping(id uint) error
+
 	CreatePrescribedMedicine(pm models.PrescribedMedicine) (models.PrescribedMedicine, error)
 	ListAllPrescribedMedicines() ([]models.PrescribedMedicine, error)
 
 	GetPatientLastVisit(patientId uint) (models.Visit, error)
 	ListPatientVisitPrescribedMedicine(visitId uint) ([]models.PrescribedMedicine, error)
+	ListPatientUsedPrescribedMedicinesOnTimeRange(patientId uint, from, to time.Time) ([]models.PrescribedMedicine, error)
 	UseMedicineForVisit(prescribedMedicineId, visitId, treatmentId uint) error
 
 	CreateTreatmentDetails(td models.TreatmentDetails) (models.TreatmentDetails, error)
@@ -87,4 +119,31 @@ type Repository interface {
 
 	CreateDiagnosisResult(dr models.DiagnosisResult) (models.DiagnosisResult, error)
 	ListPatientDiagnosisResults(patientId uint) ([]models.DiagnosisResult, error)
+
+	CreateBleedingEpisode(be models.BleedingEpisode) (models.BleedingEpisode, error)
+	ListPatientBleedingEpisodes(patientId uint) ([]models.BleedingEpisode, error)
+	ListBleedingEpisodesOnSites(sites []models.BleedingSite) ([]models.BleedingEpisode, error)
+	DeleteBleedingEpisodeForPatient(id, patientId uint) error
+
+	CreateStockMovement(sm models.StockMovement) (models.StockMovement, error)
+	ListMedicineStockMovements(medicineId uint) ([]models.StockMovement, error)
+
+	CreateMedicineAlerts(alerts []models.MedicineAlert) error
+	ListAllMedicineAlerts() ([]models.MedicineAlert, error)
+	DeleteAllMedicineAlerts() error
+
+	CreateAuditEvent(event models.AuditEvent) (models.AuditEvent, error)
+	ListAuditEvents(filter models.AuditEventsFilter) ([]models.AuditEvent, error)
+
+	CreatePatientRevision(revision models.PatientRevision) (models.PatientRevision, error)
+	GetPatientRevision(id uint) (models.PatientRevision, error)
+	ListPatientRevisions(patientId uint) ([]models.PatientRevision, error)
+
+	CountPatientsOnTimeRange(from, to time.Time) (int, error)
+	CountPatientsByDiagnosisGroup(from, to time.Time) ([]models.StatisticsCount, error)
+	CountPatientsByGender(from, to time.Time) ([]models.StatisticsCount, error)
+	CountPatientsByAgeBand(from, to time.Time) ([]models.StatisticsCount, error)
+	CountPatientsByResidencyGovernorate(from, to time.Time) ([]models.StatisticsCount, error)
+	CountPatientsByVirus(from, to time.Time) ([]models.StatisticsCount, error)
+	CountVisitsByReason(from, to time.Time) ([]models.StatisticsCount, error)
 }
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"shs/app/models"
+	"time"
+)
+
+func (a *App) CountPatientsOnTimeRange(from, to time.Time) (int, error) {
+	return a.repo.CountPatientsOnTimeRange(from, to)
+}
+
+func (a *App) CountPatientsByDiagnosisGroup(from, to time.Time) ([]models.StatisticsCount, error) {
+	return a.repo.CountPatientsByDiagnosisGroup(from, to)
+}
+
+func (a *App) CountPatientsByGender(from, to time.Time) ([]models.StatisticsCount, error) {
+	return a.repo.CountPatientsByGender(from, to)
+}
+
+func (a *App) CountPatientsByAgeBand(from, to time.Time) ([]models.StatisticsCount, error) {
+	return a.repo.CountPatientsByAgeBand(from, to)
+}
+
+func (a *App) CountPatientsByResidencyGovernorate(from, to time.Time) ([]models.StatisticsCount, error) {
+	return a.repo.CountPatientsByResidencyGovernorate(from, to)
+}
+
+func (a *App) CountPatientsByVirus(from, to time.Time) ([]models.StatisticsCount, error) {
+	return a.repo.CountPatientsByVirus(from, to)
+}
+
+func (a *App) CountVisitsByReason(from, to time.Time) ([]models.StatisticsCount, error) {
+	return a.repo.CountVisitsByReason(from, to)
+}
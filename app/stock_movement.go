@@ -0,0 +1,11 @@
+package app
+
+import "shs/app/models"
+
+func (a *App) CreateStockMovement(sm models.StockMovement) (models.StockMovement, error) {
+	return a.repo.CreateStockMovement(sm)
+}
+
+func (a *App) ListMedicineStockMovements(medicineId uint) ([]models.StockMovement, error) {
+	return a.repo.ListMedicineStockMovements(medicineId)
+}
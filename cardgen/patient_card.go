@@ -46,10 +46,11 @@ func (mwc *Buffer) Close() error {
 }
 
 type PatientCardGenerator struct {
-	writer  io.WriteCloser
-	patient models.Patient
-	ttf     *opentype.Font
-	boldttf *opentype.Font
+	writer   io.WriteCloser
+	patient  models.Patient
+	severity string
+	ttf      *opentype.Font
+	boldttf  *opentype.Font
 
 	baseImage   draw.Image
 	lastDrawnAt image.Point
@@ -89,6 +90,11 @@ func New(writer io.WriteCloser, patient models.Patient) (*PatientCardGenerator,
 	return p, nil
 }
 
+// WithSeverity adds the patient's hemophilia severity to the card.
+func (p *PatientCardGenerator) WithSeverity(severity string) {
+	p.severity = severity
+}
+
 type qrVersionData struct {
 	maxLength    int
 	totalModules int
@@ -248,6 +254,20 @@ func (p *PatientCardGenerator) Generate(rtl bool) error {
 		return err
 	}
 
+	if p.severity == "" {
+		return nil
+	}
+
+	p.lastDrawnAt.Y += 50
+	p.lastDrawnAt.X = oldX
+
+	if err := p.drawText("Severity: ", true, p.lastDrawnAt); err != nil {
+		return err
+	}
+	if err := p.drawText(p.severity, false, p.lastDrawnAt); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -0,0 +1,109 @@
+// hl7import imports the lab results of the HL7 v2 ORU^R01 messages in a
+// directory's .hl7 files as pending blood test results, and moves each
+// imported file into the imported directory, so that a file isn't imported
+// twice when the directory is imported again, where a file that failed is
+// imported again without its messages that were already imported.
+//
+// Usage:
+//
+//	hl7import -dir /path/to/lab/drop [-imported-dir /path/to/done]
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"shs/actions"
+	"shs/app"
+	"shs/config"
+	"shs/drivers"
+	"shs/jwt"
+	"shs/log"
+	"shs/memory"
+	"slices"
+	"strings"
+)
+
+func main() {
+	dir := flag.String("dir", "", "directory of the .hl7 files to import")
+	importedDir := flag.String("imported-dir", "", "directory that the imported files are moved to, defaults to the imported directory under -dir")
+	flag.Parse()
+
+	if *dir == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *importedDir == "" {
+		*importedDir = filepath.Join(*dir, "imported")
+	}
+
+	repo, err := drivers.NewRepository()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// the importer doesn't use the cache, and it's a memory one whatever the
+	// configured driver is, so that it doesn't touch the server's snapshot.
+	cache := memory.NewWithoutSnapshot()
+	usecases := actions.New(
+		app.New(repo, cache),
+		cache,
+		jwt.New[actions.TokenPayload](),
+	)
+
+	entries, err := os.ReadDir(*dir)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.EqualFold(filepath.Ext(entry.Name()), ".hl7") {
+			files = append(files, entry.Name())
+		}
+	}
+	slices.Sort(files)
+
+	err = os.MkdirAll(*importedDir, 0o755)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	failedFiles := 0
+	for _, file := range files {
+		path := filepath.Join(*dir, file)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Errorf("[HL7 IMPORT]: Failed to read %s, error: %s\n", path, err.Error())
+			failedFiles++
+			continue
+		}
+
+		payload, err := usecases.ImportHl7LabResults(actions.ImportHl7LabResultsParams{
+			Data:               data,
+			PatientIdAuthority: config.Env().Hl7.PatientIdAuthority,
+		})
+		if err != nil {
+			log.Errorf("[HL7 IMPORT]: Failed to import %s, error: %s\n", path, err.Error())
+			failedFiles++
+			continue
+		}
+
+		for _, imported := range payload.Imported {
+			log.Infof("[HL7 IMPORT]: %s: message %s: imported %d fields of %s for patient %s as pending result %d\n",
+				file, imported.MessageControlId, imported.FieldsCount, imported.BloodTestName, imported.PatientPublicId, imported.BloodTestResultId)
+		}
+		for _, skipped := range payload.Skipped {
+			log.Warningf("[HL7 IMPORT]: %s: message %s: skipped %q: %s\n", file, skipped.MessageControlId, skipped.Code, skipped.Reason)
+		}
+
+		err = os.Rename(path, filepath.Join(*importedDir, file))
+		if err != nil {
+			log.Errorf("[HL7 IMPORT]: Failed to move %s, error: %s\n", path, err.Error())
+			failedFiles++
+		}
+	}
+
+	log.Infof("[HL7 IMPORT]: Imported %d of %d files\n", len(files)-failedFiles, len(files))
+	if failedFiles > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"shs/actions"
+	"shs/config"
+	"shs/log"
+	"time"
+)
+
+// runMedicineAlertsJob scans the medicines once on startup, and then once a day
+// at midnight UTC, it's meant to be run in its own goroutine.
+func runMedicineAlertsJob(usecases *actions.Actions) {
+	for {
+		payload, err := usecases.ScanMedicineAlerts(actions.ScanMedicineAlertsParams{
+			Now:               time.Now().UTC(),
+			ExpiryWindowsDays: config.Env().MedicineAlerts.ExpiryWindowsDays,
+			ReorderThreshold:  config.Env().MedicineAlerts.ReorderThreshold,
+		})
+		if err != nil {
+			log.Errorf("[MEDICINE ALERTS JOB]: Failed to scan medicines, error: %s\n", err.Error())
+		} else {
+			log.Infof("[MEDICINE ALERTS JOB]: Raised %d alerts\n", payload.AlertsCount)
+		}
+
+		now := time.Now().UTC()
+		nextRun := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+		time.Sleep(nextRun.Sub(now))
+	}
+}
@@ -1,14 +1,19 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"os"
+	"os/signal"
 	"regexp"
 	"shs/actions"
 	"shs/app"
 	"shs/config"
+	"shs/drivers"
 	"shs/handlers/apis"
 	"shs/handlers/middlewares/auth"
+	"shs/handlers/middlewares/clientinfo"
 	"shs/handlers/middlewares/contenttype"
 	"shs/handlers/middlewares/ismobile"
 	"shs/handlers/middlewares/logger"
@@ -22,8 +27,8 @@ import (
 	"shs/handlers/web/static"
 	"shs/jwt"
 	"shs/log"
-	"shs/mariadb"
-	"shs/redis"
+	"syscall"
+	"time"
 
 	"github.com/tdewolff/minify/v2"
 	"github.com/tdewolff/minify/v2/css"
@@ -36,12 +41,14 @@ import (
 
 var appVersion = os.Getenv("VERSION")
 
+const shutdownTimeout = 10 * time.Second
+
 func main() {
-	repo, err := mariadb.New()
+	repo, err := drivers.NewRepository()
 	if err != nil {
 		log.Fatalln(err)
 	}
-	cache := redis.New()
+	cache := drivers.NewCache()
 	app := app.New(repo, cache)
 	jwtUtil := jwt.New[actions.TokenPayload]()
 	usecases := actions.New(
@@ -76,14 +83,19 @@ func main() {
 	pagesHandler.HandleFunc("GET /login", contenttype.Html(webAuthMiddleware.AuthPage(pages.HandleLoginPage)))
 	pagesHandler.HandleFunc("GET /viruses", contenttype.Html(webAuthMiddleware.AuthPage(pages.HandleVirusesPage)))
 	pagesHandler.HandleFunc("GET /visits", contenttype.Html(webAuthMiddleware.AuthPage(pages.HandleVisitsPage)))
+	pagesHandler.HandleFunc("GET /visits/agenda", contenttype.Html(webAuthMiddleware.AuthPage(pages.HandleAgendaPage)))
 	pagesHandler.HandleFunc("GET /medicines", contenttype.Html(webAuthMiddleware.AuthPage(pages.HandleMedicinesPage)))
 	pagesHandler.HandleFunc("GET /medicines/logs", contenttype.Html(webAuthMiddleware.AuthPage(pages.HandleMedicinesUseLogsPage)))
 	pagesHandler.HandleFunc("GET /medicine/{id}", contenttype.Html(webAuthMiddleware.AuthPage(pages.HandleMedicinePage)))
 	pagesHandler.HandleFunc("GET /blood-tests", contenttype.Html(webAuthMiddleware.AuthPage(pages.HandleBloodTestsPage)))
 	pagesHandler.HandleFunc("GET /blood-test/{id}", contenttype.Html(webAuthMiddleware.AuthPage(pages.HandleBloodTestPage)))
+	pagesHandler.HandleFunc("GET /blood-tests/out-of-range", contenttype.Html(webAuthMiddleware.AuthPage(pages.HandleOutOfRangeBloodTestResultsPage)))
 	pagesHandler.HandleFunc("GET /management", contenttype.Html(webAuthMiddleware.AuthPage(pages.HandleManagementPage)))
 	pagesHandler.HandleFunc("GET /management/account/{id}", contenttype.Html(webAuthMiddleware.AuthPage(pages.HandleAccountManagementPage)))
+	pagesHandler.HandleFunc("GET /management/audit-events", contenttype.Html(webAuthMiddleware.AuthPage(pages.HandleAuditEventsPage)))
 	pagesHandler.HandleFunc("GET /patients", contenttype.Html(webAuthMiddleware.AuthPage(pages.HandlePatientsPage)))
+	pagesHandler.HandleFunc("GET /patients/target-joints", contenttype.Html(webAuthMiddleware.AuthPage(pages.HandleTargetJointsPage)))
+	pagesHandler.HandleFunc("GET /patients/inhibitor-screenings-due", contenttype.Html(webAuthMiddleware.AuthPage(pages.HandleInhibitorScreeningsDuePage)))
 	pagesHandler.HandleFunc("GET /patient/{id}", contenttype.Html(webAuthMiddleware.AuthPage(pages.HandlePatientPage)))
 	pagesHandler.HandleFunc("GET /patient/{id}/blood-test-result/{btr_id}", contenttype.Html(webAuthMiddleware.AuthPage(pages.HandlePatientBloodTestResultPage)))
 	pagesHandler.HandleFunc("GET /patient/{id}/visit/{visit_id}", contenttype.Html(webAuthMiddleware.AuthPage(pages.HandlePatientVisitPage)))
@@ -105,6 +117,9 @@ func main() {
 	addressApi := apis.NewAddressApi(usecases)
 	patientApi := apis.NewPatientApi(usecases)
 	diagnosisApi := apis.NewDiagnosisApi(usecases)
+	statisticsApi := apis.NewStatisticsApi(usecases)
+	appointmentApi := apis.NewAppointmentApi(usecases)
+	auditEventApi := apis.NewAuditEventApi(usecases)
 
 	v1ApisHandler := http.NewServeMux()
 	v1ApisHandler.HandleFunc("POST /login/username", emailLoginApi.HandleUsernameLogin)
@@ -124,6 +139,13 @@ func main() {
 	v1ApisHandler.HandleFunc("GET /bloodtests/{id}", authMiddleware.AuthApi(bloodTestApi.HandleGetBloodTest))
 	v1ApisHandler.HandleFunc("GET /bloodtests", authMiddleware.AuthApi(bloodTestApi.HandleListBloodTests))
 	v1ApisHandler.HandleFunc("DELETE /bloodtests/{id}", authMiddleware.AuthApi(bloodTestApi.HandleDeleteBloodTest))
+	v1ApisHandler.HandleFunc("POST /bloodtests/{id}/fields/{field_id}/reference-ranges", authMiddleware.AuthApi(bloodTestApi.HandleCreateBloodTestReferenceRange))
+	v1ApisHandler.HandleFunc("DELETE /bloodtests/reference-ranges/{id}", authMiddleware.AuthApi(bloodTestApi.HandleDeleteBloodTestReferenceRange))
+	v1ApisHandler.HandleFunc("PUT /bloodtests/{id}/fields/{field_id}/loinc-code", authMiddleware.AuthApi(bloodTestApi.HandleUpdateBloodTestFieldLoincCode))
+	v1ApisHandler.HandleFunc("GET /bloodtests/out-of-range", authMiddleware.AuthApi(bloodTestApi.HandleListOutOfRangeBloodTestResults))
+	v1ApisHandler.HandleFunc("POST /bloodtests/lab-code-mappings", authMiddleware.AuthApi(bloodTestApi.HandleCreateLabCodeMapping))
+	v1ApisHandler.HandleFunc("GET /bloodtests/lab-code-mappings", authMiddleware.AuthApi(bloodTestApi.HandleListLabCodeMappings))
+	v1ApisHandler.HandleFunc("DELETE /bloodtests/lab-code-mappings/{id}", authMiddleware.AuthApi(bloodTestApi.HandleDeleteLabCodeMapping))
 
 	v1ApisHandler.HandleFunc("POST /diagnoses", authMiddleware.AuthApi(diagnosisApi.HandleCreateDiagnosis))
 	v1ApisHandler.HandleFunc("GET /diagnoses", authMiddleware.AuthApi(diagnosisApi.HandleListDiagnosiss))
@@ -135,9 +157,13 @@ func main() {
 
 	v1ApisHandler.HandleFunc("POST /medicines", authMiddleware.AuthApi(medicineApi.HandleCreateMedicine))
 	v1ApisHandler.HandleFunc("GET /medicines", authMiddleware.AuthApi(medicineApi.HandleListMedicines))
+	v1ApisHandler.HandleFunc("GET /medicines/alerts", authMiddleware.AuthApi(medicineApi.HandleListMedicineAlerts))
+	v1ApisHandler.HandleFunc("POST /medicines/dose-calculation", authMiddleware.AuthApi(medicineApi.HandleCalculateFactorDose))
 	v1ApisHandler.HandleFunc("GET /medicines/{id}", authMiddleware.AuthApi(medicineApi.HandleGetMedicine))
 	v1ApisHandler.HandleFunc("PUT /medicines/{id}/amount", authMiddleware.AuthApi(medicineApi.HandleUpdateMedicineAmount))
 	v1ApisHandler.HandleFunc("DELETE /medicines/{id}", authMiddleware.AuthApi(medicineApi.HandleDeleteMedicine))
+	v1ApisHandler.HandleFunc("POST /medicines/{id}/stock-movements", authMiddleware.AuthApi(medicineApi.HandleCreateMedicineStockMovement))
+	v1ApisHandler.HandleFunc("GET /medicines/{id}/stock-movements", authMiddleware.AuthApi(medicineApi.HandleListMedicineStockMovements))
 
 	v1ApisHandler.HandleFunc(
 		"GET /addresses/goveronate/{goveronate}/suburb/{suburb}/street/{street}",
@@ -160,11 +186,38 @@ func main() {
 	v1ApisHandler.HandleFunc("POST /patients/{id}/joints-evaluation", authMiddleware.AuthApi(patientApi.HandleCreatePatientJointsEvaluation))
 	v1ApisHandler.HandleFunc("GET /patients/{id}/joints-evaluations", authMiddleware.AuthApi(patientApi.HandleListPatientJointsEvaluations))
 	v1ApisHandler.HandleFunc("GET /patients/{id}/visits", authMiddleware.AuthApi(patientApi.HandleListPatientVisits))
+	v1ApisHandler.HandleFunc("POST /patients/{id}/bleeding-episodes", authMiddleware.AuthApi(patientApi.HandleCreatePatientBleedingEpisode))
+	v1ApisHandler.HandleFunc("GET /patients/{id}/bleeding-episodes", authMiddleware.AuthApi(patientApi.HandleListPatientBleedingEpisodes))
+	v1ApisHandler.HandleFunc("DELETE /patients/{id}/bleeding-episodes/{be_id}", authMiddleware.AuthApi(patientApi.HandleDeletePatientBleedingEpisode))
+	v1ApisHandler.HandleFunc("GET /patients/{id}/bleeding-rates", authMiddleware.AuthApi(patientApi.HandleGetPatientBleedingRates))
+	v1ApisHandler.HandleFunc("GET /patients/{id}/target-joints", authMiddleware.AuthApi(patientApi.HandleGetPatientTargetJoints))
+	v1ApisHandler.HandleFunc("GET /patients/target-joints", authMiddleware.AuthApi(patientApi.HandleListPatientsWithTargetJoints))
+	v1ApisHandler.HandleFunc("GET /patients/{id}/blood-test-trends", authMiddleware.AuthApi(patientApi.HandleListPatientBloodTestTrends))
+	v1ApisHandler.HandleFunc("GET /patients/{id}/fhir", authMiddleware.AuthApi(patientApi.HandleExportPatientFhir))
+	v1ApisHandler.HandleFunc("GET /patients/{id}/inhibitors", authMiddleware.AuthApi(patientApi.HandleGetPatientInhibitorSurveillance))
+	v1ApisHandler.HandleFunc("PUT /patients/{id}/inhibitors", authMiddleware.AuthApi(patientApi.HandleUpdatePatientInhibitorSurveillance))
+	v1ApisHandler.HandleFunc("GET /patients/inhibitor-screenings-due", authMiddleware.AuthApi(patientApi.HandleListInhibitorScreeningsDue))
+	v1ApisHandler.HandleFunc("GET /patients/{id}/prophylaxis-adherence", authMiddleware.AuthApi(patientApi.HandleGetPatientProphylaxisAdherence))
+	v1ApisHandler.HandleFunc("GET /patients/{id}/revisions", authMiddleware.AuthApi(patientApi.HandleListPatientRevisions))
+	v1ApisHandler.HandleFunc("PUT /patients/{id}/revisions/{revision_id}/restore", authMiddleware.AuthApi(patientApi.HandleRestorePatientRevision))
 
 	// TODO: separate this from admin patient endpoints
 	v1ApisHandler.HandleFunc("POST /patients/visit/{visit_id}/medicine/{med_id}", authMiddleware.AuthApi(patientApi.HandleUsePrescribedMedicineForVisit))
 
 	v1ApisHandler.HandleFunc("GET /me/patient/last-visit", authMiddleware.AuthApi(patientApi.HandleGetPatientLastVisit))
+	v1ApisHandler.HandleFunc("POST /me/patient/bleeding-episodes", authMiddleware.AuthApi(patientApi.HandleReportOwnBleedingEpisode))
+	v1ApisHandler.HandleFunc("GET /me/patient/calendar-token", authMiddleware.AuthApi(patientApi.HandleGetOwnCalendarFeedToken))
+	v1ApisHandler.HandleFunc("GET /me/patient/calendar.ics", patientApi.HandleGetOwnCalendarFeed)
+
+	v1ApisHandler.HandleFunc("POST /appointments", authMiddleware.AuthApi(appointmentApi.HandleCreateAppointment))
+	v1ApisHandler.HandleFunc("GET /appointments", authMiddleware.AuthApi(appointmentApi.HandleListAppointmentsAgenda))
+	v1ApisHandler.HandleFunc("PUT /appointments/{id}/status", authMiddleware.AuthApi(appointmentApi.HandleUpdateAppointmentStatus))
+	v1ApisHandler.HandleFunc("GET /patients/{id}/appointments", authMiddleware.AuthApi(appointmentApi.HandleListPatientAppointments))
+
+	v1ApisHandler.HandleFunc("GET /statistics", authMiddleware.AuthApi(statisticsApi.HandleGetStatistics))
+
+	v1ApisHandler.HandleFunc("GET /audit-events", authMiddleware.AuthApi(auditEventApi.HandleListAuditEvents))
+	v1ApisHandler.HandleFunc("GET /audit-events/export", authMiddleware.AuthApi(auditEventApi.HandleExportAuditEvents))
 
 	if config.Env().GoEnv == config.GoEnvTest || config.Env().GoEnv == config.GoEnvDev {
 		v1ApisHandler.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
@@ -212,6 +265,8 @@ func main() {
 	patientWebApi := webapis.NewPatientApi(usecases)
 	diagnosisWebApi := webapis.NewDiagnosisApi(usecases)
 	visitWebApi := webapis.NewVisitApi(usecases)
+	appointmentWebApi := webapis.NewAppointmentApi(usecases)
+	auditEventWebApi := webapis.NewAuditEventApi(usecases)
 
 	webApisHandler := http.NewServeMux()
 	webApisHandler.HandleFunc("POST /login/username", usernameLoginWebApi.HandleUsernameLogin)
@@ -223,10 +278,14 @@ func main() {
 	webApisHandler.HandleFunc("POST /medicine", webAuthMiddleware.AuthApi(medicineWebApi.HandleCreateMedicine))
 	webApisHandler.HandleFunc("DELETE /medicine/{id}", webAuthMiddleware.AuthApi(medicineWebApi.HandleDeleteMedicine))
 	webApisHandler.HandleFunc("PUT /medicine/{id}", webAuthMiddleware.AuthApi(medicineWebApi.HandleUpdateMedicine))
+	webApisHandler.HandleFunc("POST /medicine/{id}/stock-movement", webAuthMiddleware.AuthApi(medicineWebApi.HandleCreateMedicineStockMovement))
 
 	webApisHandler.HandleFunc("POST /blood-test", webAuthMiddleware.AuthApi(bloodTestWebApi.HandleCreateBloodTest))
 	webApisHandler.HandleFunc("DELETE /blood-test/{id}", webAuthMiddleware.AuthApi(bloodTestWebApi.HandleDeleteBloodTest))
 	webApisHandler.HandleFunc("PUT /blood-test/{id}/display", webAuthMiddleware.AuthApi(bloodTestWebApi.HandleToggleBloodTestDisplay))
+	webApisHandler.HandleFunc("POST /blood-test/{id}/reference-range", webAuthMiddleware.AuthApi(bloodTestWebApi.HandleCreateBloodTestReferenceRange))
+	webApisHandler.HandleFunc("DELETE /blood-test/reference-range/{id}", webAuthMiddleware.AuthApi(bloodTestWebApi.HandleDeleteBloodTestReferenceRange))
+	webApisHandler.HandleFunc("PUT /blood-test/{id}/loinc-code", webAuthMiddleware.AuthApi(bloodTestWebApi.HandleUpdateBloodTestFieldLoincCode))
 
 	webApisHandler.HandleFunc("POST /diagnosis", webAuthMiddleware.AuthApi(diagnosisWebApi.HandleCreateDiagnosis))
 	webApisHandler.HandleFunc("DELETE /diagnosis/{id}", webAuthMiddleware.AuthApi(diagnosisWebApi.HandleDeleteDiagnosis))
@@ -247,25 +306,39 @@ func main() {
 	webApisHandler.HandleFunc("PUT /patient/{id}/prophylaxis/{pp_id}/end", webAuthMiddleware.AuthApi(patientWebApi.HandleEndPatientProphylaxis))
 	webApisHandler.HandleFunc("PUT /patient/{id}/prophylaxis/{pp_id}/mark-chosen", webAuthMiddleware.AuthApi(patientWebApi.HandleMarkPatientProphylaxisAsChosen))
 	webApisHandler.HandleFunc("DELETE /patient/{id}/prophylaxis/{pp_id}", webAuthMiddleware.AuthApi(patientWebApi.HandleDeletePatientProphylaxis))
+	webApisHandler.HandleFunc("POST /patient/{id}/bleeding-episode", webAuthMiddleware.AuthApi(patientWebApi.HandleCreatePatientBleedingEpisode))
+	webApisHandler.HandleFunc("DELETE /patient/{id}/bleeding-episode/{be_id}", webAuthMiddleware.AuthApi(patientWebApi.HandleDeletePatientBleedingEpisode))
+	webApisHandler.HandleFunc("PUT /patient/{id}/inhibitor-surveillance", webAuthMiddleware.AuthApi(patientWebApi.HandleUpdatePatientInhibitorSurveillance))
+	webApisHandler.HandleFunc("PUT /patient/{id}/revision/{revision_id}/restore", webAuthMiddleware.AuthApi(patientWebApi.HandleRestorePatientRevision))
 	webApisHandler.HandleFunc("POST /patient/visit/{visit_id}/use-medicine", webAuthMiddleware.AuthApi(patientWebApi.HandlePatientUseMedicine))
+	webApisHandler.HandleFunc("POST /patient/visit/{visit_id}/bleeding-episode", webAuthMiddleware.AuthApi(patientWebApi.HandleReportOwnBleedingEpisode))
 	webApisHandler.HandleFunc("DELETE /patient/{id}", webAuthMiddleware.AuthApi(patientWebApi.HandleDeletePatient))
 	webApisHandler.HandleFunc("POST /patients/import/csv", webAuthMiddleware.AuthApi(patientWebApi.HandleUploadImportPatientsFromCsv))
 
 	webApisHandler.HandleFunc("POST /visit/treatment", webAuthMiddleware.AuthApi(visitWebApi.HandleCreateTreatmentDetails))
 	webApisHandler.HandleFunc("DELETE /visit/treatment/{id}", webAuthMiddleware.AuthApi(visitWebApi.HandleDeleteTreatmentDetails))
 
+	webApisHandler.HandleFunc("POST /appointment", webAuthMiddleware.AuthApi(appointmentWebApi.HandleCreateAppointment))
+	webApisHandler.HandleFunc("PUT /appointment/{id}/status/{status}", webAuthMiddleware.AuthApi(appointmentWebApi.HandleUpdateAppointmentStatus))
+
+	webApisHandler.HandleFunc("GET /audit-events/export", webAuthMiddleware.AuthApi(auditEventWebApi.HandleExportAuditEvents))
+
 	///
 	/// HTMX APIS
 	///
 
 	patientHtmx := webhtmx.NewPatientHtmx(usecases)
 	visitHtmx := webhtmx.NewVisitHtmx(usecases)
+	statisticsHtmx := webhtmx.NewStatisticsHtmx(usecases)
+	doseHtmx := webhtmx.NewDoseHtmx(usecases)
 
 	htmxHandler := http.NewServeMux()
 	htmxHandler.HandleFunc("POST /patient/find", webAuthMiddleware.AuthApi(patientHtmx.HandleFindPatients))
 	htmxHandler.HandleFunc("GET /patient/{id}/view", webAuthMiddleware.AuthApi(patientHtmx.HandlePatientDetailsView))
 	htmxHandler.HandleFunc("GET /patient/{id}/update", webAuthMiddleware.AuthApi(patientHtmx.HandlePatientUpdateView))
 	htmxHandler.HandleFunc("POST /visits/find", webAuthMiddleware.AuthApi(visitHtmx.HandleFindVisits))
+	htmxHandler.HandleFunc("POST /statistics/find", webAuthMiddleware.AuthApi(statisticsHtmx.HandleFindStatistics))
+	htmxHandler.HandleFunc("POST /dose/calculate", webAuthMiddleware.AuthApi(doseHtmx.HandleCalculateDose))
 
 	applicationHandler := http.NewServeMux()
 	applicationHandler.Handle("/", version.Handler(appVersion, webi18n.Handler(ismobile.Handler(webtheme.Handler(pagesHandler)))))
@@ -274,11 +347,45 @@ func main() {
 	applicationHandler.Handle("/api/web/", webi18n.Handler(ismobile.Handler(webtheme.Handler(http.StripPrefix("/api/web", webApisHandler)))))
 	applicationHandler.Handle("/htmx/", webi18n.Handler(ismobile.Handler(webtheme.Handler(http.StripPrefix("/htmx", htmxHandler)))))
 
-	log.Info("Starting http server at port " + config.Env().Port)
+	go runMedicineAlertsJob(usecases)
+
+	var handler http.Handler
 	switch config.Env().GoEnv {
 	case config.GoEnvBeta, config.GoEnvDev, config.GoEnvTest:
-		log.Fatalln(http.ListenAndServe(":"+config.Env().Port, logger.Handler(applicationHandler)))
+		handler = logger.Handler(clientinfo.Handler(applicationHandler))
 	case config.GoEnvProd:
-		log.Fatalln(http.ListenAndServe(":"+config.Env().Port, minifyer.Middleware(applicationHandler)))
+		handler = minifyer.Middleware(clientinfo.Handler(applicationHandler))
+	}
+	server := &http.Server{
+		Addr:    ":" + config.Env().Port,
+		Handler: handler,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		log.Info("Starting http server at port " + config.Env().Port)
+		err := server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalln(err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Info("Shutting down http server")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	err = server.Shutdown(shutdownCtx)
+	if err != nil {
+		log.Errorf("Failed to shut down the http server gracefully, error: %s\n", err.Error())
+	}
+
+	// the cache is closed after the server, so that the memory cache's final
+	// snapshot has the sessions of the requests that were in flight.
+	err = cache.Close()
+	if err != nil {
+		log.Errorf("Failed to close the cache, error: %s\n", err.Error())
 	}
 }
@@ -1,13 +1,142 @@
+// migrator migrates the configured database's schema with its numbered
+// migrations in mariadb/migrations or sqlite/migrations, where running it
+// without a command applies all of the pending migrations, and the baseline
+// can't be rolled back, since it would drop all of the data.
+//
+// Usage:
+//
+//	migrator up [-to VERSION]
+//	migrator down [-steps N]
+//	migrator status
+//	migrator new NAME
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+	"shs/config"
+	"shs/dbmigrate"
 	"shs/log"
 	"shs/mariadb"
+	"shs/sqlite"
+	"text/tabwriter"
+	"time"
 )
 
+// database has the configured database's migrations.
+type database struct {
+	Migrate          func() error
+	MigrateUp        func(toVersion uint) error
+	MigrateDown      func(steps int) error
+	MigrationsStatus func() ([]dbmigrate.Status, error)
+}
+
+func configuredDatabase() database {
+	switch config.Env().DB.Driver {
+	case config.DBDriverSqlite:
+		return database{
+			Migrate:          sqlite.Migrate,
+			MigrateUp:        sqlite.MigrateUp,
+			MigrateDown:      sqlite.MigrateDown,
+			MigrationsStatus: sqlite.MigrationsStatus,
+		}
+	default:
+		return database{
+			Migrate:          mariadb.Migrate,
+			MigrateUp:        mariadb.MigrateUp,
+			MigrateDown:      mariadb.MigrateDown,
+			MigrationsStatus: mariadb.MigrationsStatus,
+		}
+	}
+}
+
 func main() {
-	err := mariadb.Migrate()
+	command := "up"
+	args := os.Args[1:]
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	var err error
+	switch command {
+	case "up":
+		err = up(args)
+	case "down":
+		err = down(args)
+	case "status":
+		err = status(args)
+	case "new":
+		err = newMigration(args)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q, expected one of up, down, status or new\n", command)
+		os.Exit(2)
+	}
 	if err != nil {
 		log.Fatalln(err)
 	}
 }
+
+func up(args []string) error {
+	flags := flag.NewFlagSet("up", flag.ExitOnError)
+	to := flags.Uint("to", 0, "version to migrate up to, defaults to the latest version")
+	_ = flags.Parse(args)
+
+	if *to != 0 {
+		return configuredDatabase().MigrateUp(*to)
+	}
+
+	return configuredDatabase().Migrate()
+}
+
+func down(args []string) error {
+	flags := flag.NewFlagSet("down", flag.ExitOnError)
+	steps := flags.Int("steps", 1, "number of the latest applied migrations to roll back")
+	_ = flags.Parse(args)
+
+	return configuredDatabase().MigrateDown(*steps)
+}
+
+func status(args []string) error {
+	flags := flag.NewFlagSet("status", flag.ExitOnError)
+	_ = flags.Parse(args)
+
+	statuses, err := configuredDatabase().MigrationsStatus()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, s := range statuses {
+		appliedAt := "-"
+		if !s.AppliedAt.IsZero() {
+			appliedAt = s.AppliedAt.Format(time.DateTime)
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, s.State, appliedAt)
+	}
+
+	return w.Flush()
+}
+
+// newMigration creates the migration in both of the databases' migrations, so
+// that a schema change isn't made to only one of them.
+func newMigration(args []string) error {
+	flags := flag.NewFlagSet("new", flag.ExitOnError)
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: migrator new NAME")
+		os.Exit(2)
+	}
+
+	files, err := dbmigrate.New(flags.Arg(0), mariadb.MigrationsDir, sqlite.MigrationsDir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		fmt.Println(file)
+	}
+
+	return nil
+}
@@ -1,8 +1,12 @@
 package config
 
 import (
+	"net/netip"
 	"os"
 	"shs/log"
+	"strconv"
+	"strings"
+	"time"
 )
 
 var (
@@ -10,6 +14,36 @@ var (
 )
 
 func initEnvVars() {
+	dbDriver := DBDriver(getEnvOr("DB_DRIVER", string(DBDriverMariaDB)))
+	switch dbDriver {
+	case DBDriverMariaDB, DBDriverSqlite:
+	default:
+		log.Fatalln("The \"DB_DRIVER\" variable is not one of mariadb or sqlite.")
+	}
+	// getMariaDBEnv is like getEnv, but MariaDB's variables are only
+	// required when it's the database.
+	getMariaDBEnv := func(key string) string {
+		if dbDriver != DBDriverMariaDB {
+			return os.Getenv(key)
+		}
+		return getEnv(key)
+	}
+
+	cacheDriver := CacheDriver(getEnvOr("CACHE_DRIVER", string(CacheDriverRedis)))
+	switch cacheDriver {
+	case CacheDriverRedis, CacheDriverMemory:
+	default:
+		log.Fatalln("The \"CACHE_DRIVER\" variable is not one of redis or memory.")
+	}
+	// getRedisEnv is like getEnv, but Redis' variables are only required when
+	// it's the cache.
+	getRedisEnv := func(key string) string {
+		if cacheDriver != CacheDriverRedis {
+			return os.Getenv(key)
+		}
+		return getEnv(key)
+	}
+
 	_config = config{
 		Port:      getEnv("PORT"),
 		GoEnv:     GoEnv(getEnv("GO_ENV")),
@@ -17,22 +51,32 @@ func initEnvVars() {
 		JwtSecret: getEnv("JWT_SECRET"),
 		BlobsDir:  getEnv("BLOBS_DIR"),
 		DB: struct {
+			Driver   DBDriver
 			Name     string
 			Host     string
 			Username string
 			Password string
+			Path     string
 		}{
-			Name:     getEnv("DB_NAME"),
-			Host:     getEnv("DB_HOST"),
-			Username: getEnv("DB_USERNAME"),
-			Password: getEnv("DB_PASSWORD"),
+			Driver:   dbDriver,
+			Name:     getMariaDBEnv("DB_NAME"),
+			Host:     getMariaDBEnv("DB_HOST"),
+			Username: getMariaDBEnv("DB_USERNAME"),
+			Password: getMariaDBEnv("DB_PASSWORD"),
+			Path:     getEnvOr("DB_PATH", "shs.db"),
 		},
 		Cache: struct {
-			Host     string
-			Password string
+			Driver           CacheDriver
+			Host             string
+			Password         string
+			SnapshotPath     string
+			SnapshotInterval time.Duration
 		}{
-			Host:     getEnv("CACHE_HOST"),
-			Password: getEnv("CACHE_PASSWORD"),
+			Driver:           cacheDriver,
+			Host:             getRedisEnv("CACHE_HOST"),
+			Password:         getRedisEnv("CACHE_PASSWORD"),
+			SnapshotPath:     os.Getenv("CACHE_SNAPSHOT_PATH"),
+			SnapshotInterval: getEnvDuration("CACHE_SNAPSHOT_INTERVAL", "1m"),
 		},
 		SuperAdmin: struct {
 			Username string
@@ -41,6 +85,19 @@ func initEnvVars() {
 			Username: getEnv("SUPERADMIN_USERNAME"),
 			Password: getEnv("SUPERADMIN_PASSWORD"),
 		},
+		MedicineAlerts: struct {
+			ExpiryWindowsDays []int
+			ReorderThreshold  int
+		}{
+			ExpiryWindowsDays: getEnvInts("MEDICINE_EXPIRY_WINDOWS_DAYS", "90,30,7"),
+			ReorderThreshold:  getEnvInt("MEDICINE_REORDER_THRESHOLD", "10"),
+		},
+		TrustedProxies: getEnvPrefixes("TRUSTED_PROXIES"),
+		Hl7: struct {
+			PatientIdAuthority string
+		}{
+			PatientIdAuthority: os.Getenv("HL7_PATIENT_ID_AUTHORITY"),
+		},
 	}
 }
 
@@ -53,6 +110,27 @@ const (
 	GoEnvTest GoEnv = "test"
 )
 
+// DBDriver is the database that the app's data is stored in.
+type DBDriver string
+
+const (
+	DBDriverMariaDB DBDriver = "mariadb"
+	// DBDriverSqlite stores the data in a single file, for a small clinic that
+	// runs the app on a single machine.
+	DBDriverSqlite DBDriver = "sqlite"
+)
+
+// CacheDriver is where the sessions and the other short lived values are
+// cached.
+type CacheDriver string
+
+const (
+	CacheDriverRedis CacheDriver = "redis"
+	// CacheDriverMemory caches the values in the server's memory, which only
+	// works with a single server.
+	CacheDriverMemory CacheDriver = "memory"
+)
+
 type config struct {
 	Port      string
 	GoEnv     GoEnv
@@ -60,19 +138,44 @@ type config struct {
 	JwtSecret string
 	BlobsDir  string
 	DB        struct {
+		Driver   DBDriver
 		Name     string
 		Host     string
 		Username string
 		Password string
+		// Path is the SQLite database's file.
+		Path string
 	}
 	Cache struct {
+		Driver   CacheDriver
 		Host     string
 		Password string
+		// SnapshotPath is the file that the memory cache is saved to, so that
+		// the sessions survive a restart, where an empty path doesn't save it.
+		SnapshotPath     string
+		SnapshotInterval time.Duration
 	}
 	SuperAdmin struct {
 		Username string
 		Password string
 	}
+	MedicineAlerts struct {
+		// ExpiryWindowsDays are the days before a batch's expiry date at which
+		// an alert is raised.
+		ExpiryWindowsDays []int
+		// ReorderThreshold is the amount of packages under which a medicine
+		// needs to be reordered.
+		ReorderThreshold int
+	}
+	// TrustedProxies are the reverse proxies' addresses, whose
+	// X-Forwarded-For is trusted to have the clients' addresses.
+	TrustedProxies []netip.Prefix
+	Hl7            struct {
+		// PatientIdAuthority is the assigning authority of the patients'
+		// public ids in the imported lab results, where the ids that other
+		// authorities assigned aren't taken as public ids.
+		PatientIdAuthority string
+	}
 }
 
 // Env returns the thing's config values :)
@@ -87,3 +190,64 @@ func getEnv(key string) string {
 	}
 	return value
 }
+
+// getEnvOr is like getEnv, but returns the fallback value instead of failing
+// when the variable is not set.
+func getEnvOr(key, fallback string) string {
+	value := os.Getenv(key)
+	if len(value) == 0 {
+		return fallback
+	}
+	return value
+}
+
+func getEnvInt(key, fallback string) int {
+	value, err := strconv.Atoi(getEnvOr(key, fallback))
+	if err != nil {
+		log.Fatalln("The \"" + key + "\" variable is not a number.")
+	}
+	return value
+}
+
+func getEnvDuration(key, fallback string) time.Duration {
+	value, err := time.ParseDuration(getEnvOr(key, fallback))
+	if err != nil {
+		log.Fatalln("The \"" + key + "\" variable is not a duration.")
+	}
+	return value
+}
+
+func getEnvInts(key, fallback string) []int {
+	rawValues := strings.Split(getEnvOr(key, fallback), ",")
+	values := make([]int, 0, len(rawValues))
+	for _, rawValue := range rawValues {
+		value, err := strconv.Atoi(strings.TrimSpace(rawValue))
+		if err != nil {
+			log.Fatalln("The \"" + key + "\" variable is not a comma separated list of numbers.")
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// getEnvPrefixes is a comma separated list of addresses or CIDRs, where an
+// address is the prefix of only itself.
+func getEnvPrefixes(key string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0)
+	for _, rawValue := range strings.Split(os.Getenv(key), ",") {
+		rawValue = strings.TrimSpace(rawValue)
+		if rawValue == "" {
+			continue
+		}
+		if addr, err := netip.ParseAddr(rawValue); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(rawValue)
+		if err != nil {
+			log.Fatalln("The \"" + key + "\" variable is not a comma separated list of addresses or CIDRs.")
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes
+}
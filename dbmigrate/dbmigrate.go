@@ -0,0 +1,411 @@
+// Package dbmigrate applies the numbered SQL migrations of a database's
+// schema, where each version has a NNNN_name.up.sql and a NNNN_name.down.sql
+// file, and the applied versions are recorded in the schema_migrations table.
+package dbmigrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Checksum is the up migration's checksum, which is recorded when it's
+// applied, so that a migration that was changed after it was applied is
+// detected.
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up))
+	return hex.EncodeToString(sum[:])
+}
+
+type schemaMigration struct {
+	Version   uint      `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:255;not null"`
+	Checksum  string    `gorm:"size:64;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// State is a migration's state in the database.
+type State string
+
+const (
+	StatePending  State = "pending"
+	StateApplied  State = "applied"
+	StateModified State = "modified"
+	StateMissing  State = "missing"
+)
+
+type Status struct {
+	Version   uint
+	Name      string
+	State     State
+	AppliedAt time.Time
+}
+
+// ErrModified is returned when an applied migration was changed after it was
+// applied, so that the schema isn't migrated any further until the change is
+// reverted or the migration is rolled back.
+type ErrModified struct {
+	Version uint
+	Name    string
+}
+
+func (e ErrModified) Error() string {
+	return fmt.Sprintf("migration %04d_%s was modified after it was applied", e.Version, e.Name)
+}
+
+// ErrMissing is returned when an applied migration doesn't exist in this
+// build, which happens when the database was migrated by a newer build.
+type ErrMissing struct {
+	Version uint
+}
+
+func (e ErrMissing) Error() string {
+	return fmt.Sprintf("migration %04d was applied, but it doesn't exist in this build", e.Version)
+}
+
+// Load reads the migrations in the file system's directory sorted by their
+// versions.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make(map[uint]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("invalid migration version in %q", entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := migrations[uint(version)]
+		if !ok {
+			m = &Migration{Version: uint(version), Name: match[2]}
+			migrations[uint(version)] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %04d has two names %q and %q", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	out := make([]Migration, 0, len(migrations))
+	for _, m := range migrations {
+		if strings.TrimSpace(m.Up) == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up migration", m.Version, m.Name)
+		}
+		out = append(out, *m)
+	}
+	slices.SortFunc(out, func(a, b Migration) int {
+		return int(a.Version) - int(b.Version)
+	})
+
+	return out, nil
+}
+
+func appliedMigrations(db *gorm.DB) (map[uint]schemaMigration, error) {
+	if !db.Migrator().HasTable(new(schemaMigration)) {
+		err := db.Migrator().CreateTable(new(schemaMigration))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var applied []schemaMigration
+	err := db.Model(new(schemaMigration)).Order("version ASC").Find(&applied).Error
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[uint]schemaMigration, len(applied))
+	for _, sm := range applied {
+		out[sm.Version] = sm
+	}
+
+	return out, nil
+}
+
+// Up applies the pending migrations up to and including the given version,
+// where a zero version applies all of them.
+//
+// MariaDB commits DDL statements implicitly, so a migration isn't applied in a
+// transaction, and a migration that fails midway has to be fixed by hand before
+// it's applied again.
+func Up(db *gorm.DB, migrations []Migration, toVersion uint) error {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+	for version := range applied {
+		if !slices.ContainsFunc(migrations, func(m Migration) bool { return m.Version == version }) {
+			return ErrMissing{Version: version}
+		}
+	}
+
+	for _, m := range migrations {
+		if toVersion != 0 && m.Version > toVersion {
+			break
+		}
+		if sm, ok := applied[m.Version]; ok {
+			if sm.Checksum != m.Checksum() {
+				return ErrModified{Version: m.Version, Name: m.Name}
+			}
+			continue
+		}
+
+		err = execMigration(db, m.Up)
+		if err != nil {
+			return fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+		err = db.Create(&schemaMigration{
+			Version:   m.Version,
+			Name:      m.Name,
+			Checksum:  m.Checksum(),
+			AppliedAt: time.Now().UTC(),
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Down rolls back the given number of the latest applied migrations, where a
+// migration with an empty down migration, e.g. the baseline that would drop
+// all of the data, can't be rolled back, and nothing is rolled back when any
+// of the steps can't be.
+func Down(db *gorm.DB, migrations []Migration, steps int) error {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	versions := make([]uint, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	slices.Sort(versions)
+	slices.Reverse(versions)
+
+	rollbacks := make([]Migration, 0)
+	for _, version := range versions[:min(max(steps, 0), len(versions))] {
+		idx := slices.IndexFunc(migrations, func(m Migration) bool { return m.Version == version })
+		if idx < 0 {
+			return ErrMissing{Version: version}
+		}
+		m := migrations[idx]
+		if strings.TrimSpace(m.Down) == "" {
+			return fmt.Errorf("migration %04d_%s can't be rolled back", m.Version, m.Name)
+		}
+		rollbacks = append(rollbacks, m)
+	}
+
+	for _, m := range rollbacks {
+		err = execMigration(db, m.Down)
+		if err != nil {
+			return fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+		err = db.Where("version = ?", m.Version).Delete(new(schemaMigration)).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Statuses lists the migrations and the applied migrations that don't exist
+// in this build sorted by their versions.
+func Statuses(db *gorm.DB, migrations []Migration) ([]Status, error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(migrations))
+	for _, m := range migrations {
+		status := Status{
+			Version: m.Version,
+			Name:    m.Name,
+			State:   StatePending,
+		}
+		if sm, ok := applied[m.Version]; ok {
+			status.State = StateApplied
+			status.AppliedAt = sm.AppliedAt
+			if sm.Checksum != m.Checksum() {
+				status.State = StateModified
+			}
+			delete(applied, m.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, sm := range applied {
+		statuses = append(statuses, Status{
+			Version:   sm.Version,
+			Name:      sm.Name,
+			State:     StateMissing,
+			AppliedAt: sm.AppliedAt,
+		})
+	}
+	slices.SortFunc(statuses, func(a, b Status) int {
+		return int(a.Version) - int(b.Version)
+	})
+
+	return statuses, nil
+}
+
+// New creates an empty up and down migration in each of the given
+// directories, which is versioned after the directories' latest migration,
+// so that the databases' migrations keep the same versions.
+func New(name string, dirs ...string) ([]string, error) {
+	if !regexp.MustCompile(`^\w+$`).MatchString(name) {
+		return nil, errors.New("a migration's name can only have letters, digits and underscores")
+	}
+
+	version := uint(1)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			match := migrationFileName.FindStringSubmatch(entry.Name())
+			if match == nil {
+				continue
+			}
+			v, _ := strconv.ParseUint(match[1], 10, 64)
+			version = max(version, uint(v)+1)
+		}
+	}
+
+	files := make([]string, 0, 2*len(dirs))
+	for _, dir := range dirs {
+		prefix := filepath.Join(dir, fmt.Sprintf("%04d_%s", version, name))
+		for _, file := range []string{prefix + ".up.sql", prefix + ".down.sql"} {
+			f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+			if err != nil {
+				return nil, err
+			}
+			_ = f.Close()
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
+
+func execMigration(db *gorm.DB, sql string) error {
+	for _, statement := range splitStatements(sql) {
+		err := db.Exec(statement).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// splitStatements splits the script on the semicolons that aren't in a quoted
+// string, identifier or a comment, so that a migration can have many
+// statements without enabling the driver's multiStatements.
+func splitStatements(sql string) []string {
+	statements := make([]string, 0)
+	var current strings.Builder
+	flush := func() {
+		statement := strings.TrimSpace(current.String())
+		if statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := i + 1
+			for end < len(sql) {
+				if sql[end] == '\\' && c != '`' {
+					end += 2
+					continue
+				}
+				if sql[end] == c {
+					// A doubled quote is an escaped quote.
+					if end+1 < len(sql) && sql[end+1] == c {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+			end = min(end, len(sql)-1)
+			current.WriteString(sql[i : end+1])
+			i = end
+		case c == '#' || (c == '-' && strings.HasPrefix(sql[i:], "-- ")) || strings.HasPrefix(sql[i:], "--\n"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end
+				current.WriteByte('\n')
+			}
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end + 3
+				current.WriteByte(' ')
+			}
+		case c == ';':
+			flush()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+
+	return statements
+}
@@ -0,0 +1,191 @@
+package dbmigrate
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{
+			name: "empty",
+			sql:  "",
+			want: []string{},
+		},
+		{
+			name: "single statement without a semicolon",
+			sql:  "CREATE TABLE a (id int)",
+			want: []string{"CREATE TABLE a (id int)"},
+		},
+		{
+			name: "many statements",
+			sql:  "CREATE TABLE a (id int);\nCREATE TABLE b (id int);\n\n;",
+			want: []string{"CREATE TABLE a (id int)", "CREATE TABLE b (id int)"},
+		},
+		{
+			name: "semicolon in a single quoted string",
+			sql:  "INSERT INTO a (name) VALUES ('a;b'); SELECT 1;",
+			want: []string{"INSERT INTO a (name) VALUES ('a;b')", "SELECT 1"},
+		},
+		{
+			name: "doubled quote in a string",
+			sql:  "INSERT INTO a (name) VALUES ('it''s; fine'); SELECT 1",
+			want: []string{"INSERT INTO a (name) VALUES ('it''s; fine')", "SELECT 1"},
+		},
+		{
+			name: "backslash escaped quote in a string",
+			sql:  `INSERT INTO a (name) VALUES ('it\'s; fine'); SELECT 1`,
+			want: []string{`INSERT INTO a (name) VALUES ('it\'s; fine')`, "SELECT 1"},
+		},
+		{
+			name: "semicolon in a double quoted identifier",
+			sql:  `CREATE TABLE "a;b" (id int); SELECT 1`,
+			want: []string{`CREATE TABLE "a;b" (id int)`, "SELECT 1"},
+		},
+		{
+			name: "semicolon in a backquoted identifier",
+			sql:  "CREATE TABLE `a;b` (id int); SELECT 1",
+			want: []string{"CREATE TABLE `a;b` (id int)", "SELECT 1"},
+		},
+		{
+			name: "backslash in a backquoted identifier",
+			sql:  "CREATE TABLE `a\\` (id int); SELECT 1",
+			want: []string{"CREATE TABLE `a\\` (id int)", "SELECT 1"},
+		},
+		{
+			name: "dash dash comment",
+			sql:  "-- drop the table; or not\nDROP TABLE a; -- done;\nSELECT 1",
+			want: []string{"DROP TABLE a", "SELECT 1"},
+		},
+		{
+			name: "empty dash dash comment",
+			sql:  "--\nSELECT 1;",
+			want: []string{"SELECT 1"},
+		},
+		{
+			name: "dash dash without a space isn't a comment",
+			sql:  "SELECT 1--1; SELECT 2",
+			want: []string{"SELECT 1--1", "SELECT 2"},
+		},
+		{
+			name: "hash comment",
+			sql:  "# a; b\nSELECT 1;",
+			want: []string{"SELECT 1"},
+		},
+		{
+			name: "block comment",
+			sql:  "SELECT /* a; b */ 1; /* trailing; */",
+			want: []string{"SELECT   1"},
+		},
+		{
+			name: "multi line block comment",
+			sql:  "/*\n a;\n b;\n*/\nSELECT 1",
+			want: []string{"SELECT 1"},
+		},
+		{
+			name: "comment only",
+			sql:  "-- the baseline can't be rolled back;\n",
+			want: []string{},
+		},
+		{
+			name: "quote in a comment",
+			sql:  "-- it's a comment\nSELECT 1; SELECT 2",
+			want: []string{"SELECT 1", "SELECT 2"},
+		},
+		{
+			name: "comment marker in a string",
+			sql:  "SELECT '-- a; b', '/* c; */'; SELECT 2",
+			want: []string{"SELECT '-- a; b', '/* c; */'", "SELECT 2"},
+		},
+		{
+			name: "unterminated string",
+			sql:  "SELECT 'a; b",
+			want: []string{"SELECT 'a; b"},
+		},
+		{
+			name: "unterminated block comment",
+			sql:  "SELECT 1; /* a; b",
+			want: []string{"SELECT 1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitStatements(tt.sql)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("splitStatements(%q) = %q, want %q", tt.sql, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name         string
+		files        fstest.MapFS
+		wantVersions []uint
+		wantErr      bool
+	}{
+		{
+			name: "sorted by version",
+			files: fstest.MapFS{
+				"migrations/0010_b.up.sql":   {Data: []byte("SELECT 10")},
+				"migrations/0010_b.down.sql": {Data: []byte("SELECT -10")},
+				"migrations/0002_a.up.sql":   {Data: []byte("SELECT 2")},
+				"migrations/0001_c.up.sql":   {Data: []byte("SELECT 1")},
+			},
+			wantVersions: []uint{1, 2, 10},
+		},
+		{
+			name: "invalid file name",
+			files: fstest.MapFS{
+				"migrations/0001_a.sql": {Data: []byte("SELECT 1")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero version",
+			files: fstest.MapFS{
+				"migrations/0000_a.up.sql": {Data: []byte("SELECT 1")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "two names of a version",
+			files: fstest.MapFS{
+				"migrations/0001_a.up.sql":   {Data: []byte("SELECT 1")},
+				"migrations/0001_b.down.sql": {Data: []byte("SELECT -1")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "down without up",
+			files: fstest.MapFS{
+				"migrations/0001_a.down.sql": {Data: []byte("SELECT -1")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := Load(tt.files, "migrations")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			versions := make([]uint, 0, len(migrations))
+			for _, m := range migrations {
+				versions = append(versions, m.Version)
+			}
+			if !tt.wantErr && !slices.Equal(versions, tt.wantVersions) {
+				t.Errorf("Load() versions = %v, want %v", versions, tt.wantVersions)
+			}
+		})
+	}
+}
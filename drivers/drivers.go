@@ -0,0 +1,50 @@
+// Package drivers opens the configured database and cache, so that the
+// binaries use the same ones for the same configuration.
+package drivers
+
+import (
+	"shs/actions"
+	"shs/app"
+	"shs/config"
+	"shs/mariadb"
+	"shs/memory"
+	"shs/redis"
+	"shs/sqlite"
+)
+
+type Repository interface {
+	app.Repository
+	DeleteAll() error
+	CreateSuperAdmin() error
+}
+
+// NewRepository opens the configured database, where SQLite's database is
+// migrated when it's opened, since it's the server's own file and there's no
+// migrator run before it.
+func NewRepository() (Repository, error) {
+	switch config.Env().DB.Driver {
+	case config.DBDriverSqlite:
+		err := sqlite.Migrate()
+		if err != nil {
+			return nil, err
+		}
+		return sqlite.New()
+	default:
+		return mariadb.New()
+	}
+}
+
+type Cache interface {
+	actions.Cache
+	FlushAll() error
+	Close() error
+}
+
+func NewCache() Cache {
+	switch config.Env().Cache.Driver {
+	case config.CacheDriverMemory:
+		return memory.New()
+	default:
+		return redis.New()
+	}
+}
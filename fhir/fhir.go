@@ -0,0 +1,188 @@
+// Package fhir has the subset of the FHIR R4 resources that a patient's record
+// is exported as, see https://hl7.org/fhir/R4/
+package fhir
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Code systems that are used in the exported resources.
+const (
+	SystemLoinc                     = "http://loinc.org"
+	SystemUcum                      = "http://unitsofmeasure.org"
+	SystemIcd11Mms                  = "http://id.who.int/icd/release/11/mms"
+	SystemObservationCategory       = "http://terminology.hl7.org/CodeSystem/observation-category"
+	SystemObservationInterpretation = "http://terminology.hl7.org/CodeSystem/v3-ObservationInterpretation"
+	SystemConditionClinical         = "http://terminology.hl7.org/CodeSystem/condition-clinical"
+	SystemConditionCategory         = "http://terminology.hl7.org/CodeSystem/condition-category"
+	SystemActCode                   = "http://terminology.hl7.org/CodeSystem/v3-ActCode"
+)
+
+const (
+	dateFormat     = "2006-01-02"
+	dateTimeFormat = time.RFC3339
+)
+
+// Date formats a FHIR date, where a zero time is an empty date, which is
+// omitted.
+func Date(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(dateFormat)
+}
+
+// DateTime formats a FHIR dateTime or instant, where a zero time is an empty
+// one, which is omitted.
+func DateTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(dateTimeFormat)
+}
+
+type Coding struct {
+	System  string `json:"system,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Display string `json:"display,omitempty"`
+}
+
+type CodeableConcept struct {
+	Coding []Coding `json:"coding,omitempty"`
+	Text   string   `json:"text,omitempty"`
+}
+
+type Reference struct {
+	Reference string `json:"reference,omitempty"`
+	Display   string `json:"display,omitempty"`
+}
+
+type Identifier struct {
+	System string `json:"system,omitempty"`
+	Value  string `json:"value"`
+}
+
+type HumanName struct {
+	Use    string   `json:"use,omitempty"`
+	Text   string   `json:"text,omitempty"`
+	Family string   `json:"family,omitempty"`
+	Given  []string `json:"given,omitempty"`
+}
+
+type ContactPoint struct {
+	System string `json:"system,omitempty"`
+	Value  string `json:"value,omitempty"`
+	Use    string `json:"use,omitempty"`
+}
+
+type Address struct {
+	Use      string   `json:"use,omitempty"`
+	Text     string   `json:"text,omitempty"`
+	Line     []string `json:"line,omitempty"`
+	District string   `json:"district,omitempty"`
+	State    string   `json:"state,omitempty"`
+}
+
+type Quantity struct {
+	Value  float64 `json:"value"`
+	Unit   string  `json:"unit,omitempty"`
+	System string  `json:"system,omitempty"`
+	Code   string  `json:"code,omitempty"`
+}
+
+type Period struct {
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+type Annotation struct {
+	Text string `json:"text"`
+}
+
+// Resource is one of the resources that a bundle can have.
+type Resource interface {
+	resourceType() string
+	resourceId() string
+}
+
+// marshalResource marshals the resource's fields, where v is the resource's
+// type without its MarshalJSON, and puts its resourceType first.
+func marshalResource(resourceType string, v any) ([]byte, error) {
+	fields, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBufferString(`{"resourceType":`)
+	typeName, _ := json.Marshal(resourceType)
+	buf.Write(typeName)
+	if !bytes.Equal(fields, []byte("{}")) {
+		buf.WriteByte(',')
+	}
+	buf.Write(fields[1:])
+
+	return buf.Bytes(), nil
+}
+
+///
+
+const BundleTypeCollection = "collection"
+
+type BundleEntry struct {
+	FullUrl  string   `json:"fullUrl"`
+	Resource Resource `json:"resource"`
+}
+
+// Bundle is a collection of resources, where the entries reference each other
+// by their full urls.
+type Bundle struct {
+	Id        string        `json:"id,omitempty"`
+	Type      string        `json:"type"`
+	Timestamp string        `json:"timestamp,omitempty"`
+	Entry     []BundleEntry `json:"entry"`
+}
+
+func NewCollection(id string, timestamp time.Time) *Bundle {
+	return &Bundle{
+		Id:        id,
+		Type:      BundleTypeCollection,
+		Timestamp: DateTime(timestamp),
+		Entry:     make([]BundleEntry, 0),
+	}
+}
+
+// Add appends the resource to the bundle, and returns a reference to it that
+// the other entries can use.
+func (b *Bundle) Add(resource Resource) Reference {
+	fullUrl := FullUrl(resource)
+	b.Entry = append(b.Entry, BundleEntry{
+		FullUrl:  fullUrl,
+		Resource: resource,
+	})
+
+	return Reference{
+		Reference: fullUrl,
+	}
+}
+
+// FullUrl is the resource's urn:uuid, which is derived from its type and id, so
+// that exporting the same record again gives the same urls.
+func FullUrl(resource Resource) string {
+	sum := sha1.Sum([]byte(resource.resourceType() + "/" + resource.resourceId()))
+	// RFC 4122's name based version 5 uuid.
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+func (b Bundle) MarshalJSON() ([]byte, error) {
+	type bundle Bundle
+	return marshalResource("Bundle", bundle(b))
+}
@@ -0,0 +1,135 @@
+package fhir
+
+// Patient is https://hl7.org/fhir/R4/patient.html
+type Patient struct {
+	Id         string         `json:"id"`
+	Identifier []Identifier   `json:"identifier,omitempty"`
+	Name       []HumanName    `json:"name,omitempty"`
+	Telecom    []ContactPoint `json:"telecom,omitempty"`
+	// Gender is one of male, female, other or unknown.
+	Gender    string    `json:"gender,omitempty"`
+	BirthDate string    `json:"birthDate,omitempty"`
+	Address   []Address `json:"address,omitempty"`
+}
+
+func (Patient) resourceType() string {
+	return "Patient"
+}
+
+func (p Patient) resourceId() string {
+	return p.Id
+}
+
+func (p Patient) MarshalJSON() ([]byte, error) {
+	type patient Patient
+	return marshalResource(p.resourceType(), patient(p))
+}
+
+// Condition is https://hl7.org/fhir/R4/condition.html
+type Condition struct {
+	Id             string            `json:"id"`
+	ClinicalStatus *CodeableConcept  `json:"clinicalStatus,omitempty"`
+	Category       []CodeableConcept `json:"category,omitempty"`
+	Code           CodeableConcept   `json:"code"`
+	Subject        Reference         `json:"subject"`
+	OnsetDateTime  string            `json:"onsetDateTime,omitempty"`
+	RecordedDate   string            `json:"recordedDate,omitempty"`
+	Note           []Annotation      `json:"note,omitempty"`
+}
+
+func (Condition) resourceType() string {
+	return "Condition"
+}
+
+func (c Condition) resourceId() string {
+	return c.Id
+}
+
+func (c Condition) MarshalJSON() ([]byte, error) {
+	type condition Condition
+	return marshalResource(c.resourceType(), condition(c))
+}
+
+type ObservationReferenceRange struct {
+	Low  *Quantity `json:"low,omitempty"`
+	High *Quantity `json:"high,omitempty"`
+}
+
+// Observation is https://hl7.org/fhir/R4/observation.html, where the value is
+// either a quantity or a string.
+type Observation struct {
+	Id                string                      `json:"id"`
+	Status            string                      `json:"status"`
+	Category          []CodeableConcept           `json:"category,omitempty"`
+	Code              CodeableConcept             `json:"code"`
+	Subject           Reference                   `json:"subject"`
+	EffectiveDateTime string                      `json:"effectiveDateTime,omitempty"`
+	ValueQuantity     *Quantity                   `json:"valueQuantity,omitempty"`
+	ValueString       string                      `json:"valueString,omitempty"`
+	Interpretation    []CodeableConcept           `json:"interpretation,omitempty"`
+	Note              []Annotation                `json:"note,omitempty"`
+	ReferenceRange    []ObservationReferenceRange `json:"referenceRange,omitempty"`
+}
+
+func (Observation) resourceType() string {
+	return "Observation"
+}
+
+func (o Observation) resourceId() string {
+	return o.Id
+}
+
+func (o Observation) MarshalJSON() ([]byte, error) {
+	type observation Observation
+	return marshalResource(o.resourceType(), observation(o))
+}
+
+type Dosage struct {
+	Text string `json:"text,omitempty"`
+}
+
+// MedicationStatement is https://hl7.org/fhir/R4/medicationstatement.html
+type MedicationStatement struct {
+	Id                        string          `json:"id"`
+	Status                    string          `json:"status"`
+	MedicationCodeableConcept CodeableConcept `json:"medicationCodeableConcept"`
+	Subject                   Reference       `json:"subject"`
+	EffectivePeriod           *Period         `json:"effectivePeriod,omitempty"`
+	Dosage                    []Dosage        `json:"dosage,omitempty"`
+}
+
+func (MedicationStatement) resourceType() string {
+	return "MedicationStatement"
+}
+
+func (ms MedicationStatement) resourceId() string {
+	return ms.Id
+}
+
+func (ms MedicationStatement) MarshalJSON() ([]byte, error) {
+	type medicationStatement MedicationStatement
+	return marshalResource(ms.resourceType(), medicationStatement(ms))
+}
+
+// Encounter is https://hl7.org/fhir/R4/encounter.html
+type Encounter struct {
+	Id         string            `json:"id"`
+	Status     string            `json:"status"`
+	Class      Coding            `json:"class"`
+	Subject    Reference         `json:"subject"`
+	Period     *Period           `json:"period,omitempty"`
+	ReasonCode []CodeableConcept `json:"reasonCode,omitempty"`
+}
+
+func (Encounter) resourceType() string {
+	return "Encounter"
+}
+
+func (e Encounter) resourceId() string {
+	return e.Id
+}
+
+func (e Encounter) MarshalJSON() ([]byte, error) {
+	type encounter Encounter
+	return marshalResource(e.resourceType(), encounter(e))
+}
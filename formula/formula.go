@@ -0,0 +1,449 @@
+// Package formula parses and evaluates arithmetic expressions over named
+// values, used for the blood test fields that are computed from their sibling
+// fields, e.g. "{Hb} / {Hct} * 100".
+//
+// An expression has numbers, variables written in braces, the +, -, *, / and ^
+// operators, parentheses, and the functions abs, sqrt, ln, log10, exp, min and
+// max, and nothing else, so evaluating it can't do anything besides arithmetic.
+package formula
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrEmptyExpression = errors.New("formula: empty expression")
+	ErrDivisionByZero  = errors.New("formula: division by zero")
+	ErrNotANumber      = errors.New("formula: result is not a finite number")
+)
+
+// ErrSyntax is an expression that can't be parsed, where Position is the byte
+// offset of the offending token.
+type ErrSyntax struct {
+	Position int
+	Message  string
+}
+
+func (e ErrSyntax) Error() string {
+	return fmt.Sprintf("formula: %s at position %d", e.Message, e.Position)
+}
+
+// ErrMissingVariable is a variable that has no value when evaluating.
+type ErrMissingVariable struct {
+	Name string
+}
+
+func (e ErrMissingVariable) Error() string {
+	return fmt.Sprintf("formula: missing value for {%s}", e.Name)
+}
+
+type function struct {
+	arity int
+	call  func(args []float64) float64
+}
+
+var functions = map[string]function{
+	"abs":   {1, func(args []float64) float64 { return math.Abs(args[0]) }},
+	"sqrt":  {1, func(args []float64) float64 { return math.Sqrt(args[0]) }},
+	"ln":    {1, func(args []float64) float64 { return math.Log(args[0]) }},
+	"log10": {1, func(args []float64) float64 { return math.Log10(args[0]) }},
+	"exp":   {1, func(args []float64) float64 { return math.Exp(args[0]) }},
+	"min":   {2, func(args []float64) float64 { return math.Min(args[0], args[1]) }},
+	"max":   {2, func(args []float64) float64 { return math.Max(args[0], args[1]) }},
+}
+
+// Expression is a parsed formula, that's safe to evaluate many times.
+type Expression struct {
+	source    string
+	root      node
+	variables []string
+}
+
+// Parse parses the formula, and fails on any syntax error, or an unknown
+// function, so that a formula is checked once when it's defined.
+func Parse(source string) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return nil, ErrEmptyExpression
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEnd {
+		return nil, ErrSyntax{Position: p.peek().position, Message: fmt.Sprintf("unexpected %q", p.peek().text)}
+	}
+
+	return &Expression{
+		source:    source,
+		root:      root,
+		variables: p.variables,
+	}, nil
+}
+
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Variables returns the distinct variables that the expression references, in
+// the order of their first appearance.
+func (e *Expression) Variables() []string {
+	return slices.Clone(e.variables)
+}
+
+// Evaluate computes the expression using the given variables' values, and
+// fails when a variable is missing, or when the result isn't a finite number.
+func (e *Expression) Evaluate(values map[string]float64) (float64, error) {
+	result, err := e.root.evaluate(values)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return 0, ErrNotANumber
+	}
+
+	return result, nil
+}
+
+///
+
+type node interface {
+	evaluate(values map[string]float64) (float64, error)
+}
+
+type numberNode float64
+
+func (n numberNode) evaluate(map[string]float64) (float64, error) {
+	return float64(n), nil
+}
+
+type variableNode string
+
+func (n variableNode) evaluate(values map[string]float64) (float64, error) {
+	value, ok := values[string(n)]
+	if !ok {
+		return 0, ErrMissingVariable{Name: string(n)}
+	}
+
+	return value, nil
+}
+
+type negateNode struct {
+	operand node
+}
+
+func (n negateNode) evaluate(values map[string]float64) (float64, error) {
+	value, err := n.operand.evaluate(values)
+	if err != nil {
+		return 0, err
+	}
+
+	return -value, nil
+}
+
+type binaryNode struct {
+	operator    byte
+	left, right node
+}
+
+func (n binaryNode) evaluate(values map[string]float64) (float64, error) {
+	left, err := n.left.evaluate(values)
+	if err != nil {
+		return 0, err
+	}
+	right, err := n.right.evaluate(values)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n.operator {
+	case '+':
+		return left + right, nil
+	case '-':
+		return left - right, nil
+	case '*':
+		return left * right, nil
+	case '/':
+		if right == 0 {
+			return 0, ErrDivisionByZero
+		}
+		return left / right, nil
+	case '^':
+		return math.Pow(left, right), nil
+	default:
+		return 0, fmt.Errorf("formula: unknown operator %q", n.operator)
+	}
+}
+
+type callNode struct {
+	function function
+	args     []node
+}
+
+func (n callNode) evaluate(values map[string]float64) (float64, error) {
+	args := make([]float64, 0, len(n.args))
+	for _, arg := range n.args {
+		value, err := arg.evaluate(values)
+		if err != nil {
+			return 0, err
+		}
+		args = append(args, value)
+	}
+
+	return n.function.call(args), nil
+}
+
+///
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenNumber
+	tokenVariable
+	tokenIdentifier
+	tokenOperator
+	tokenOpenParen
+	tokenCloseParen
+	tokenComma
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	number   float64
+	position int
+}
+
+func tokenize(source string) ([]token, error) {
+	tokens := make([]token, 0)
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '{':
+			end := strings.IndexByte(source[i:], '}')
+			if end == -1 {
+				return nil, ErrSyntax{Position: i, Message: "unclosed variable"}
+			}
+			name := strings.TrimSpace(source[i+1 : i+end])
+			if name == "" {
+				return nil, ErrSyntax{Position: i, Message: "empty variable"}
+			}
+			tokens = append(tokens, token{kind: tokenVariable, text: name, position: i})
+			i += end + 1
+		case c >= '0' && c <= '9' || c == '.':
+			start := i
+			for i < len(source) && (source[i] >= '0' && source[i] <= '9' || source[i] == '.') {
+				i++
+			}
+			number, err := strconv.ParseFloat(source[start:i], 64)
+			if err != nil {
+				return nil, ErrSyntax{Position: start, Message: fmt.Sprintf("invalid number %q", source[start:i])}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: source[start:i], number: number, position: start})
+		case c < unicode.MaxASCII && unicode.IsLetter(rune(c)):
+			start := i
+			for i < len(source) && source[i] < unicode.MaxASCII && (unicode.IsLetter(rune(source[i])) || unicode.IsDigit(rune(source[i]))) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, text: source[start:i], position: start})
+		case strings.IndexByte("+-*/^", c) != -1:
+			tokens = append(tokens, token{kind: tokenOperator, text: string(c), position: i})
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenOpenParen, text: "(", position: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenCloseParen, text: ")", position: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", position: i})
+			i++
+		default:
+			return nil, ErrSyntax{Position: i, Message: fmt.Sprintf("unexpected character %q", c)}
+		}
+	}
+
+	return append(tokens, token{kind: tokenEnd, text: "end of formula", position: len(source)}), nil
+}
+
+///
+
+// parser is a recursive descent parser, where from the lowest precedence:
+//
+//	expression = term { ("+" | "-") term }
+//	term       = unary { ("*" | "/") unary }
+//	unary      = ("+" | "-") unary | power
+//	power      = primary [ "^" unary ]
+//	primary    = number | variable | identifier "(" expression { "," expression } ")" | "(" expression ")"
+type parser struct {
+	tokens    []token
+	current   int
+	variables []string
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.current]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.current]
+	if t.kind != tokenEnd {
+		p.current++
+	}
+	return t
+}
+
+func (p *parser) isOperator(operators string) bool {
+	t := p.peek()
+	return t.kind == tokenOperator && strings.Contains(operators, t.text)
+}
+
+func (p *parser) expect(kind tokenKind, what string) error {
+	t := p.next()
+	if t.kind != kind {
+		return ErrSyntax{Position: t.position, Message: fmt.Sprintf("expected %s but got %q", what, t.text)}
+	}
+	return nil
+}
+
+func (p *parser) parseExpression() (node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("+-") {
+		operator := p.next().text[0]
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{operator: operator, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseTerm() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("*/") {
+		operator := p.next().text[0]
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{operator: operator, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.isOperator("+-") {
+		operator := p.next().text
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if operator == "-" {
+			return negateNode{operand: operand}, nil
+		}
+		return operand, nil
+	}
+
+	return p.parsePower()
+}
+
+func (p *parser) parsePower() (node, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOperator("^") {
+		p.next()
+		// the exponent is parsed as a unary, so that ^ is right associative.
+		exponent, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{operator: '^', left: base, right: exponent}, nil
+	}
+
+	return base, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		return numberNode(t.number), nil
+	case tokenVariable:
+		if !slices.Contains(p.variables, t.text) {
+			p.variables = append(p.variables, t.text)
+		}
+		return variableNode(t.text), nil
+	case tokenOpenParen:
+		inner, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		err = p.expect(tokenCloseParen, "\")\"")
+		if err != nil {
+			return nil, err
+		}
+		return inner, nil
+	case tokenIdentifier:
+		return p.parseCall(t)
+	default:
+		return nil, ErrSyntax{Position: t.position, Message: fmt.Sprintf("unexpected %q", t.text)}
+	}
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	fn, ok := functions[strings.ToLower(name.text)]
+	if !ok {
+		return nil, ErrSyntax{Position: name.position, Message: fmt.Sprintf("unknown function %q", name.text)}
+	}
+	err := p.expect(tokenOpenParen, "\"(\"")
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]node, 0, fn.arity)
+	for {
+		arg, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if p.peek().kind != tokenComma {
+			break
+		}
+		p.next()
+	}
+	err = p.expect(tokenCloseParen, "\")\"")
+	if err != nil {
+		return nil, err
+	}
+	if len(args) != fn.arity {
+		return nil, ErrSyntax{Position: name.position, Message: fmt.Sprintf("%s takes %d arguments but got %d", name.text, fn.arity, len(args))}
+	}
+
+	return callNode{function: fn, args: args}, nil
+}
@@ -0,0 +1,162 @@
+package formula
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	values := map[string]float64{
+		"Hb":         15,
+		"Hct":        45,
+		"Factor VII": 80,
+		"zero":       0,
+	}
+
+	tests := []struct {
+		name   string
+		source string
+		want   float64
+	}{
+		{name: "number", source: "42", want: 42},
+		{name: "decimal", source: ".5", want: 0.5},
+		{name: "multiplication before addition", source: "2 + 3 * 4", want: 14},
+		{name: "division before subtraction", source: "10 - 6 / 2", want: 7},
+		{name: "left associative subtraction", source: "10 - 4 - 3", want: 3},
+		{name: "left associative division", source: "24 / 4 / 2", want: 3},
+		{name: "parentheses", source: "(2 + 3) * 4", want: 20},
+		{name: "nested parentheses", source: "((1 + 2) * (3 + 4))", want: 21},
+		{name: "power before multiplication", source: "2 * 3 ^ 2", want: 18},
+		{name: "right associative power", source: "2 ^ 3 ^ 2", want: 512},
+		{name: "power before negation", source: "-2 ^ 2", want: -4},
+		{name: "negative exponent", source: "2 ^ -1", want: 0.5},
+		{name: "double negation", source: "--3", want: 3},
+		{name: "unary plus", source: "+3 - -2", want: 5},
+		{name: "variables", source: "{Hb} / {Hct} * 100", want: 100.0 / 3},
+		{name: "variable with spaces", source: "{ Factor VII } / 2", want: 40},
+		{name: "functions", source: "sqrt(16) + abs(-2) + max(1, 3) - min(4, 5)", want: 5},
+		{name: "function case", source: "LOG10(1000)", want: 3},
+		{name: "nested functions", source: "ln(exp(2))", want: 2},
+		{name: "whitespace", source: " \t1\n+\r2 ", want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expression, err := Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.source, err)
+			}
+			got, err := expression.Evaluate(values)
+			if err != nil {
+				t.Fatalf("Evaluate(%q) error = %v", tt.source, err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Evaluate(%q) = %v, want %v", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	values := map[string]float64{
+		"a":    1,
+		"zero": 0,
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		wantErr error
+	}{
+		{name: "division by zero", source: "1 / 0", wantErr: ErrDivisionByZero},
+		{name: "division by a zero variable", source: "{a} / {zero}", wantErr: ErrDivisionByZero},
+		{name: "division by a zero expression", source: "{a} / ({a} - 1)", wantErr: ErrDivisionByZero},
+		{name: "not a number", source: "sqrt(-1)", wantErr: ErrNotANumber},
+		{name: "infinite", source: "ln({zero})", wantErr: ErrNotANumber},
+		{name: "overflow", source: "10 ^ 400", wantErr: ErrNotANumber},
+		{name: "missing variable", source: "{a} + {b}", wantErr: ErrMissingVariable{Name: "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expression, err := Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.source, err)
+			}
+			_, err = expression.Evaluate(values)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Evaluate(%q) error = %v, want %v", tt.source, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		source       string
+		wantErr      error
+		wantPosition int
+	}{
+		{name: "empty", source: "", wantErr: ErrEmptyExpression},
+		{name: "blank", source: "   ", wantErr: ErrEmptyExpression},
+		{name: "unclosed variable", source: "1 + {Hb", wantPosition: 4},
+		{name: "empty variable", source: "{ } + 1", wantPosition: 0},
+		{name: "invalid number", source: "1.2.3", wantPosition: 0},
+		{name: "unexpected character", source: "1 % 2", wantPosition: 2},
+		{name: "dangling operator", source: "1 +", wantPosition: 3},
+		{name: "unclosed parenthesis", source: "(1 + 2", wantPosition: 6},
+		{name: "extra parenthesis", source: "1 + 2)", wantPosition: 5},
+		{name: "unknown function", source: "eval(1)", wantPosition: 0},
+		{name: "wrong arity", source: "min(1)", wantPosition: 0},
+		{name: "function without parentheses", source: "sqrt 4", wantPosition: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.source)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Parse(%q) error = %v, want %v", tt.source, err, tt.wantErr)
+				}
+				return
+			}
+
+			var syntaxErr ErrSyntax
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%q) error = %v, want ErrSyntax", tt.source, err)
+			}
+			if syntaxErr.Position != tt.wantPosition {
+				t.Errorf("Parse(%q) error position = %d, want %d", tt.source, syntaxErr.Position, tt.wantPosition)
+			}
+		})
+	}
+}
+
+func TestVariables(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{name: "none", source: "1 + 2", want: []string{}},
+		{name: "in order of appearance", source: "{Hct} / {Hb}", want: []string{"Hct", "Hb"}},
+		{name: "without duplicates", source: "{a} * {b} + {a}", want: []string{"a", "b"}},
+		{name: "trimmed", source: "{ Factor VIII }", want: []string{"Factor VIII"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expression, err := Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.source, err)
+			}
+			got := expression.Variables()
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Variables() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
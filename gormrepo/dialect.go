@@ -0,0 +1,23 @@
+package gormrepo
+
+import (
+	"shs/app/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Dialect has the queries whose SQL differs between the databases that the
+// repository is used with.
+type Dialect interface {
+	// AgeInYears returns the SQL expression of the age in full years of the
+	// date column at the given time, and the expression's args.
+	AgeInYears(column string, at time.Time) (string, []any)
+	// FindOrCreateLastPatientId returns the last patient id, and creates the
+	// next one, so that the patients that are created at the same time don't
+	// get the same id, where the first id is created when there are none.
+	FindOrCreateLastPatientId(db *gorm.DB) (models.PatientId, error)
+	// SetForeignKeyChecks returns the statement that enables or disables the
+	// connection's foreign key checks.
+	SetForeignKeyChecks(enabled bool) string
+}
@@ -1,9 +1,8 @@
-package mariadb
+package gormrepo
 
 import (
 	"errors"
 
-	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
 )
 
@@ -61,6 +60,8 @@ func (e ErrRecordExists) ExposeToClients() bool {
 	return false
 }
 
+// tryWrapDbError wraps gorm's errors, where the connections translate their
+// databases' errors to gorm's, e.g. a duplicate key to gorm.ErrDuplicatedKey.
 func tryWrapDbError(err error) error {
 	switch {
 	case errors.Is(err, gorm.ErrRecordNotFound):
@@ -69,16 +70,5 @@ func tryWrapDbError(err error) error {
 		return &ErrRecordExists{}
 	}
 
-	if mysqlErr, ok := err.(*mysql.MySQLError); ok {
-		switch mysqlErr.Number {
-		case 1146:
-			return &ErrRecordNotFound{}
-		case 1062:
-			return &ErrRecordExists{}
-		default:
-			return err
-		}
-	}
-
 	return err
 }
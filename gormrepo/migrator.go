@@ -0,0 +1,106 @@
+package gormrepo
+
+import (
+	"shs/app/models"
+	"shs/config"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// migratableModels are the models' tables, whose schema is created by the
+// databases' numbered migrations.
+var migratableModels = []schema.Tabler{
+	new(models.Account),
+	new(models.Virus),
+	new(models.Medicine),
+	new(models.Visit),
+	new(models.BloodTest),
+	new(models.BloodTestResult),
+	new(models.BloodTestField),
+	new(models.BloodTestReferenceRange),
+	new(models.BloodTestFilledField),
+	new(models.Address),
+	new(models.Patient),
+	new(models.PatientId),
+	new(models.HasVirus),
+	new(models.TreatmentDetails),
+	new(models.PrescribedMedicine),
+	new(models.JointsEvaluation),
+	new(models.HjhsJointScore),
+	new(models.Prophylaxis),
+	new(models.Diagnosis),
+	new(models.DiagnosisResult),
+	new(models.BleedingEpisode),
+	new(models.StockMovement),
+	new(models.MedicineAlert),
+	new(models.Appointment),
+	new(models.InhibitorSurveillance),
+	new(models.LabCodeMapping),
+	new(models.AuditEvent),
+	new(models.PatientRevision),
+}
+
+func (r *Repository) CreateSuperAdmin() error {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(config.Env().SuperAdmin.Password), bcrypt.DefaultCost)
+	superMechman := models.Account{
+		DisplayName: "Super Admin!",
+		Username:    config.Env().SuperAdmin.Username,
+		Password:    string(hashedPassword),
+		Type:        models.AccountTypeSuperAdmin,
+		Permissions: models.AccountPermissionReadAccounts |
+			models.AccountPermissionWriteAccounts |
+			models.AccountPermissionReadPatient |
+			models.AccountPermissionWritePatient |
+			models.AccountPermissionReadMedicine |
+			models.AccountPermissionWriteMedicine |
+			models.AccountPermissionReadVirus |
+			models.AccountPermissionWriteVirus |
+			models.AccountPermissionReadBloodTest |
+			models.AccountPermissionWriteBloodTest |
+			models.AccountPermissionReadOwnVisit |
+			models.AccountPermissionWriteOwnVisit |
+			models.AccountPermissionReadOtherVisits |
+			models.AccountPermissionWriteOtherVisits |
+			models.AccountPermissionReadDiagnoses |
+			models.AccountPermissionWriteDiagnoses |
+			models.AccountPermissionReadJoints |
+			models.AccountPermissionWriteJoints |
+			models.AccountPermissionReadProphylaxes |
+			models.AccountPermissionWriteProphylaxes |
+			models.AccountPermissionReadAuditEvents,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	return r.client.Create(&superMechman).Error
+}
+
+// DeleteAll clears the tables for the e2e tests, where the foreign key checks
+// are the connection's, so the tables are cleared on a single connection.
+func (r *Repository) DeleteAll() error {
+	return r.client.Connection(func(conn *gorm.DB) error {
+		err := conn.Exec(r.dialect.SetForeignKeyChecks(false)).Error
+		if err != nil {
+			return err
+		}
+		defer conn.Exec(r.dialect.SetForeignKeyChecks(true))
+
+		for _, table := range migratableModels {
+			var err error
+			_, ok := table.(models.Account)
+			if ok {
+				err = conn.Model(table).Where("username != ?", "b").Delete(nil).Error
+			} else {
+				err = conn.Model(table).Where("true").Delete(nil).Error
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,2484 @@
+package gormrepo
+
+import (
+	"errors"
+	"fmt"
+	"shs/app"
+	"shs/app/models"
+	"shs/log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Repository is the app's repository over a gorm connection, which is shared
+// by the databases, where the queries whose SQL differs between them are the
+// dialect's.
+type Repository struct {
+	client  *gorm.DB
+	dialect Dialect
+}
+
+func New(client *gorm.DB, dialect Dialect) *Repository {
+	return &Repository{
+		client:  client,
+		dialect: dialect,
+	}
+}
+
+func (r *Repository) WithTransaction(fn func(tx app.Repository) error) error {
+	return tryWrapDbError(r.client.Transaction(func(tx *gorm.DB) error {
+		return fn(&Repository{
+			client:  tx,
+			dialect: r.dialect,
+		})
+	}))
+}
+
+// --------------------------------
+// App Repository
+// --------------------------------
+
+func (r *Repository) GetAccount(id uint) (models.Account, error) {
+	var account models.Account
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Account)).
+			First(&account, "id = ?", id).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return models.Account{}, &app.ErrNotFound{
+			ResourceName: "account",
+		}
+	}
+	if err != nil {
+		return models.Account{}, err
+	}
+
+	return account, nil
+}
+
+func (r *Repository) GetAccountByUsername(username string) (models.Account, error) {
+	var account models.Account
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Account)).
+			First(&account, "username = ?", username).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return models.Account{}, &app.ErrNotFound{
+			ResourceName: "account",
+		}
+	}
+	if err != nil {
+		return models.Account{}, err
+	}
+
+	return account, nil
+}
+
+func (r *Repository) CreateAccount(account models.Account) (models.Account, error) {
+	account.CreatedAt = time.Now().UTC()
+	account.UpdatedAt = time.Now().UTC()
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Account)).
+			Create(&account).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.Account{}, &app.ErrExists{
+			ResourceName: "account",
+		}
+	}
+	if err != nil {
+		return models.Account{}, err
+	}
+
+	return account, nil
+}
+
+func (r *Repository) ListAllAccounts() ([]models.Account, error) {
+	var accounts []models.Account
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Account)).
+			Where("type NOT IN ?", []models.AccountType{models.AccountTypeSuperAdmin, models.AccountTypePatient}).
+			Find(&accounts).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
+func (r *Repository) DeleteAccount(id uint) error {
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Account)).
+			Delete(&models.Account{Id: id}, "id = ?", id).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "account",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) UpdateAccountPermissions(id uint, permissions models.AccountPermissions) error {
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Account)).
+			Where("id = ?", id).
+			Update("permissions", permissions).
+			Update("updated_at", time.Now().UTC()).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "account",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) UpdateAccountDisplayName(id uint, name string) error {
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Account)).
+			Where("id = ?", id).
+			Update("display_name", name).
+			Update("updated_at", time.Now().UTC()).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "account",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) UpdateAccountUsername(id uint, username string) error {
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Account)).
+			Where("id = ?", id).
+			Update("username", username).
+			Update("updated_at", time.Now().UTC()).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "account",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) UpdateAccountPassword(id uint, password string) error {
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Account)).
+			Where("id = ?", id).
+			Update("password", password).
+			Update("updated_at", time.Now().UTC()).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "account",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) UpdateAccountCalendarFeedVersion(id uint, version uint) error {
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Account)).
+			Where("id = ?", id).
+			Update("calendar_feed_version", version).
+			Update("updated_at", time.Now().UTC()).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "account",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) CreateBloodTest(bt models.BloodTest) (models.BloodTest, error) {
+	bt.CreatedAt = time.Now().UTC()
+	bt.UpdatedAt = time.Now().UTC()
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.BloodTest)).
+			Create(&bt).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.BloodTest{}, &app.ErrExists{
+			ResourceName: "blood_test",
+		}
+	}
+	if err != nil {
+		return models.BloodTest{}, err
+	}
+
+	return bt, nil
+}
+
+func (r *Repository) DeleteBloodTest(id uint) error {
+	err := tryWrapDbError(
+		r.client.
+			Exec("DELETE FROM blood_test_reference_ranges WHERE blood_test_field_id IN (SELECT id FROM blood_test_fields WHERE blood_test_id = ?)", id).
+			Error,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = tryWrapDbError(
+		r.client.
+			Model(new(models.BloodTestField)).
+			Delete(&models.BloodTestField{BloodTestId: id}, "blood_test_id = ?", id).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "blood_test",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	err = tryWrapDbError(
+		r.client.
+			Model(new(models.BloodTest)).
+			Delete(&models.BloodTest{Id: id}, "id = ?", id).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "blood_test",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) GetBloodTest(id uint) (models.BloodTest, error) {
+	var bt models.BloodTest
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.BloodTest)).
+			Preload("Fields").
+			Preload("Fields.ReferenceRanges").
+			First(&bt, "id = ?", id).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return models.BloodTest{}, &app.ErrNotFound{
+			ResourceName: "blood_test",
+		}
+	}
+	if err != nil {
+		return models.BloodTest{}, err
+	}
+
+	return bt, nil
+}
+
+func (r *Repository) UpdateBloodTest(id uint, bt models.BloodTest) (models.BloodTest, error) {
+	return models.BloodTest{}, errors.New("not implemented")
+}
+
+func (r *Repository) ListAllBloodTests() ([]models.BloodTest, error) {
+	var bloodTests []models.BloodTest
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.BloodTest)).
+			Preload("Fields").
+			Preload("Fields.ReferenceRanges").
+			Find(&bloodTests).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return bloodTests, nil
+}
+
+func (r *Repository) UpdateBloodTestFieldLoincCode(id uint, loincCode string) error {
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.BloodTestField)).
+			Where("id = ?", id).
+			Update("loinc_code", loincCode).
+			Update("updated_at", time.Now().UTC()).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "blood_test_field",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) CreateBloodTestReferenceRange(rr models.BloodTestReferenceRange) (models.BloodTestReferenceRange, error) {
+	rr.CreatedAt = time.Now().UTC()
+	rr.UpdatedAt = time.Now().UTC()
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.BloodTestReferenceRange)).
+			Create(&rr).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.BloodTestReferenceRange{}, &app.ErrExists{
+			ResourceName: "blood_test_reference_range",
+		}
+	}
+	if err != nil {
+		return models.BloodTestReferenceRange{}, err
+	}
+
+	return rr, nil
+}
+
+func (r *Repository) DeleteBloodTestReferenceRange(id uint) error {
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.BloodTestReferenceRange)).
+			Delete(&models.BloodTestReferenceRange{Id: id}, "id = ?", id).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "blood_test_reference_range",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) ToggleBloodTestDisplay(id uint) error {
+	btBlyat, err := r.GetBloodTest(id)
+	if err != nil {
+		return tryWrapDbError(err)
+	}
+
+	err = tryWrapDbError(
+		r.client.
+			Model(new(models.BloodTest)).
+			Where("id = ?", id).
+			Update("display_in_brief", !btBlyat.DisplayInBrief).
+			Update("created_at", time.Now().UTC()).
+			Error,
+	)
+
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "blood_test",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (r *Repository) CreateBloodTestResult(btResult models.BloodTestResult) (models.BloodTestResult, error) {
+	btResult.CreatedAt = time.Now().UTC()
+	btResult.UpdatedAt = time.Now().UTC()
+	if btResult.TestedAt.IsZero() {
+		btResult.TestedAt = btResult.CreatedAt
+	}
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.BloodTestResult)).
+			Create(&btResult).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.BloodTestResult{}, &app.ErrExists{
+			ResourceName: "blood_test_result",
+		}
+	}
+	if err != nil {
+		return models.BloodTestResult{}, err
+	}
+
+	return btResult, nil
+}
+
+func (r *Repository) ListPatientBloodTestResults(patientId uint) ([]models.BloodTestResult, error) {
+	var bloodTestResults []models.BloodTestResult
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.BloodTestResult)).
+			Preload("FilledFields").
+			Where("patient_id = ?", patientId).
+			Find(&bloodTestResults).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return bloodTestResults, nil
+}
+
+// ListBloodTestResultsOnTimeRange lists all patients' blood test results that
+// were tested in [from, to), ordered by their test time.
+func (r *Repository) ListBloodTestResultsOnTimeRange(from, to time.Time) ([]models.BloodTestResult, error) {
+	var bloodTestResults []models.BloodTestResult
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.BloodTestResult)).
+			Preload("FilledFields").
+			Where("tested_at >= ? AND tested_at < ?", from, to).
+			Order("tested_at ASC").
+			Find(&bloodTestResults).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return bloodTestResults, nil
+}
+
+func (r *Repository) ListBloodTestResultsForLabMessage(sendingFacility, controlId string) ([]models.BloodTestResult, error) {
+	var bloodTestResults []models.BloodTestResult
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.BloodTestResult)).
+			Where("lab_sending_facility = ? AND lab_message_control_id = ?", sendingFacility, controlId).
+			Find(&bloodTestResults).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return bloodTestResults, nil
+}
+
+func (r *Repository) SetBloodTestResultPending(id uint, pending bool) error {
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.BloodTestResult)).
+			Where("id = ?", id).
+			Update("pending", pending).
+			Error,
+	)
+
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "blood_test_result",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) CreateBloodTestResultFilledFields(filledFields []models.BloodTestFilledField) error {
+	for i := range filledFields {
+		filledFields[i].CreatedAt = time.Now().UTC()
+		filledFields[i].UpdatedAt = time.Now().UTC()
+	}
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.BloodTestFilledField)).
+			Create(filledFields).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return &app.ErrExists{
+			ResourceName: "blood_test_result_field",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteBloodTestResultFilledFields(btrId uint) error {
+	return tryWrapDbError(
+		r.client.
+			Model(new(models.BloodTestFilledField)).
+			Delete(new(models.BloodTestFilledField), "blood_test_result_id = ?", btrId).
+			Error,
+	)
+}
+
+func (r *Repository) UpdateBloodTestResultCreatedAt(id uint, ts time.Time) error {
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.BloodTestResult)).
+			Where("id = ?", id).
+			Update("created_at", ts).
+			Error,
+	)
+
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "blood_test_result",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (r *Repository) CreateVirus(virus models.Virus) (models.Virus, error) {
+	virus.CreatedAt = time.Now().UTC()
+	virus.UpdatedAt = time.Now().UTC()
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Virus)).
+			Create(&virus).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.Virus{}, &app.ErrExists{
+			ResourceName: "virus",
+		}
+	}
+	if err != nil {
+		return models.Virus{}, err
+	}
+
+	return virus, nil
+}
+
+func (r *Repository) DeleteVirus(id uint) error {
+	err := tryWrapDbError(
+		r.client.
+			Exec("DELETE FROM identifying_blood_tests WHERE virus_id = ?", id).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "virus",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	err = tryWrapDbError(
+		r.client.
+			Model(new(models.Virus)).
+			Delete(&models.Virus{Id: id}, "id = ?", id).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "virus",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) ListAllViruses() ([]models.Virus, error) {
+	var viruses []models.Virus
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Virus)).
+			Preload("IdentifyingBloodTests").
+			Find(&viruses).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return viruses, nil
+}
+
+func (r *Repository) ListVirusesForPatient(patientId uint) ([]models.Virus, error) {
+	viruses := make([]models.Virus, 0)
+
+	query := fmt.Sprintf(`SELECT %s.id, viruses.name
+	FROM viruses
+		JOIN has_viruses ON %s.id = has_viruses.virus_id
+	WHERE has_viruses.patient_id = ?`, models.Virus{}.TableName(), models.Virus{}.TableName())
+
+	err := tryWrapDbError(
+		r.client.
+			Raw(query, patientId).
+			Find(&viruses).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return viruses, nil
+}
+
+func (r *Repository) CreateMedicine(medicine models.Medicine) (models.Medicine, error) {
+	medicine.CreatedAt = time.Now().UTC()
+	medicine.UpdatedAt = time.Now().UTC()
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Medicine)).
+			Create(&medicine).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.Medicine{}, &app.ErrExists{
+			ResourceName: "medicine",
+		}
+	}
+	if err != nil {
+		return models.Medicine{}, err
+	}
+
+	return medicine, nil
+
+}
+
+func (r *Repository) DeleteMedicine(id uint) error {
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Medicine)).
+			Delete(&models.Medicine{Id: id}, "id = ?", id).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "medicine",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) ListAllMedicines() ([]models.Medicine, error) {
+	var medicines []models.Medicine
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Medicine)).
+			Find(&medicines).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return medicines, nil
+}
+
+func (r *Repository) ListMedicinesByIds(ids []uint) ([]models.Medicine, error) {
+	var medicines []models.Medicine
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Medicine)).
+			Where("id IN ?", ids).
+			Find(&medicines).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return medicines, nil
+}
+
+func (r *Repository) LockMedicinesByIds(ids []uint) ([]models.Medicine, error) {
+	var medicines []models.Medicine
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Medicine)).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", ids).
+			Order("id ASC").
+			Find(&medicines).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return medicines, nil
+}
+
+func (r *Repository) UpdateMedicineAmount(id uint, newAmount int) error {
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Medicine)).
+			Where("id = ?", id).
+			Update("amount", newAmount).
+			Error,
+	)
+
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "medicine",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) GetMedicine(id uint) (models.Medicine, error) {
+	var medicine models.Medicine
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Medicine)).
+			First(&medicine, "id = ?", id).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return models.Medicine{}, &app.ErrNotFound{
+			ResourceName: "medicine",
+		}
+	}
+	if err != nil {
+		return models.Medicine{}, err
+	}
+
+	return medicine, nil
+}
+
+func (r *Repository) findOrCreateLastPatientId() (models.PatientId, error) {
+	lastPatientId, err := r.dialect.FindOrCreateLastPatientId(r.client)
+	if err != nil {
+		return models.PatientId{}, tryWrapDbError(err)
+	}
+
+	return lastPatientId, nil
+}
+
+func (r *Repository) CreatePatient(patient models.Patient) (models.Patient, error) {
+	lastPatientId, err := r.findOrCreateLastPatientId()
+	if err != nil {
+		return models.Patient{}, err
+	}
+
+	patient.PublicId = fmt.Sprintf("%06d", lastPatientId.PublicId)
+	patient.CreatedAt = time.Now().UTC()
+	patient.UpdatedAt = time.Now().UTC()
+
+	if patient.NationalId == "" {
+		patient.NationalId = "please_change_" + patient.PublicId
+	}
+	patient.FillEmptyFieldsUsingPublicId()
+
+	err = tryWrapDbError(
+		r.client.
+			Model(new(models.Patient)).
+			Create(&patient).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.Patient{}, &app.ErrExists{
+			ResourceName: "patient",
+		}
+	}
+	if err != nil {
+		return models.Patient{}, err
+	}
+
+	return patient, nil
+}
+
+func (r *Repository) UpdatePatient(id uint, patient models.Patient) (models.Patient, error) {
+	patient.UpdatedAt = time.Now().UTC()
+
+	if patient.NationalId == "" {
+		patient.NationalId = "please_change_" + patient.PublicId
+	}
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Patient)).
+			Where("id = ?", id).
+			Updates(map[string]any{
+				"national_id":               patient.NationalId,
+				"nationality":               patient.Nationality,
+				"first_name":                patient.FirstName,
+				"last_name":                 patient.LastName,
+				"father_name":               patient.FatherName,
+				"mother_name":               patient.MotherName,
+				"place_of_birth_id":         patient.PlaceOfBirthId,
+				"date_of_birth":             patient.DateOfBirth,
+				"residency_id":              patient.ResidencyId,
+				"gender":                    patient.Gender,
+				"phone_number":              patient.PhoneNumber,
+				"phone_number_country_code": patient.PhoneNumberCountryCode,
+				"family_history_exists":     patient.FamilyHistoryExists,
+				"first_visit_reason":        patient.FirstVisitReason,
+				"wbdr":                      patient.WBDR,
+				"bat_score":                 patient.BATScore,
+				"updated_at":                patient.UpdatedAt,
+			}).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return models.Patient{}, &app.ErrNotFound{
+			ResourceName: "patient",
+		}
+	}
+	if err != nil {
+		return models.Patient{}, err
+	}
+
+	return patient, nil
+}
+
+func (r *Repository) GetPatientById(id uint) (models.Patient, error) {
+	var patient models.Patient
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Patient)).
+			Preload("Residency").
+			Preload("PlaceOfBirth").
+			First(&patient, "id = ?", id).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return models.Patient{}, &app.ErrNotFound{
+			ResourceName: "patient",
+		}
+	}
+	if err != nil {
+		return models.Patient{}, err
+	}
+
+	return patient, nil
+}
+
+func (r *Repository) GetPatientByPublicId(publicId string) (models.Patient, error) {
+	var patient models.Patient
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Patient)).
+			Preload("Residency").
+			Preload("PlaceOfBirth").
+			First(&patient, "public_id = ?", publicId).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return models.Patient{}, &app.ErrNotFound{
+			ResourceName: "patient",
+		}
+	}
+	if err != nil {
+		return models.Patient{}, err
+	}
+
+	return patient, nil
+}
+
+func (r *Repository) FindPatientsByVisitDateRange(from, to time.Time) ([]models.Patient, error) {
+	return nil, errors.New("not inmplemented")
+}
+
+func (r *Repository) FindPatientsByFields(patientIndexFields models.PatientIndexFields) ([]models.Patient, error) {
+	findQuery := make([]string, 0, 9)
+	findArgs := make([]any, 0, 9)
+	if patientIndexFields.FirstName != "" {
+		findQuery = append(findQuery, "LOWER(first_name) LIKE LOWER(?)")
+		findArgs = append(findArgs, likeArg(patientIndexFields.FirstName))
+	}
+	if patientIndexFields.LastName != "" {
+		findQuery = append(findQuery, "LOWER(last_name) LIKE LOWER(?)")
+		findArgs = append(findArgs, likeArg(patientIndexFields.LastName))
+	}
+	if patientIndexFields.FatherName != "" {
+		findQuery = append(findQuery, "LOWER(father_name) LIKE LOWER(?)")
+		findArgs = append(findArgs, likeArg(patientIndexFields.FatherName))
+	}
+	if patientIndexFields.MotherName != "" {
+		findQuery = append(findQuery, "LOWER(mother_name) LIKE LOWER(?)")
+		findArgs = append(findArgs, likeArg(patientIndexFields.MotherName))
+	}
+	if patientIndexFields.PhoneNumber != "" {
+		findQuery = append(findQuery, "LOWER(phone_number) LIKE LOWER(?)")
+		findArgs = append(findArgs, likeArg(patientIndexFields.PhoneNumber))
+	}
+	if patientIndexFields.NationalId != "" {
+		findQuery = append(findQuery, "national_id = ?")
+		findArgs = append(findArgs, patientIndexFields.NationalId)
+	}
+	if patientIndexFields.PublicId != "" {
+		findQuery = append(findQuery, "public_id = ?")
+		findArgs = append(findArgs, patientIndexFields.PublicId)
+	}
+
+	var patients []models.Patient
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Patient)).
+			Where(strings.Join(findQuery, " AND "), findArgs...).
+			Find(&patients).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return nil, &app.ErrNotFound{
+			ResourceName: "patient",
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return patients, nil
+}
+
+func (r *Repository) ListLastPatients(limit int) ([]models.Patient, error) {
+	var patients []models.Patient
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Patient)).
+			Order("created_at DESC").
+			Limit(limit).
+			Find(&patients).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return patients, nil
+}
+
+// DeletePatient deletes the patient with their records in a single
+// transaction, where the stock movements of their visits and the audit events
+// are kept on purpose, since they're the medicines' ledger and the audit trail.
+func (r *Repository) DeletePatient(id uint) error {
+	return r.WithTransaction(func(tx app.Repository) error {
+		txClient := tx.(*Repository).client
+		statements := []string{
+			"DELETE FROM blood_test_filled_fields WHERE blood_test_result_id IN (SELECT id FROM blood_test_results WHERE patient_id = ?)",
+			"DELETE FROM blood_test_results WHERE patient_id = ?",
+			"DELETE FROM has_viruses WHERE patient_id = ?",
+			"DELETE FROM bleeding_episodes WHERE patient_id = ?",
+			"DELETE FROM hjhs_joint_scores WHERE joints_evaluation_id IN (SELECT id FROM joints_evaluations WHERE patient_id = ?)",
+			"DELETE FROM joints_evaluations WHERE patient_id = ?",
+			"DELETE FROM prophylaxes WHERE patient_id = ?",
+			"DELETE FROM prescribed_medicines WHERE patient_id = ?",
+			"DELETE FROM visits WHERE patient_id = ?",
+			"DELETE FROM diagnoses_results WHERE patient_id = ?",
+			"DELETE FROM appointments WHERE patient_id = ?",
+			"DELETE FROM inhibitor_surveillances WHERE patient_id = ?",
+			"DELETE FROM patient_revisions WHERE patient_id = ?",
+		}
+		for _, statement := range statements {
+			err := tryWrapDbError(
+				txClient.
+					Exec(statement, id).
+					Error,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := tryWrapDbError(
+			txClient.
+				Model(new(models.Patient)).
+				Delete(&models.Patient{Id: id}, "id = ?", id).
+				Error,
+		)
+		if _, ok := err.(*ErrRecordNotFound); ok {
+			return &app.ErrNotFound{
+				ResourceName: "patient",
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (r *Repository) CreatePatientVisit(visit models.Visit) (models.Visit, error) {
+	visit.CreatedAt = time.Now().UTC()
+	visit.UpdatedAt = time.Now().UTC()
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Visit)).
+			Create(&visit).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.Visit{}, &app.ErrExists{
+			ResourceName: "visit",
+		}
+	}
+	if err != nil {
+		return models.Visit{}, err
+	}
+
+	return visit, nil
+}
+
+func (r *Repository) ListPatientVisits(patientId uint) ([]models.Visit, error) {
+	var visits []models.Visit
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Visit)).
+			Where("patient_id = ?", patientId).
+			Find(&visits).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return visits, nil
+}
+
+func (r *Repository) GetPatientVisit(visitId uint) (models.Visit, error) {
+	var visit models.Visit
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Visit)).
+			Where("id = ?", visitId).
+			First(&visit).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return models.Visit{}, &app.ErrNotFound{
+			ResourceName: "visit",
+		}
+	}
+	if err != nil {
+		return models.Visit{}, err
+	}
+
+	return visit, nil
+}
+
+func (r *Repository) CreateAppointment(appointment models.Appointment) (models.Appointment, error) {
+	appointment.CreatedAt = time.Now().UTC()
+	appointment.UpdatedAt = time.Now().UTC()
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Appointment)).
+			Create(&appointment).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.Appointment{}, &app.ErrExists{
+			ResourceName: "appointment",
+		}
+	}
+	if err != nil {
+		return models.Appointment{}, err
+	}
+
+	return appointment, nil
+}
+
+func (r *Repository) GetAppointment(id uint) (models.Appointment, error) {
+	var appointment models.Appointment
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Appointment)).
+			Where("id = ?", id).
+			First(&appointment).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return models.Appointment{}, &app.ErrNotFound{
+			ResourceName: "appointment",
+		}
+	}
+	if err != nil {
+		return models.Appointment{}, err
+	}
+
+	return appointment, nil
+}
+
+func (r *Repository) LockAppointment(id uint) (models.Appointment, error) {
+	var appointment models.Appointment
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Appointment)).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", id).
+			First(&appointment).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return models.Appointment{}, &app.ErrNotFound{
+			ResourceName: "appointment",
+		}
+	}
+	if err != nil {
+		return models.Appointment{}, err
+	}
+
+	return appointment, nil
+}
+
+func (r *Repository) ListAppointmentsOnTimeRange(from, to time.Time) ([]models.Appointment, error) {
+	var appointments []models.Appointment
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Appointment)).
+			Where("scheduled_at >= ? AND scheduled_at < ?", from, to).
+			Order("scheduled_at ASC").
+			Find(&appointments).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return appointments, nil
+}
+
+func (r *Repository) ListPatientAppointments(patientId uint) ([]models.Appointment, error) {
+	var appointments []models.Appointment
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Appointment)).
+			Where("patient_id = ?", patientId).
+			Order("scheduled_at ASC").
+			Find(&appointments).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return appointments, nil
+}
+
+func (r *Repository) UpdateAppointmentStatus(id uint, status models.AppointmentStatus, visitId uint) error {
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Appointment)).
+			Where("id = ?", id).
+			Updates(map[string]any{
+				"status":     status,
+				"visit_id":   visitId,
+				"updated_at": time.Now().UTC(),
+			}).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "appointment",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) CreateInhibitorSurveillance(is models.InhibitorSurveillance) (models.InhibitorSurveillance, error) {
+	is.CreatedAt = time.Now().UTC()
+	is.UpdatedAt = time.Now().UTC()
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.InhibitorSurveillance)).
+			Create(&is).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.InhibitorSurveillance{}, &app.ErrExists{
+			ResourceName: "inhibitor_surveillance",
+		}
+	}
+	if err != nil {
+		return models.InhibitorSurveillance{}, err
+	}
+
+	return is, nil
+}
+
+func (r *Repository) GetInhibitorSurveillanceForPatient(patientId uint) (models.InhibitorSurveillance, error) {
+	var is models.InhibitorSurveillance
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.InhibitorSurveillance)).
+			First(&is, "patient_id = ?", patientId).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return models.InhibitorSurveillance{}, &app.ErrNotFound{
+			ResourceName: "inhibitor_surveillance",
+		}
+	}
+	if err != nil {
+		return models.InhibitorSurveillance{}, err
+	}
+
+	return is, nil
+}
+
+func (r *Repository) UpdateInhibitorSurveillance(id uint, is models.InhibitorSurveillance) error {
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.InhibitorSurveillance)).
+			Where("id = ?", id).
+			Updates(map[string]any{
+				"screening_interval_eds": is.ScreeningIntervalEds,
+				"iti_status":             is.ItiStatus,
+				"iti_started_at":         is.ItiStartedAt,
+				"iti_ended_at":           is.ItiEndedAt,
+				"notes":                  is.Notes,
+				"updated_at":             time.Now().UTC(),
+			}).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "inhibitor_surveillance",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) CreatePrescribedMedicine(pm models.PrescribedMedicine) (models.PrescribedMedicine, error) {
+	pm.CreatedAt = time.Now().UTC()
+	pm.UpdatedAt = time.Now().UTC()
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.PrescribedMedicine)).
+			Create(&pm).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.PrescribedMedicine{}, &app.ErrExists{
+			ResourceName: "prescribed_medicine",
+		}
+	}
+	if err != nil {
+		return models.PrescribedMedicine{}, err
+	}
+
+	return pm, nil
+}
+
+func (r *Repository) ListVisitsOnTimeRange(from, to time.Time) ([]models.Visit, error) {
+	var visits []models.Visit
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Visit)).
+			Where("created_at BETWEEN ? AND ?", from, to).
+			Find(&visits).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return visits, nil
+}
+
+func (r *Repository) GetPatientLastVisit(patientId uint) (models.Visit, error) {
+	var visits []models.Visit
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Visit)).
+			Where("patient_id = ?", patientId).
+			Order("created_at DESC").
+			Limit(1).
+			Find(&visits).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return models.Visit{}, &app.ErrNotFound{
+			ResourceName: "visit",
+		}
+	}
+	if err != nil {
+		return models.Visit{}, err
+	}
+
+	if len(visits) == 0 {
+		return models.Visit{}, &app.ErrNotFound{
+			ResourceName: "visit",
+		}
+	}
+
+	return visits[0], nil
+}
+
+func (r *Repository) ListPatientUsedPrescribedMedicinesOnTimeRange(patientId uint, from, to time.Time) ([]models.PrescribedMedicine, error) {
+	var pms []models.PrescribedMedicine
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.PrescribedMedicine)).
+			Where("patient_id = ? AND used_at >= ? AND used_at < ?", patientId, from, to).
+			Order("used_at ASC").
+			Find(&pms).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return pms, nil
+}
+
+func (r *Repository) ListPatientVisitPrescribedMedicine(visitId uint) ([]models.PrescribedMedicine, error) {
+	var pms []models.PrescribedMedicine
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.PrescribedMedicine)).
+			Where("visit_id = ?", visitId).
+			Find(&pms).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	treatmentsMapped, err := r.listTreatmentsForPrescribedMedicines(pms)
+	if err != nil {
+		log.Errorf("ListPatientVisitPrescribedMedicine error: %v\n", err)
+		return nil, err
+	}
+
+	for i := range pms {
+		pms[i].TreatmentDetails = treatmentsMapped[pms[i].TreatmentDetailsId]
+	}
+
+	return pms, nil
+}
+
+func (r *Repository) ListAllPrescribedMedicines() ([]models.PrescribedMedicine, error) {
+	var pms []models.PrescribedMedicine
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.PrescribedMedicine)).
+			Find(&pms).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	treatmentsMapped, err := r.listTreatmentsForPrescribedMedicines(pms)
+	if err != nil {
+		log.Errorf("ListAllPrescribedMedicines error: %v\n", err)
+		return nil, err
+	}
+
+	for i := range pms {
+		pms[i].TreatmentDetails = treatmentsMapped[pms[i].TreatmentDetailsId]
+	}
+
+	return pms, nil
+}
+
+func (r *Repository) listTreatmentsForPrescribedMedicines(pms []models.PrescribedMedicine) (map[uint]models.TreatmentDetails, error) {
+	treatmentIds := make([]uint, 0, len(pms))
+	for _, pm := range pms {
+		if pm.TreatmentDetailsId == 0 {
+			continue
+		}
+		treatmentIds = append(treatmentIds, pm.TreatmentDetailsId)
+	}
+
+	var treatments []models.TreatmentDetails
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.TreatmentDetails)).
+			Where("id in ?", treatmentIds).
+			Find(&treatments).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); !ok && err != nil {
+		return nil, &app.ErrNotFound{
+			ResourceName: "treatment_details",
+		}
+	}
+
+	treatmentsMapped := make(map[uint]models.TreatmentDetails, len(pms))
+	for _, t := range treatments {
+		treatmentsMapped[t.Id] = t
+	}
+
+	return treatmentsMapped, nil
+}
+
+func (r *Repository) UseMedicineForVisit(prescribedMedicineId, visitId, treatmentId uint) error {
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.PrescribedMedicine)).
+			Where("id = ? AND visit_id = ?", prescribedMedicineId, visitId).
+			Update("used_at", time.Now().UTC()).
+			Update("treatment_details_id", treatmentId).
+			Error,
+	)
+
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "prescribed_medicine",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) CreateTreatmentDetails(td models.TreatmentDetails) (models.TreatmentDetails, error) {
+	td.CreatedAt = time.Now().UTC()
+	td.UpdatedAt = time.Now().UTC()
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.TreatmentDetails)).
+			Create(&td).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.TreatmentDetails{}, &app.ErrExists{
+			ResourceName: "treatment_details",
+		}
+	}
+	if err != nil {
+		return models.TreatmentDetails{}, err
+	}
+
+	return td, nil
+}
+
+func (r *Repository) ListAllTreatmentDetails() ([]models.TreatmentDetails, error) {
+	var td []models.TreatmentDetails
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.TreatmentDetails)).
+			Find(&td).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return td, nil
+}
+
+func (r *Repository) DeleteTreatmentDetails(id uint) error {
+	var prescribedMedsUsingTreatment []models.PrescribedMedicine
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.PrescribedMedicine)).
+			Where("treatment_details_id = ?", id).
+			Find(&prescribedMedsUsingTreatment).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); !ok && err != nil {
+		return err
+	}
+
+	err = tryWrapDbError(
+		r.client.
+			Model(new(models.TreatmentDetails)).
+			Delete(&models.TreatmentDetails{Id: id}, "id = ?", id).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "treatment_details",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) CreateAddress(address models.Address) (models.Address, error) {
+	address.CreatedAt = time.Now().UTC()
+	address.UpdatedAt = time.Now().UTC()
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Address)).
+			Create(&address).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.Address{}, &app.ErrExists{
+			ResourceName: "address",
+		}
+	}
+	if err != nil {
+		return models.Address{}, err
+	}
+
+	return address, nil
+}
+
+func (r *Repository) GetAllAddresses() ([]models.Address, error) {
+	var addresses []models.Address
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Address)).
+			Find(&addresses).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return nil, &app.ErrNotFound{
+			ResourceName: "address",
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return addresses, nil
+}
+
+func (r *Repository) GetAllAddressesALike(searchAddress models.Address) ([]models.Address, error) {
+	findQuery := make([]string, 0, 3)
+	findArgs := make([]any, 0, 3)
+	if searchAddress.Governorate != "" {
+		findQuery = append(findQuery, "LOWER(governorate) LIKE LOWER(?)")
+		findArgs = append(findArgs, likeArg(searchAddress.Governorate))
+	}
+	if searchAddress.Suburb != "" {
+		findQuery = append(findQuery, "LOWER(suburb) LIKE LOWER(?)")
+		findArgs = append(findArgs, likeArg(searchAddress.Suburb))
+	}
+	if searchAddress.Street != "" {
+		findQuery = append(findQuery, "LOWER(street) LIKE LOWER(?)")
+		findArgs = append(findArgs, likeArg(searchAddress.Street))
+	}
+
+	var addresses []models.Address
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Address)).
+			Where(strings.Join(findQuery, " AND "), findArgs...).
+			Find(&addresses).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return nil, &app.ErrNotFound{
+			ResourceName: "address",
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return addresses, nil
+}
+
+func (r *Repository) DeleteAddress(id uint) error {
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Address)).
+			Delete(&models.Address{Id: id}, "id = ?", id).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "address",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) CreateJointEvaluation(je models.JointsEvaluation) (models.JointsEvaluation, error) {
+	je.CreatedAt = time.Now().UTC()
+	je.UpdatedAt = time.Now().UTC()
+	for i := range je.Scores {
+		je.Scores[i].CreatedAt = je.CreatedAt
+		je.Scores[i].UpdatedAt = je.UpdatedAt
+	}
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.JointsEvaluation)).
+			Create(&je).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.JointsEvaluation{}, &app.ErrExists{
+			ResourceName: "joints_evaluation",
+		}
+	}
+	if err != nil {
+		return models.JointsEvaluation{}, err
+	}
+
+	return je, nil
+}
+
+func (r *Repository) ListJointEvaluationsForPatient(patientId uint) ([]models.JointsEvaluation, error) {
+	var jes []models.JointsEvaluation
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.JointsEvaluation)).
+			Preload("Scores").
+			Where("patient_id = ?", patientId).
+			Order("created_at ASC").
+			Find(&jes).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return jes, nil
+}
+
+func (r *Repository) CreateProphylaxis(pp models.Prophylaxis) (models.Prophylaxis, error) {
+	pp.CreatedAt = time.Now().UTC()
+	pp.UpdatedAt = time.Now().UTC()
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Prophylaxis)).
+			Create(&pp).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.Prophylaxis{}, &app.ErrExists{
+			ResourceName: "prophylaxis",
+		}
+	}
+	if err != nil {
+		return models.Prophylaxis{}, err
+	}
+
+	return pp, nil
+}
+
+func (r *Repository) ListProphylaxesForPatient(patientId uint) ([]models.Prophylaxis, error) {
+	var pp []models.Prophylaxis
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Prophylaxis)).
+			Preload("Medicine").
+			Where("patient_id = ?", patientId).
+			Find(&pp).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return pp, nil
+}
+
+func (r *Repository) SetProphylaxisEndDateForPatient(id, patientId uint, endDate time.Time) (models.Prophylaxis, error) {
+	var pp models.Prophylaxis
+	err := tryWrapDbError(
+		r.client.
+			Model(&pp).
+			Where("id = ? AND patient_id = ?", id, patientId).
+			Update("end_date", endDate).
+			Update("updated_at", time.Now().UTC()).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return models.Prophylaxis{}, &app.ErrNotFound{
+			ResourceName: "prophylaxis",
+		}
+	}
+	if err != nil {
+		return models.Prophylaxis{}, err
+	}
+
+	return pp, nil
+}
+
+func (r *Repository) SetProphylaxisChosenForPatient(id, patientId uint, chosen bool) (models.Prophylaxis, error) {
+	var pp models.Prophylaxis
+	err := tryWrapDbError(
+		r.client.
+			Model(&pp).
+			Where("id = ? AND patient_id = ?", id, patientId).
+			Update("chosen", chosen).
+			Update("updated_at", time.Now().UTC()).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return models.Prophylaxis{}, &app.ErrNotFound{
+			ResourceName: "prophylaxis",
+		}
+	}
+	if err != nil {
+		return models.Prophylaxis{}, err
+	}
+
+	return pp, nil
+}
+
+func (r *Repository) DeleteProphylaxisForPatient(id, patientId uint) error {
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Prophylaxis)).
+			Delete(&models.Prophylaxis{Id: id}, "id = ? AND patient_id = ?", id, patientId).
+			Error,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) CreateDiagnosis(diagnosis models.Diagnosis) (models.Diagnosis, error) {
+	diagnosis.CreatedAt = time.Now().UTC()
+	diagnosis.UpdatedAt = time.Now().UTC()
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Diagnosis)).
+			Create(&diagnosis).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.Diagnosis{}, &app.ErrExists{
+			ResourceName: "diagnosis",
+		}
+	}
+	if err != nil {
+		return models.Diagnosis{}, err
+	}
+
+	return diagnosis, nil
+}
+
+func (r *Repository) DeleteDiagnisis(id uint) error {
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Diagnosis)).
+			Delete(&models.Diagnosis{Id: id}, "id = ?", id).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "diagnosis",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) ListAllDiagnoses() ([]models.Diagnosis, error) {
+	var diagnoses []models.Diagnosis
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Diagnosis)).
+			Find(&diagnoses).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return diagnoses, nil
+}
+
+func (r *Repository) CreateDiagnosisResult(diagnosis models.DiagnosisResult) (models.DiagnosisResult, error) {
+	diagnosis.CreatedAt = time.Now().UTC()
+	diagnosis.UpdatedAt = time.Now().UTC()
+	if diagnosis.DiagnosedAt.IsZero() {
+		diagnosis.DiagnosedAt = diagnosis.CreatedAt
+	}
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.DiagnosisResult)).
+			Create(&diagnosis).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.DiagnosisResult{}, &app.ErrExists{
+			ResourceName: "diagnosis_result",
+		}
+	}
+	if err != nil {
+		return models.DiagnosisResult{}, err
+	}
+
+	return diagnosis, nil
+}
+
+func (r *Repository) ListPatientDiagnosisResults(patientId uint) ([]models.DiagnosisResult, error) {
+	var diagnoses []models.DiagnosisResult
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.DiagnosisResult)).
+			Where("patient_id = ?", patientId).
+			Find(&diagnoses).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return diagnoses, nil
+}
+
+func (r *Repository) CreateBleedingEpisode(be models.BleedingEpisode) (models.BleedingEpisode, error) {
+	be.CreatedAt = time.Now().UTC()
+	be.UpdatedAt = time.Now().UTC()
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.BleedingEpisode)).
+			Create(&be).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.BleedingEpisode{}, &app.ErrExists{
+			ResourceName: "bleeding_episode",
+		}
+	}
+	if err != nil {
+		return models.BleedingEpisode{}, err
+	}
+
+	return be, nil
+}
+
+func (r *Repository) ListPatientBleedingEpisodes(patientId uint) ([]models.BleedingEpisode, error) {
+	var episodes []models.BleedingEpisode
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.BleedingEpisode)).
+			Where("patient_id = ?", patientId).
+			Order("onset_at DESC").
+			Find(&episodes).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return episodes, nil
+}
+
+// ListBleedingEpisodesOnSites lists all patients' episodes on the given sites,
+// ordered by their onset.
+func (r *Repository) ListBleedingEpisodesOnSites(sites []models.BleedingSite) ([]models.BleedingEpisode, error) {
+	var episodes []models.BleedingEpisode
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.BleedingEpisode)).
+			Where("site IN ?", sites).
+			Order("onset_at ASC").
+			Find(&episodes).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return episodes, nil
+}
+
+func (r *Repository) DeleteBleedingEpisodeForPatient(id, patientId uint) error {
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.BleedingEpisode)).
+			Delete(&models.BleedingEpisode{Id: id}, "id = ? AND patient_id = ?", id, patientId).
+			Error,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) CreateStockMovement(sm models.StockMovement) (models.StockMovement, error) {
+	sm.CreatedAt = time.Now().UTC()
+	sm.UpdatedAt = time.Now().UTC()
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.StockMovement)).
+			Create(&sm).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.StockMovement{}, &app.ErrExists{
+			ResourceName: "stock_movement",
+		}
+	}
+	if err != nil {
+		return models.StockMovement{}, err
+	}
+
+	return sm, nil
+}
+
+func (r *Repository) ListMedicineStockMovements(medicineId uint) ([]models.StockMovement, error) {
+	var movements []models.StockMovement
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.StockMovement)).
+			Where("medicine_id = ?", medicineId).
+			Order("id ASC").
+			Find(&movements).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+func (r *Repository) CreateMedicineAlerts(alerts []models.MedicineAlert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	for i := range alerts {
+		alerts[i].CreatedAt = time.Now().UTC()
+		alerts[i].UpdatedAt = time.Now().UTC()
+	}
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.MedicineAlert)).
+			Create(&alerts).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return &app.ErrExists{
+			ResourceName: "medicine_alert",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) ListAllMedicineAlerts() ([]models.MedicineAlert, error) {
+	var alerts []models.MedicineAlert
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.MedicineAlert)).
+			Order("expires_at ASC").
+			Find(&alerts).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+func (r *Repository) DeleteAllMedicineAlerts() error {
+	err := tryWrapDbError(
+		r.client.
+			Exec(fmt.Sprintf("DELETE FROM %s;", models.MedicineAlert{}.TableName())).
+			Error,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) CreateAuditEvent(event models.AuditEvent) (models.AuditEvent, error) {
+	event.CreatedAt = time.Now().UTC()
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.AuditEvent)).
+			Create(&event).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.AuditEvent{}, &app.ErrExists{
+			ResourceName: "audit_event",
+		}
+	}
+	if err != nil {
+		return models.AuditEvent{}, err
+	}
+
+	return event, nil
+}
+
+func (r *Repository) ListAuditEvents(filter models.AuditEventsFilter) ([]models.AuditEvent, error) {
+	var events []models.AuditEvent
+
+	query := r.client.
+		Model(new(models.AuditEvent)).
+		Order("id DESC")
+	if filter.AccountId != 0 {
+		query = query.Where("account_id = ?", filter.AccountId)
+	}
+	if filter.PatientPublicId != "" {
+		query = query.Where("patient_public_id = ?", filter.PatientPublicId)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at <= ?", filter.To)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	err := tryWrapDbError(
+		query.
+			Find(&events).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// CreatePatientRevision keeps the revision's CreatedAt when it's set, so that
+// the patient's details from before their revisions were recorded keep their
+// update's time.
+func (r *Repository) CreatePatientRevision(revision models.PatientRevision) (models.PatientRevision, error) {
+	if revision.CreatedAt.IsZero() {
+		revision.CreatedAt = time.Now().UTC()
+	}
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.PatientRevision)).
+			Omit(clause.Associations).
+			Create(&revision).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.PatientRevision{}, &app.ErrExists{
+			ResourceName: "patient_revision",
+		}
+	}
+	if err != nil {
+		return models.PatientRevision{}, err
+	}
+
+	return revision, nil
+}
+
+func (r *Repository) GetPatientRevision(id uint) (models.PatientRevision, error) {
+	var revision models.PatientRevision
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.PatientRevision)).
+			Preload("Residency").
+			Preload("PlaceOfBirth").
+			First(&revision, "id = ?", id).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return models.PatientRevision{}, &app.ErrNotFound{
+			ResourceName: "patient_revision",
+		}
+	}
+	if err != nil {
+		return models.PatientRevision{}, err
+	}
+
+	return revision, nil
+}
+
+func (r *Repository) ListPatientRevisions(patientId uint) ([]models.PatientRevision, error) {
+	var revisions []models.PatientRevision
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.PatientRevision)).
+			Preload("Residency").
+			Preload("PlaceOfBirth").
+			Where("patient_id = ?", patientId).
+			Order("id ASC").
+			Find(&revisions).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (r *Repository) CountPatientsOnTimeRange(from, to time.Time) (int, error) {
+	var count int64
+
+	condition, args := patientsOnTimeRangeCondition(from, to)
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Patient)).
+			Where(condition, args...).
+			Count(&count).
+			Error,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (r *Repository) CountPatientsByDiagnosisGroup(from, to time.Time) ([]models.StatisticsCount, error) {
+	counts := make([]models.StatisticsCount, 0)
+
+	condition, args := patientsOnTimeRangeCondition(from, to)
+	query := fmt.Sprintf(`SELECT COALESCE(%[2]s.group_name, '') AS name, COUNT(DISTINCT patients.id) AS count
+	FROM patients
+		LEFT JOIN %[1]s ON %[1]s.patient_id = patients.id
+		LEFT JOIN %[2]s ON %[2]s.id = %[1]s.diagnosis_id
+	WHERE %[3]s
+	GROUP BY %[2]s.group_name`, models.DiagnosisResult{}.TableName(), models.Diagnosis{}.TableName(), condition)
+
+	err := tryWrapDbError(
+		r.client.
+			Raw(query, args...).
+			Scan(&counts).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (r *Repository) CountPatientsByGender(from, to time.Time) ([]models.StatisticsCount, error) {
+	counts := make([]models.StatisticsCount, 0)
+
+	condition, args := patientsOnTimeRangeCondition(from, to)
+	query := fmt.Sprintf(`SELECT CASE WHEN patients.gender THEN 'male' ELSE 'female' END AS name, COUNT(*) AS count
+	FROM patients
+	WHERE %s
+	GROUP BY patients.gender`, condition)
+
+	err := tryWrapDbError(
+		r.client.
+			Raw(query, args...).
+			Scan(&counts).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (r *Repository) CountPatientsByAgeBand(from, to time.Time) ([]models.StatisticsCount, error) {
+	counts := make([]models.StatisticsCount, 0)
+
+	condition, args := patientsOnTimeRangeCondition(from, to)
+	age, ageArgs := r.dialect.AgeInYears("patients.date_of_birth", to)
+	query := fmt.Sprintf(`SELECT
+		CASE
+			WHEN age < 5 THEN '%s'
+			WHEN age < 14 THEN '%s'
+			WHEN age < 19 THEN '%s'
+			WHEN age < 45 THEN '%s'
+			ELSE '%s'
+		END AS name,
+		COUNT(*) AS count
+	FROM (
+		SELECT %s AS age
+		FROM patients
+		WHERE %s
+	) AS patient_ages
+	GROUP BY name`,
+		models.AgeBand0To4, models.AgeBand5To13, models.AgeBand14To18, models.AgeBand19To44, models.AgeBand45Plus,
+		age, condition,
+	)
+
+	err := tryWrapDbError(
+		r.client.
+			Raw(query, append(ageArgs, args...)...).
+			Scan(&counts).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (r *Repository) CountPatientsByResidencyGovernorate(from, to time.Time) ([]models.StatisticsCount, error) {
+	counts := make([]models.StatisticsCount, 0)
+
+	condition, args := patientsOnTimeRangeCondition(from, to)
+	query := fmt.Sprintf(`SELECT %[1]s.governorate AS name, COUNT(*) AS count
+	FROM patients
+		JOIN %[1]s ON %[1]s.id = patients.residency_id
+	WHERE %[2]s
+	GROUP BY %[1]s.governorate`, models.Address{}.TableName(), condition)
+
+	err := tryWrapDbError(
+		r.client.
+			Raw(query, args...).
+			Scan(&counts).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (r *Repository) CountPatientsByVirus(from, to time.Time) ([]models.StatisticsCount, error) {
+	counts := make([]models.StatisticsCount, 0)
+
+	condition, args := patientsOnTimeRangeCondition(from, to)
+	query := fmt.Sprintf(`SELECT COALESCE(%[2]s.name, '') AS name, COUNT(DISTINCT patients.id) AS count
+	FROM patients
+		LEFT JOIN %[1]s ON %[1]s.patient_id = patients.id
+		LEFT JOIN %[2]s ON %[2]s.id = %[1]s.virus_id
+	WHERE %[3]s
+	GROUP BY %[2]s.name`, models.HasVirus{}.TableName(), models.Virus{}.TableName(), condition)
+
+	err := tryWrapDbError(
+		r.client.
+			Raw(query, args...).
+			Scan(&counts).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (r *Repository) CountVisitsByReason(from, to time.Time) ([]models.StatisticsCount, error) {
+	counts := make([]models.StatisticsCount, 0)
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.Visit)).
+			Select("reason AS name, COUNT(*) AS count").
+			Where("created_at BETWEEN ? AND ?", from, to).
+			Group("reason").
+			Scan(&counts).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// patientsOnTimeRangeCondition returns a where clause that matches patients who were either
+// registered or had a visit in the given time range, so that statistics reflect active patients.
+func patientsOnTimeRangeCondition(from, to time.Time) (string, []any) {
+	return fmt.Sprintf("(patients.created_at BETWEEN ? AND ? OR patients.id IN (SELECT patient_id FROM %s WHERE created_at BETWEEN ? AND ?))", models.Visit{}.TableName()),
+		[]any{from, to, from, to}
+}
+
+func likeArg(arg string) string {
+	return fmt.Sprintf("%%%s%%", arg)
+}
+
+func (r *Repository) CreateLabCodeMapping(mapping models.LabCodeMapping) (models.LabCodeMapping, error) {
+	mapping.CreatedAt = time.Now().UTC()
+	mapping.UpdatedAt = time.Now().UTC()
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.LabCodeMapping)).
+			Create(&mapping).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordExists); ok {
+		return models.LabCodeMapping{}, &app.ErrExists{
+			ResourceName: "lab_code_mapping",
+		}
+	}
+	if err != nil {
+		return models.LabCodeMapping{}, err
+	}
+
+	return mapping, nil
+}
+
+func (r *Repository) ListLabCodeMappings() ([]models.LabCodeMapping, error) {
+	var mappings []models.LabCodeMapping
+
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.LabCodeMapping)).
+			Order("coding_system ASC, code ASC").
+			Find(&mappings).
+			Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return mappings, nil
+}
+
+func (r *Repository) DeleteLabCodeMapping(id uint) error {
+	err := tryWrapDbError(
+		r.client.
+			Model(new(models.LabCodeMapping)).
+			Delete(&models.LabCodeMapping{Id: id}, "id = ?", id).
+			Error,
+	)
+	if _, ok := err.(*ErrRecordNotFound); ok {
+		return &app.ErrNotFound{
+			ResourceName: "lab_code_mapping",
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
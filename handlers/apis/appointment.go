@@ -0,0 +1,136 @@
+package apis
+
+import (
+	"encoding/json"
+	"net/http"
+	"shs/actions"
+	"shs/log"
+	"strconv"
+	"time"
+)
+
+type appointmentApi struct {
+	usecases *actions.Actions
+}
+
+func NewAppointmentApi(usecases *actions.Actions) *appointmentApi {
+	return &appointmentApi{
+		usecases: usecases,
+	}
+}
+
+type createAppointmentRequest struct {
+	PatientId string `json:"patient_id"`
+	actions.Appointment
+}
+
+func (e *appointmentApi) HandleCreateAppointment(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	var reqBody createAppointmentRequest
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		handleErrorResponse(w, err)
+		return
+	}
+
+	payload, err := e.usecases.CreateAppointment(actions.CreateAppointmentParams{
+		ActionContext: ctx,
+		PatientId:     reqBody.PatientId,
+		Appointment:   reqBody.Appointment,
+	})
+	if err != nil {
+		log.Errorf("[APPOINTMENT API]: Failed to create appointment: %+v, error: %s\n", reqBody, err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *appointmentApi) HandleListAppointmentsAgenda(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	var date time.Time
+	if dateStr := r.URL.Query().Get("date"); dateStr != "" {
+		date, err = time.ParseInLocation(time.DateOnly, dateStr, time.Local)
+		if err != nil {
+			handleErrorResponse(w, actions.ErrValidation{Field: "date"})
+			return
+		}
+	}
+
+	payload, err := e.usecases.ListAppointmentsAgenda(actions.ListAppointmentsAgendaParams{
+		ActionContext: ctx,
+		Date:          date,
+	})
+	if err != nil {
+		log.Errorf("[APPOINTMENT API]: Failed to list appointments agenda, error: %s\n", err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *appointmentApi) HandleUpdateAppointmentStatus(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	var reqBody actions.UpdateAppointmentStatusParams
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		handleErrorResponse(w, err)
+		return
+	}
+	reqBody.ActionContext = ctx
+	reqBody.AppointmentId = uint(id)
+
+	payload, err := e.usecases.UpdateAppointmentStatus(reqBody)
+	if err != nil {
+		log.Errorf("[APPOINTMENT API]: Failed to update appointment status: %+v, error: %s\n", reqBody, err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *appointmentApi) HandleListPatientAppointments(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	payload, err := e.usecases.ListPatientAppointments(actions.ListPatientAppointmentsParams{
+		ActionContext: ctx,
+		PatientId:     r.PathValue("id"),
+	})
+	if err != nil {
+		log.Errorf("[APPOINTMENT API]: Failed to list patient appointments, error: %s\n", err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,125 @@
+package apis
+
+import (
+	"encoding/json"
+	"net/http"
+	"shs/actions"
+	"shs/log"
+	"strconv"
+	"time"
+)
+
+type auditEventApi struct {
+	usecases *actions.Actions
+}
+
+func NewAuditEventApi(usecases *actions.Actions) *auditEventApi {
+	return &auditEventApi{
+		usecases: usecases,
+	}
+}
+
+// auditEventsFilter is the listed and the exported events' filter, where
+// patient_id, account_id, from and to are all optional.
+type auditEventsFilter struct {
+	PatientPublicId string
+	AccountId       uint
+	From            time.Time
+	To              time.Time
+}
+
+func parseAuditEventsFilter(r *http.Request) (auditEventsFilter, error) {
+	filter := auditEventsFilter{
+		PatientPublicId: r.URL.Query().Get("patient_id"),
+	}
+
+	if accountIdStr := r.URL.Query().Get("account_id"); accountIdStr != "" {
+		accountId, err := strconv.Atoi(accountIdStr)
+		if err != nil {
+			return auditEventsFilter{}, actions.ErrValidation{Field: "account_id"}
+		}
+		filter.AccountId = uint(accountId)
+	}
+
+	if from := r.URL.Query().Get("from"); from != "" {
+		var err error
+		filter.From, err = time.Parse(time.DateOnly, from)
+		if err != nil {
+			return auditEventsFilter{}, actions.ErrValidation{Field: "from"}
+		}
+	}
+
+	if to := r.URL.Query().Get("to"); to != "" {
+		var err error
+		filter.To, err = time.Parse(time.DateOnly, to)
+		if err != nil {
+			return auditEventsFilter{}, actions.ErrValidation{Field: "to"}
+		}
+		// include the whole end day.
+		filter.To = filter.To.AddDate(0, 0, 1)
+	}
+
+	return filter, nil
+}
+
+func (e *auditEventApi) HandleListAuditEvents(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	filter, err := parseAuditEventsFilter(r)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	payload, err := e.usecases.ListAuditEvents(actions.ListAuditEventsParams{
+		ActionContext:   ctx,
+		AccountId:       filter.AccountId,
+		PatientPublicId: filter.PatientPublicId,
+		From:            filter.From,
+		To:              filter.To,
+	})
+	if err != nil {
+		log.Errorf("[AUDIT EVENT API]: Failed to list audit events, error: %s\n", err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+// HandleExportAuditEvents responds with the filtered events as a CSV file,
+// rather than the payload, so that it's reviewed as is.
+func (e *auditEventApi) HandleExportAuditEvents(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	filter, err := parseAuditEventsFilter(r)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	payload, err := e.usecases.ExportAuditEvents(actions.ExportAuditEventsParams{
+		ActionContext:   ctx,
+		AccountId:       filter.AccountId,
+		PatientPublicId: filter.PatientPublicId,
+		From:            filter.From,
+		To:              filter.To,
+	})
+	if err != nil {
+		log.Errorf("[AUDIT EVENT API]: Failed to export audit events, error: %s\n", err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="audit-events.csv"`)
+	_, _ = w.Write([]byte(payload.Csv))
+}
@@ -114,3 +114,210 @@ func (e *bloodTestApi) HandleDeleteBloodTest(w http.ResponseWriter, r *http.Requ
 
 	_ = json.NewEncoder(w).Encode(payload)
 }
+
+func (e *bloodTestApi) HandleCreateBloodTestReferenceRange(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	fieldId, err := strconv.Atoi(r.PathValue("field_id"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	var reqBody actions.CreateBloodTestReferenceRangeParams
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		handleErrorResponse(w, err)
+		return
+	}
+	reqBody.ActionContext = ctx
+	reqBody.BloodTestId = uint(id)
+	reqBody.BloodTestFieldId = uint(fieldId)
+
+	payload, err := e.usecases.CreateBloodTestReferenceRange(reqBody)
+	if err != nil {
+		log.Errorf("[BLOODTEST API]: Failed to create blood test reference range: %+v, error: %s\n", reqBody, err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *bloodTestApi) HandleUpdateBloodTestFieldLoincCode(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	fieldId, err := strconv.Atoi(r.PathValue("field_id"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	var reqBody actions.UpdateBloodTestFieldLoincCodeParams
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		handleErrorResponse(w, err)
+		return
+	}
+	reqBody.ActionContext = ctx
+	reqBody.BloodTestId = uint(id)
+	reqBody.BloodTestFieldId = uint(fieldId)
+
+	payload, err := e.usecases.UpdateBloodTestFieldLoincCode(reqBody)
+	if err != nil {
+		log.Errorf("[BLOODTEST API]: Failed to update blood test field's loinc code: %+v, error: %s\n", reqBody, err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *bloodTestApi) HandleDeleteBloodTestReferenceRange(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	payload, err := e.usecases.DeleteBloodTestReferenceRange(actions.DeleteBloodTestReferenceRangeParams{
+		ActionContext:    ctx,
+		ReferenceRangeId: uint(id),
+	})
+	if err != nil {
+		log.Errorf("[BLOODTEST API]: Failed to delete blood test reference range, error: %s\n", err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *bloodTestApi) HandleListOutOfRangeBloodTestResults(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	var days int
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		days, err = strconv.Atoi(daysStr)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			handleErrorResponse(w, err)
+			return
+		}
+	}
+
+	payload, err := e.usecases.ListOutOfRangeBloodTestResults(actions.ListOutOfRangeBloodTestResultsParams{
+		ActionContext: ctx,
+		Days:          days,
+	})
+	if err != nil {
+		log.Errorf("[BLOODTEST API]: Failed to list out of range blood test results, error: %s\n", err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *bloodTestApi) HandleCreateLabCodeMapping(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	var reqBody actions.CreateLabCodeMappingParams
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		handleErrorResponse(w, err)
+		return
+	}
+	reqBody.ActionContext = ctx
+
+	payload, err := e.usecases.CreateLabCodeMapping(reqBody)
+	if err != nil {
+		log.Errorf("[BLOODTEST API]: Failed to create lab code mapping: %+v, error: %s\n", reqBody, err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *bloodTestApi) HandleListLabCodeMappings(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	payload, err := e.usecases.ListLabCodeMappings(actions.ListLabCodeMappingsParams{
+		ActionContext: ctx,
+	})
+	if err != nil {
+		log.Errorf("[BLOODTEST API]: Failed to list lab code mappings, error: %s\n", err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *bloodTestApi) HandleDeleteLabCodeMapping(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	payload, err := e.usecases.DeleteLabCodeMapping(actions.DeleteLabCodeMappingParams{
+		ActionContext:    ctx,
+		LabCodeMappingId: uint(id),
+	})
+	if err != nil {
+		log.Errorf("[BLOODTEST API]: Failed to delete lab code mapping, error: %s\n", err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
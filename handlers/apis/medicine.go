@@ -147,3 +147,107 @@ func (e *medicineApi) HandleGetMedicine(w http.ResponseWriter, r *http.Request)
 
 	_ = json.NewEncoder(w).Encode(payload)
 }
+
+func (e *medicineApi) HandleCreateMedicineStockMovement(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	var params actions.CreateMedicineStockMovementParams
+	err = json.NewDecoder(r.Body).Decode(&params)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		handleErrorResponse(w, err)
+		return
+	}
+	params.ActionContext = ctx
+	params.MedicineId = uint(id)
+
+	payload, err := e.usecases.CreateMedicineStockMovement(params)
+	if err != nil {
+		log.Errorf("[MEDICINE API]: Failed to create medicine stock movement, error: %s\n", err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *medicineApi) HandleListMedicineStockMovements(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	payload, err := e.usecases.ListMedicineStockMovements(actions.ListMedicineStockMovementsParams{
+		ActionContext: ctx,
+		MedicineId:    uint(id),
+	})
+	if err != nil {
+		log.Errorf("[MEDICINE API]: Failed to list medicine stock movements, error: %s\n", err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *medicineApi) HandleListMedicineAlerts(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	payload, err := e.usecases.ListMedicineAlerts(actions.ListMedicineAlertsParams{
+		ActionContext: ctx,
+	})
+	if err != nil {
+		log.Errorf("[MEDICINE API]: Failed to list medicine alerts, error: %s\n", err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *medicineApi) HandleCalculateFactorDose(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	var params actions.CalculateFactorDoseParams
+	err = json.NewDecoder(r.Body).Decode(&params)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		handleErrorResponse(w, err)
+		return
+	}
+	params.ActionContext = ctx
+
+	payload, err := e.usecases.CalculateFactorDose(params)
+	if err != nil {
+		log.Errorf("[MEDICINE API]: Failed to calculate factor dose, error: %s\n", err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
@@ -9,6 +9,7 @@ import (
 	"shs/log"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type patientApi struct {
@@ -345,6 +346,317 @@ func (e *patientApi) HandleListPatientJointsEvaluations(w http.ResponseWriter, r
 	_ = json.NewEncoder(w).Encode(payload)
 }
 
+func (e *patientApi) HandleCreatePatientBleedingEpisode(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	var reqBody actions.CreatePatientBleedingEpisodeParams
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	reqBody.ActionContext = ctx
+	reqBody.PatientId = r.PathValue("id")
+
+	payload, err := e.usecases.CreatePatientBleedingEpisode(reqBody)
+	if err != nil {
+		log.Errorf("[PATIENT API]: Failed to create patient's bleeding episode: %+v, error: %s\n", reqBody, err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *patientApi) HandleListPatientBleedingEpisodes(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	payload, err := e.usecases.ListPatientBleedingEpisodes(actions.ListPatientBleedingEpisodesParams{
+		ActionContext: ctx,
+		PatientId:     r.PathValue("id"),
+	})
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *patientApi) HandleDeletePatientBleedingEpisode(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	beId, err := strconv.Atoi(r.PathValue("be_id"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	payload, err := e.usecases.DeletePatientBleedingEpisode(actions.DeletePatientBleedingEpisodeParams{
+		ActionContext:     ctx,
+		PatientId:         r.PathValue("id"),
+		BleedingEpisodeId: uint(beId),
+	})
+	if err != nil {
+		log.Errorf("[PATIENT API]: Failed to delete patient's bleeding episode, error: %s\n", err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *patientApi) HandleGetPatientBleedingRates(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	payload, err := e.usecases.GetPatientBleedingRates(actions.GetPatientBleedingRatesParams{
+		ActionContext: ctx,
+		PatientId:     r.PathValue("id"),
+	})
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *patientApi) HandleGetPatientTargetJoints(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	payload, err := e.usecases.GetPatientTargetJoints(actions.GetPatientTargetJointsParams{
+		ActionContext: ctx,
+		PatientId:     r.PathValue("id"),
+	})
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *patientApi) HandleListPatientsWithTargetJoints(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	payload, err := e.usecases.ListPatientsWithTargetJoints(actions.ListPatientsWithTargetJointsParams{
+		ActionContext: ctx,
+	})
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *patientApi) HandleGetPatientInhibitorSurveillance(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	payload, err := e.usecases.GetPatientInhibitorSurveillance(actions.GetPatientInhibitorSurveillanceParams{
+		ActionContext: ctx,
+		PatientId:     r.PathValue("id"),
+	})
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *patientApi) HandleUpdatePatientInhibitorSurveillance(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	var params actions.UpdatePatientInhibitorSurveillanceParams
+	err = json.NewDecoder(r.Body).Decode(&params)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	params.ActionContext = ctx
+	params.PatientId = r.PathValue("id")
+
+	payload, err := e.usecases.UpdatePatientInhibitorSurveillance(params)
+	if err != nil {
+		log.Errorf("[PATIENT API]: Failed to update patient's inhibitor surveillance: %+v, error: %s\n", params, err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *patientApi) HandleListInhibitorScreeningsDue(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	payload, err := e.usecases.ListInhibitorScreeningsDue(actions.ListInhibitorScreeningsDueParams{
+		ActionContext: ctx,
+	})
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+// HandleExportPatientFhir responds with the patient's record as a FHIR R4
+// bundle, rather than the payload, so that FHIR clients can read it as is.
+func (e *patientApi) HandleExportPatientFhir(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	payload, err := e.usecases.ExportPatientFhir(actions.ExportPatientFhirParams{
+		ActionContext:   ctx,
+		PatientPublicId: r.PathValue("id"),
+	})
+	if err != nil {
+		log.Errorf("[PATIENT API]: Failed to export patient's fhir bundle, error: %s\n", err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/fhir+json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(payload.Bundle)
+}
+
+func (e *patientApi) HandleListPatientBloodTestTrends(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	var fieldId int
+	if fieldIdStr := r.URL.Query().Get("field_id"); fieldIdStr != "" {
+		fieldId, err = strconv.Atoi(fieldIdStr)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			handleErrorResponse(w, err)
+			return
+		}
+	}
+
+	payload, err := e.usecases.ListPatientBloodTestTrends(actions.ListPatientBloodTestTrendsParams{
+		ActionContext:    ctx,
+		PatientId:        r.PathValue("id"),
+		BloodTestFieldId: uint(fieldId),
+	})
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *patientApi) HandleGetPatientProphylaxisAdherence(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	var from, to time.Time
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		from, err = time.ParseInLocation(time.DateOnly, fromStr, time.Local)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			handleErrorResponse(w, err)
+			return
+		}
+	}
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		to, err = time.ParseInLocation(time.DateOnly, toStr, time.Local)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			handleErrorResponse(w, err)
+			return
+		}
+		// include the whole end day.
+		to = to.AddDate(0, 0, 1)
+	}
+
+	payload, err := e.usecases.GetPatientProphylaxisAdherence(actions.GetPatientProphylaxisAdherenceParams{
+		ActionContext: ctx,
+		PatientId:     r.PathValue("id"),
+		From:          from,
+		To:            to,
+	})
+	if err != nil {
+		log.Errorf("[PATIENT API]: Failed to get patient prophylaxis adherence, error: %s\n", err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *patientApi) HandleReportOwnBleedingEpisode(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	var reqBody actions.ReportOwnBleedingEpisodeParams
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	reqBody.ActionContext = ctx
+
+	payload, err := e.usecases.ReportOwnBleedingEpisode(reqBody)
+	if err != nil {
+		log.Errorf("[PATIENT API]: Failed to report own bleeding episode: %+v, error: %s\n", reqBody, err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
 func validateFileType(r io.ReadSeeker, wantedTypes ...string) error {
 	reader := bufio.NewReader(r)
 
@@ -437,3 +749,85 @@ func (e *patientApi) HandleUsePrescribedMedicineForVisit(w http.ResponseWriter,
 
 	_ = json.NewEncoder(w).Encode(payload)
 }
+
+func (e *patientApi) HandleGetOwnCalendarFeedToken(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	payload, err := e.usecases.GetOwnCalendarFeedToken(actions.GetOwnCalendarFeedTokenParams{
+		ActionContext: ctx,
+	})
+	if err != nil {
+		log.Errorf("[PATIENT API]: Failed to get own calendar feed token, error: %s\n", err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+// HandleGetOwnCalendarFeed isn't behind the auth middleware, since calendar
+// apps can only pass the calendar feed token in the subscribed url.
+func (e *patientApi) HandleGetOwnCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	payload, err := e.usecases.GetOwnCalendarFeed(actions.GetOwnCalendarFeedParams{
+		Token: r.URL.Query().Get("token"),
+	})
+	if err != nil {
+		log.Errorf("[PATIENT API]: Failed to get own calendar feed, error: %s\n", err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="calendar.ics"`)
+	_, _ = w.Write([]byte(payload.Calendar))
+}
+
+func (e *patientApi) HandleListPatientRevisions(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	payload, err := e.usecases.ListPatientRevisions(actions.ListPatientRevisionsParams{
+		ActionContext:   ctx,
+		PatientPublicId: r.PathValue("id"),
+	})
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func (e *patientApi) HandleRestorePatientRevision(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	revisionId, err := strconv.Atoi(r.PathValue("revision_id"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	payload, err := e.usecases.RestorePatientRevision(actions.RestorePatientRevisionParams{
+		ActionContext:   ctx,
+		PatientPublicId: r.PathValue("id"),
+		RevisionId:      uint(revisionId),
+	})
+	if err != nil {
+		log.Errorf("[PATIENT API]: Failed to restore patient's revision, error: %s\n", err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"shs/actions"
 	"shs/handlers/middlewares/auth"
+	"shs/handlers/middlewares/clientinfo"
 )
 
 func parseContext(ctx context.Context) (actions.ActionContext, error) {
@@ -12,7 +13,12 @@ func parseContext(ctx context.Context) (actions.ActionContext, error) {
 		return actions.ActionContext{}, &ErrUnauthorized{}
 	}
 
+	clientIp, _ := ctx.Value(clientinfo.ClientIpKey).(string)
+	userAgent, _ := ctx.Value(clientinfo.UserAgentKey).(string)
+
 	return actions.ActionContext{
-		Account: account,
+		Account:   account,
+		ClientIp:  clientIp,
+		UserAgent: userAgent,
 	}, nil
 }
@@ -0,0 +1,58 @@
+package apis
+
+import (
+	"encoding/json"
+	"net/http"
+	"shs/actions"
+	"shs/log"
+	"time"
+)
+
+type statisticsApi struct {
+	usecases *actions.Actions
+}
+
+func NewStatisticsApi(usecases *actions.Actions) *statisticsApi {
+	return &statisticsApi{
+		usecases: usecases,
+	}
+}
+
+func (e *statisticsApi) HandleGetStatistics(w http.ResponseWriter, r *http.Request) {
+	ctx, err := parseContext(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	params := actions.GetStatisticsParams{
+		ActionContext: ctx,
+	}
+
+	if startDate := r.URL.Query().Get("start_date"); startDate != "" {
+		params.StartDate, err = time.Parse(time.DateOnly, startDate)
+		if err != nil {
+			handleErrorResponse(w, actions.ErrValidation{Field: "start_date"})
+			return
+		}
+	}
+
+	if endDate := r.URL.Query().Get("end_date"); endDate != "" {
+		params.EndDate, err = time.Parse(time.DateOnly, endDate)
+		if err != nil {
+			handleErrorResponse(w, actions.ErrValidation{Field: "end_date"})
+			return
+		}
+		// include the whole end day.
+		params.EndDate = params.EndDate.AddDate(0, 0, 1)
+	}
+
+	payload, err := e.usecases.GetStatistics(params)
+	if err != nil {
+		log.Errorf("[STATISTICS API]: Failed to get statistics, error: %s\n", err.Error())
+		handleErrorResponse(w, err)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,67 @@
+package clientinfo
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"shs/config"
+	"strings"
+)
+
+const (
+	ClientIpKey  = "client-ip"
+	UserAgentKey = "user-agent"
+)
+
+func Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), ClientIpKey, clientIp(r))
+		ctx = context.WithValue(ctx, UserAgentKey, r.Header.Get("User-Agent"))
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// clientIp is the request's remote address, unless it's a trusted proxy's,
+// where it's the right-most address of X-Forwarded-For that isn't a trusted
+// proxy's, since the addresses on its left are set by the client.
+func clientIp(r *http.Request) string {
+	remoteIp, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remoteIp = r.RemoteAddr
+	}
+	if !trustedProxy(remoteIp) {
+		return remoteIp
+	}
+
+	ip := remoteIp
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		ip = hop
+		if !trustedProxy(hop) {
+			break
+		}
+	}
+
+	return ip
+}
+
+func trustedProxy(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, prefix := range config.Env().TrustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,120 @@
+package apis
+
+import (
+	"encoding/json"
+	"net/http"
+	"shs/actions"
+	"shs/handlers/web/context"
+	"shs/log"
+	"shs/web/i18n"
+	"shs/web/views/components"
+	"strconv"
+	"time"
+)
+
+type appointmentApi struct {
+	usecases *actions.Actions
+}
+
+func NewAppointmentApi(usecases *actions.Actions) *appointmentApi {
+	return &appointmentApi{
+		usecases: usecases,
+	}
+}
+
+type AppointmentRequest struct {
+	PatientId   string `json:"patient_id"`
+	Reason      string `json:"reason"`
+	ScheduledAt string `json:"scheduled_at"`
+	AccountId   string `json:"account_id"`
+	Notes       string `json:"notes"`
+}
+
+func clusterFuckAppointmentToActionsOne(a AppointmentRequest) (actions.Appointment, error) {
+	// the form's time is the clinic's local time.
+	scheduledAt, err := time.ParseInLocation("2006-01-02T15:04", a.ScheduledAt, time.Local)
+	if err != nil {
+		return actions.Appointment{}, err
+	}
+
+	var accountId int
+	if a.AccountId != "" {
+		accountId, err = strconv.Atoi(a.AccountId)
+		if err != nil {
+			return actions.Appointment{}, err
+		}
+	}
+
+	return actions.Appointment{
+		Reason:      a.Reason,
+		ScheduledAt: scheduledAt.UTC(),
+		AccountId:   uint(accountId),
+		Notes:       a.Notes,
+	}, nil
+}
+
+func (v *appointmentApi) HandleCreateAppointment(w http.ResponseWriter, r *http.Request) {
+	ctx, err := context.Parse(r.Context())
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	var reqBody AppointmentRequest
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	appointment, err := clusterFuckAppointmentToActionsOne(reqBody)
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	_, err = v.usecases.CreateAppointment(actions.CreateAppointmentParams{
+		ActionContext: ctx,
+		PatientId:     reqBody.PatientId,
+		Appointment:   appointment,
+	})
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	writeRawTextResponse(w, i18n.Strings("en").MessageSuccess)
+}
+
+func (v *appointmentApi) HandleUpdateAppointmentStatus(w http.ResponseWriter, r *http.Request) {
+	ctx, err := context.Parse(r.Context())
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	_, err = v.usecases.UpdateAppointmentStatus(actions.UpdateAppointmentStatusParams{
+		ActionContext: ctx,
+		AppointmentId: uint(id),
+		Status:        r.PathValue("status"),
+	})
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	writeRawTextResponse(w, i18n.Strings("en").MessageSuccess)
+}
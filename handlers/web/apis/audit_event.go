@@ -0,0 +1,74 @@
+package apis
+
+import (
+	"net/http"
+	"shs/actions"
+	"shs/handlers/web/context"
+	"shs/log"
+	"shs/web/i18n"
+	"shs/web/views/components"
+	"strconv"
+	"time"
+)
+
+type auditEventApi struct {
+	usecases *actions.Actions
+}
+
+func NewAuditEventApi(usecases *actions.Actions) *auditEventApi {
+	return &auditEventApi{
+		usecases: usecases,
+	}
+}
+
+func (a *auditEventApi) HandleExportAuditEvents(w http.ResponseWriter, r *http.Request) {
+	ctx, err := context.Parse(r.Context())
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	params := actions.ExportAuditEventsParams{
+		ActionContext:   ctx,
+		PatientPublicId: r.URL.Query().Get("patient_id"),
+	}
+	if accountIdStr := r.URL.Query().Get("account_id"); accountIdStr != "" {
+		accountId, err := strconv.Atoi(accountIdStr)
+		if err != nil {
+			components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+			log.Errorln(err)
+			return
+		}
+		params.AccountId = uint(accountId)
+	}
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		params.From, err = time.Parse(time.DateOnly, fromStr)
+		if err != nil {
+			components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+			log.Errorln(err)
+			return
+		}
+	}
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		params.To, err = time.Parse(time.DateOnly, toStr)
+		if err != nil {
+			components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+			log.Errorln(err)
+			return
+		}
+		// include the whole last day.
+		params.To = params.To.AddDate(0, 0, 1)
+	}
+
+	payload, err := a.usecases.ExportAuditEvents(params)
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="audit-events.csv"`)
+	_, _ = w.Write([]byte(payload.Csv))
+}
@@ -11,16 +11,19 @@ import (
 	"shs/web/i18n"
 	"shs/web/views/components"
 	"strconv"
+	"strings"
 )
 
 type RequestBloodTest struct {
-	Id             uint     `json:"id"`
-	Name           string   `json:"name"`
-	DisplayInBrief string   `json:"display_in_brief"`
-	FieldNames     []string `json:"blood_test_field_name"`
-	FieldUnits     []string `json:"blood_test_field_unit"`
-	MinValues      []string `json:"blood_test_field_min_value"`
-	MaxValues      []string `json:"blood_test_field_max_value"`
+	Id              uint     `json:"id"`
+	Name            string   `json:"name"`
+	DisplayInBrief  string   `json:"display_in_brief"`
+	FieldNames      []string `json:"blood_test_field_name"`
+	FieldUnits      []string `json:"blood_test_field_unit"`
+	MinValues       []string `json:"blood_test_field_min_value"`
+	MaxValues       []string `json:"blood_test_field_max_value"`
+	FieldFormulas   []string `json:"blood_test_field_formula"`
+	FieldLoincCodes []string `json:"blood_test_field_loinc_code"`
 }
 
 type RequestBloodTestSingle struct {
@@ -31,6 +34,8 @@ type RequestBloodTestSingle struct {
 	FieldUnit      string `json:"blood_test_field_unit"`
 	MinValue       string `json:"blood_test_field_min_value"`
 	MaxValue       string `json:"blood_test_field_max_value"`
+	FieldFormula   string `json:"blood_test_field_formula"`
+	FieldLoincCode string `json:"blood_test_field_loinc_code"`
 }
 
 func clusterFuckBloodTestsToActionsOne(btSingle RequestBloodTestSingle, btMulti RequestBloodTest) actions.BloodTest {
@@ -42,6 +47,14 @@ func clusterFuckBloodTestsToActionsOne(btSingle RequestBloodTestSingle, btMulti
 		for i := range len(btMulti.FieldNames) {
 			minValue, _ := strconv.ParseFloat(btMulti.MinValues[i], 64)
 			maxValue, _ := strconv.ParseFloat(btMulti.MaxValues[i], 64)
+			var formula string
+			if i < len(btMulti.FieldFormulas) {
+				formula = strings.TrimSpace(btMulti.FieldFormulas[i])
+			}
+			var loincCode string
+			if i < len(btMulti.FieldLoincCodes) {
+				loincCode = strings.TrimSpace(btMulti.FieldLoincCodes[i])
+			}
 
 			newBloodTest.Fields = append(newBloodTest.Fields, actions.BloodTestField{
 				Name:           btMulti.FieldNames[i],
@@ -50,6 +63,8 @@ func clusterFuckBloodTestsToActionsOne(btSingle RequestBloodTestSingle, btMulti
 				MinValueNumber: minValue,
 				MaxValueString: btMulti.MaxValues[i],
 				MaxValueNumber: maxValue,
+				Formula:        formula,
+				LoincCode:      loincCode,
 			})
 		}
 
@@ -67,6 +82,8 @@ func clusterFuckBloodTestsToActionsOne(btSingle RequestBloodTestSingle, btMulti
 			MinValueNumber: minValue,
 			MaxValueString: btSingle.MaxValue,
 			MaxValueNumber: maxValue,
+			Formula:        strings.TrimSpace(btSingle.FieldFormula),
+			LoincCode:      strings.TrimSpace(btSingle.FieldLoincCode),
 		})
 
 		return newBloodTest
@@ -75,6 +92,74 @@ func clusterFuckBloodTestsToActionsOne(btSingle RequestBloodTestSingle, btMulti
 	return newBloodTest
 }
 
+type LoincCodeRequest struct {
+	FieldId   string `json:"field_id"`
+	LoincCode string `json:"loinc_code"`
+}
+
+type ReferenceRangeRequest struct {
+	FieldId      string `json:"field_id"`
+	Sex          string `json:"sex"`
+	MinAgeMonths string `json:"min_age_months"`
+	MaxAgeMonths string `json:"max_age_months"`
+	Low          string `json:"low"`
+	High         string `json:"high"`
+	CriticalLow  string `json:"critical_low"`
+	CriticalHigh string `json:"critical_high"`
+}
+
+// clusterFuckReferenceRangeToActionsOne parses the range's numbers, where the
+// empty optional ones are left as zeros.
+func clusterFuckReferenceRangeToActionsOne(rr ReferenceRangeRequest) (actions.ReferenceRange, error) {
+	parseFloat := func(s string) (float64, error) {
+		if s == "" {
+			return 0, nil
+		}
+		return strconv.ParseFloat(s, 64)
+	}
+	parseInt := func(s string) (int, error) {
+		if s == "" {
+			return 0, nil
+		}
+		return strconv.Atoi(s)
+	}
+
+	minAgeMonths, err := parseInt(rr.MinAgeMonths)
+	if err != nil {
+		return actions.ReferenceRange{}, err
+	}
+	maxAgeMonths, err := parseInt(rr.MaxAgeMonths)
+	if err != nil {
+		return actions.ReferenceRange{}, err
+	}
+	low, err := strconv.ParseFloat(rr.Low, 64)
+	if err != nil {
+		return actions.ReferenceRange{}, err
+	}
+	high, err := strconv.ParseFloat(rr.High, 64)
+	if err != nil {
+		return actions.ReferenceRange{}, err
+	}
+	criticalLow, err := parseFloat(rr.CriticalLow)
+	if err != nil {
+		return actions.ReferenceRange{}, err
+	}
+	criticalHigh, err := parseFloat(rr.CriticalHigh)
+	if err != nil {
+		return actions.ReferenceRange{}, err
+	}
+
+	return actions.ReferenceRange{
+		Sex:          rr.Sex,
+		MinAgeMonths: minAgeMonths,
+		MaxAgeMonths: maxAgeMonths,
+		Low:          low,
+		High:         high,
+		CriticalLow:  criticalLow,
+		CriticalHigh: criticalHigh,
+	}, nil
+}
+
 ///
 
 type bloodTestApi struct {
@@ -178,3 +263,113 @@ func (v *bloodTestApi) HandleToggleBloodTestDisplay(w http.ResponseWriter, r *ht
 
 	writeRawTextResponse(w, i18n.Strings("en").MessageSuccess)
 }
+
+func (v *bloodTestApi) HandleCreateBloodTestReferenceRange(w http.ResponseWriter, r *http.Request) {
+	ctx, err := context.Parse(r.Context())
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	id, _ := strconv.Atoi(r.PathValue("id"))
+
+	var reqBody ReferenceRangeRequest
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	fieldId, err := strconv.Atoi(reqBody.FieldId)
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	referenceRange, err := clusterFuckReferenceRangeToActionsOne(reqBody)
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	_, err = v.usecases.CreateBloodTestReferenceRange(actions.CreateBloodTestReferenceRangeParams{
+		ActionContext:    ctx,
+		BloodTestId:      uint(id),
+		BloodTestFieldId: uint(fieldId),
+		ReferenceRange:   referenceRange,
+	})
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	writeRawTextResponse(w, i18n.Strings("en").MessageSuccess)
+}
+
+func (v *bloodTestApi) HandleDeleteBloodTestReferenceRange(w http.ResponseWriter, r *http.Request) {
+	ctx, err := context.Parse(r.Context())
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	id, _ := strconv.Atoi(r.PathValue("id"))
+
+	_, err = v.usecases.DeleteBloodTestReferenceRange(actions.DeleteBloodTestReferenceRangeParams{
+		ActionContext:    ctx,
+		ReferenceRangeId: uint(id),
+	})
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	writeRawTextResponse(w, i18n.Strings("en").MessageSuccess)
+}
+
+func (v *bloodTestApi) HandleUpdateBloodTestFieldLoincCode(w http.ResponseWriter, r *http.Request) {
+	ctx, err := context.Parse(r.Context())
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	id, _ := strconv.Atoi(r.PathValue("id"))
+
+	var reqBody LoincCodeRequest
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	fieldId, err := strconv.Atoi(reqBody.FieldId)
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	_, err = v.usecases.UpdateBloodTestFieldLoincCode(actions.UpdateBloodTestFieldLoincCodeParams{
+		ActionContext:    ctx,
+		BloodTestId:      uint(id),
+		BloodTestFieldId: uint(fieldId),
+		LoincCode:        reqBody.LoincCode,
+	})
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	writeRawTextResponse(w, i18n.Strings("en").MessageSuccess)
+}
@@ -122,6 +122,7 @@ func (v *medicineApi) HandleUpdateMedicine(w http.ResponseWriter, r *http.Reques
 
 	var reqBody struct {
 		Amount string `json:"amount"`
+		Note   string `json:"note"`
 	}
 	err = json.NewDecoder(r.Body).Decode(&reqBody)
 	if err != nil {
@@ -141,6 +142,53 @@ func (v *medicineApi) HandleUpdateMedicine(w http.ResponseWriter, r *http.Reques
 		ActionContext: ctx,
 		MedicineId:    uint(intId),
 		Amount:        medicineAmount,
+		Note:          reqBody.Note,
+	})
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	writeRawTextResponse(w, i18n.Strings("en").MessageSuccess)
+}
+
+func (v *medicineApi) HandleCreateMedicineStockMovement(w http.ResponseWriter, r *http.Request) {
+	ctx, err := context.Parse(r.Context())
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	id := r.PathValue("id")
+	intId, _ := strconv.Atoi(id)
+
+	var reqBody struct {
+		Kind   string `json:"kind"`
+		Amount string `json:"amount"`
+		Note   string `json:"note"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	amount, err := strconv.Atoi(reqBody.Amount)
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	_, err = v.usecases.CreateMedicineStockMovement(actions.CreateMedicineStockMovementParams{
+		ActionContext: ctx,
+		MedicineId:    uint(intId),
+		Kind:          reqBody.Kind,
+		Amount:        amount,
+		Note:          reqBody.Note,
 	})
 	if err != nil {
 		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"shs/actions"
+	"shs/app/models"
 	"shs/handlers/web/context"
 	"shs/log"
 	"shs/web/i18n"
@@ -81,9 +82,10 @@ func (p *PatientBloodTests) UnmarshalJSON(payload []byte) error {
 	}
 
 	const bloodTestResultFieldValue = "blood_test_result_value#"
+	const bloodTestResultFieldUnit = "blood_test_result_unit#"
 
 	getBloodTestMeta := func(key string) (name, fieldName string, id, fieldId int) {
-		stuff := strings.Split(strings.TrimPrefix(key, bloodTestResultFieldValue), "#")
+		stuff := strings.Split(strings.TrimPrefix(strings.TrimPrefix(key, bloodTestResultFieldValue), bloodTestResultFieldUnit), "#")
 		id, _ = strconv.Atoi(stuff[0])
 		fieldId, _ = strconv.Atoi(stuff[2])
 		name = stuff[1]
@@ -92,6 +94,21 @@ func (p *PatientBloodTests) UnmarshalJSON(payload []byte) error {
 		return
 	}
 
+	// the units that the fields' values are entered in, by the fields' ids.
+	bloodTestsFieldsUnits := make(map[uint]models.BlootTestUnit)
+	for k, v := range data {
+		if !strings.HasPrefix(k, bloodTestResultFieldUnit) {
+			continue
+		}
+
+		_, _, _, fieldId := getBloodTestMeta(k)
+		unit, ok := v.(string)
+		if !ok {
+			continue
+		}
+		bloodTestsFieldsUnits[uint(fieldId)] = models.BlootTestUnit(unit)
+	}
+
 	bloodTestsFields := make(map[uint][]actions.BloodTestFilledField)
 	bloodTestNames := make(map[uint]string)
 	for k, v := range data {
@@ -111,7 +128,7 @@ func (p *PatientBloodTests) UnmarshalJSON(payload []byte) error {
 		bloodTestsFields[uint(id)] = append(bloodTestsFields[uint(id)], actions.BloodTestFilledField{
 			BloodTestFieldId: uint(fieldId),
 			Name:             fieldName,
-			Unit:             "",
+			Unit:             bloodTestsFieldsUnits[uint(fieldId)],
 			ValueNumber:      testResultInt,
 			ValueString:      testResult,
 		})
@@ -150,6 +167,8 @@ type CreateCheckUpRequest struct {
 	PatientWeight       float64
 	PatientHeight       float64
 	PrescribedMedicines []actions.Medicine
+	BleedingEpisode     *actions.BleedingEpisode
+	AppointmentId       uint
 }
 
 func (v *CreateCheckUpRequest) UnmarshalJSON(payload []byte) error {
@@ -166,6 +185,11 @@ func (v *CreateCheckUpRequest) UnmarshalJSON(payload []byte) error {
 		medicineAmountKey    = "amount"
 		patientWeightKey     = "patient_weight"
 		patientHeightKey     = "patient_height"
+		bleedingSiteKey      = "bleeding_site"
+		bleedingCauseKey     = "bleeding_cause"
+		bleedingSeverityKey  = "bleeding_severity"
+		bleedingOnsetAtKey   = "bleeding_onset_at"
+		appointmentIdKey     = "appointment_id"
 	)
 
 	var ok bool
@@ -180,6 +204,30 @@ func (v *CreateCheckUpRequest) UnmarshalJSON(payload []byte) error {
 	(*v).PatientWeight, _ = strconv.ParseFloat(weight, 64)
 	(*v).PatientHeight, _ = strconv.ParseFloat(height, 64)
 
+	if appointmentId, _ := data[appointmentIdKey].(string); appointmentId != "" {
+		appointmentIdInt, err := strconv.Atoi(appointmentId)
+		if err != nil {
+			return err
+		}
+		(*v).AppointmentId = uint(appointmentIdInt)
+	}
+
+	if bleedingSite, _ := data[bleedingSiteKey].(string); bleedingSite != "" {
+		bleedingCause, _ := data[bleedingCauseKey].(string)
+		bleedingSeverity, _ := data[bleedingSeverityKey].(string)
+		bleedingOnsetAt, _ := data[bleedingOnsetAtKey].(string)
+		be, err := clusterFuckBleedingEpisodeToActionsOne(BleedingEpisodeRequest{
+			Site:     bleedingSite,
+			Cause:    bleedingCause,
+			Severity: bleedingSeverity,
+			OnsetAt:  bleedingOnsetAt,
+		})
+		if err != nil {
+			return err
+		}
+		(*v).BleedingEpisode = &be
+	}
+
 	_, ok = data[medicineIdsKey]
 	if !ok {
 		return nil
@@ -242,57 +290,51 @@ func (v *CreateCheckUpRequest) UnmarshalJSON(payload []byte) error {
 	return nil
 }
 
-type JointsEvaluationRequest struct {
-	RightAnkle string `json:"right_ankle"`
-	LeftAnkle  string `json:"left_ankle"`
-	RightKnee  string `json:"right_knee"`
-	LeftKnee   string `json:"left_knee"`
-	RightElbow string `json:"right_elbow"`
-	LeftElbow  string `json:"left_elbow"`
-}
+// JointsEvaluationRequest maps each HJHS item's field, named as
+// <joint>_<item>, e.g. right_knee_swelling, and the global_gait field, to its
+// score.
+type JointsEvaluationRequest map[string]string
 
 func clusterFuckJointsToActionsOne(je JointsEvaluationRequest) (actions.JointsEvaluation, error) {
-	rightAnkle, err := strconv.Atoi(je.RightAnkle)
-	if err != nil {
-		return actions.JointsEvaluation{}, err
-	}
-	leftAnkle, err := strconv.Atoi(je.LeftAnkle)
-	if err != nil {
-		return actions.JointsEvaluation{}, err
-	}
-	rightKnee, err := strconv.Atoi(je.RightKnee)
-	if err != nil {
-		return actions.JointsEvaluation{}, err
-	}
-	leftKnee, err := strconv.Atoi(je.LeftKnee)
-	if err != nil {
-		return actions.JointsEvaluation{}, err
-	}
-	rightElbow, err := strconv.Atoi(je.RightElbow)
+	globalGait, err := strconv.Atoi(je["global_gait"])
 	if err != nil {
 		return actions.JointsEvaluation{}, err
 	}
-	leftElbow, err := strconv.Atoi(je.LeftElbow)
-	if err != nil {
-		return actions.JointsEvaluation{}, err
+
+	scores := make([]actions.HjhsJointScore, 0, len(models.HjhsJoints))
+	for _, joint := range models.HjhsJoints {
+		score := actions.HjhsJointScore{
+			Joint: string(joint),
+		}
+		for i, item := range score.Items() {
+			itemScore, err := strconv.Atoi(je[fmt.Sprintf("%s_%s", joint, actions.HjhsJointItems[i].Key)])
+			if err != nil {
+				return actions.JointsEvaluation{}, err
+			}
+			*item = itemScore
+		}
+		scores = append(scores, score)
 	}
 
 	return actions.JointsEvaluation{
-		RightAnkle: rightAnkle,
-		LeftAnkle:  leftAnkle,
-		RightKnee:  rightKnee,
-		LeftKnee:   leftKnee,
-		RightElbow: rightElbow,
-		LeftElbow:  leftElbow,
+		GlobalGait: globalGait,
+		Scores:     scores,
 	}, nil
 }
 
 type ProphylaxisRequest struct {
 	Title            string `json:"title"`
 	FrequencyPerDays string `json:"frequency"`
-	EndDate          string `json:"end_date"`
-	MedicineId       string `json:"medicine_id"`
-	MedicineAmount   string `json:"amount"`
+	ScheduleKind     string `json:"schedule_kind"`
+	// Weekdays is a string when a single weekday is checked, and a list of
+	// strings otherwise.
+	Weekdays       any    `json:"weekdays"`
+	IntervalDays   string `json:"interval_days"`
+	DoseIu         string `json:"dose_iu"`
+	StartDate      string `json:"start_date"`
+	EndDate        string `json:"end_date"`
+	MedicineId     string `json:"medicine_id"`
+	MedicineAmount string `json:"amount"`
 }
 
 func clusterFuckProhylaxisToActionsOne(pp ProphylaxisRequest) (actions.Prophylaxis, error) {
@@ -304,17 +346,118 @@ func clusterFuckProhylaxisToActionsOne(pp ProphylaxisRequest) (actions.Prophylax
 	if err != nil {
 		return actions.Prophylaxis{}, err
 	}
-	endDate, _ := time.Parse("2006-01-02", pp.EndDate)
+	startDate, _ := time.ParseInLocation("2006-01-02", pp.StartDate, time.Local)
+	endDate, _ := time.ParseInLocation("2006-01-02", pp.EndDate, time.Local)
+	intervalDays, _ := strconv.Atoi(pp.IntervalDays)
+	doseIu, _ := strconv.Atoi(pp.DoseIu)
+
+	var rawWeekdays []string
+	switch weekdays := pp.Weekdays.(type) {
+	case string:
+		rawWeekdays = append(rawWeekdays, weekdays)
+	case []any:
+		for _, weekday := range weekdays {
+			weekdayStr, ok := weekday.(string)
+			if !ok {
+				return actions.Prophylaxis{}, errors.New("invalid weekdays type")
+			}
+			rawWeekdays = append(rawWeekdays, weekdayStr)
+		}
+	}
+
+	weekdays := make([]time.Weekday, 0, len(rawWeekdays))
+	for _, rawWeekday := range rawWeekdays {
+		weekday, err := strconv.Atoi(rawWeekday)
+		if err != nil {
+			return actions.Prophylaxis{}, err
+		}
+		weekdays = append(weekdays, time.Weekday(weekday))
+	}
 
 	return actions.Prophylaxis{
 		Title:            pp.Title,
 		FrequencyPerDays: pp.FrequencyPerDays,
+		ScheduleKind:     pp.ScheduleKind,
+		Weekdays:         weekdays,
+		IntervalDays:     intervalDays,
+		DoseIu:           doseIu,
 		MedicineId:       uint(medicineId),
 		MedicineAmount:   medicineDose,
+		StartDate:        startDate,
 		EndDate:          endDate,
 	}, nil
 }
 
+type BleedingEpisodeRequest struct {
+	Site       string `json:"site"`
+	Cause      string `json:"cause"`
+	Severity   string `json:"severity"`
+	OnsetAt    string `json:"onset_at"`
+	Notes      string `json:"notes"`
+	MedicineId string `json:"medicine_id"`
+}
+
+func clusterFuckBleedingEpisodeToActionsOne(be BleedingEpisodeRequest) (actions.BleedingEpisode, error) {
+	onsetAt, err := time.Parse("2006-01-02T15:04", be.OnsetAt)
+	if err != nil {
+		return actions.BleedingEpisode{}, err
+	}
+
+	var prescribedMedicineId int
+	if medId, ok := strings.CutPrefix(be.MedicineId, "med-"); ok {
+		prescribedMedicineId, err = strconv.Atoi(medId)
+		if err != nil {
+			return actions.BleedingEpisode{}, err
+		}
+	}
+
+	return actions.BleedingEpisode{
+		Site:                 be.Site,
+		Cause:                be.Cause,
+		Severity:             be.Severity,
+		OnsetAt:              onsetAt.UTC(),
+		Notes:                be.Notes,
+		PrescribedMedicineId: uint(prescribedMedicineId),
+	}, nil
+}
+
+type InhibitorSurveillanceRequest struct {
+	ScreeningIntervalEds string `json:"screening_interval_eds"`
+	ItiStatus            string `json:"iti_status"`
+	ItiStartedAt         string `json:"iti_started_at"`
+	ItiEndedAt           string `json:"iti_ended_at"`
+	Notes                string `json:"notes"`
+}
+
+func clusterFuckInhibitorSurveillanceToActionsOne(is InhibitorSurveillanceRequest) (actions.InhibitorSurveillance, error) {
+	screeningIntervalEds, err := strconv.Atoi(is.ScreeningIntervalEds)
+	if err != nil {
+		return actions.InhibitorSurveillance{}, err
+	}
+
+	var itiStartedAt, itiEndedAt time.Time
+	if is.ItiStartedAt != "" {
+		itiStartedAt, err = time.Parse(time.DateOnly, is.ItiStartedAt)
+		if err != nil {
+			return actions.InhibitorSurveillance{}, err
+		}
+	}
+	if is.ItiEndedAt != "" {
+		itiEndedAt, err = time.Parse(time.DateOnly, is.ItiEndedAt)
+		if err != nil {
+			return actions.InhibitorSurveillance{}, err
+		}
+	}
+
+	return actions.InhibitorSurveillance{
+		ScreeningIntervalEds: screeningIntervalEds,
+		ItiStatus:            is.ItiStatus,
+		ItiStartedAt:         itiStartedAt,
+		ItiEndedAt:           itiEndedAt,
+		Notes:                is.Notes,
+	}, nil
+}
+
 ////
 
 type patientApi struct {
@@ -404,7 +547,7 @@ func (v *patientApi) HandleCreatePatientBloodTestResult(w http.ResponseWriter, r
 		return
 	}
 
-	_, err = v.usecases.CreatePatientBloodTestResult(actions.CreatePatientBloodTestResultParams{
+	payload, err := v.usecases.CreatePatientBloodTestResult(actions.CreatePatientBloodTestResultParams{
 		ActionContext:   ctx,
 		PatientPublicId: patientId,
 		BloodTest:       reqBody.BloodTests[0],
@@ -415,6 +558,11 @@ func (v *patientApi) HandleCreatePatientBloodTestResult(w http.ResponseWriter, r
 		return
 	}
 
+	if payload.SeverityWarning != nil {
+		writeRawTextResponse(w, i18n.Strings("en").MessageSeverityContradiction)
+		return
+	}
+
 	writeRawTextResponse(w, i18n.Strings("en").MessageSuccess)
 }
 
@@ -491,15 +639,26 @@ func (v *patientApi) HandleCreatePatientCheckUp(w http.ResponseWriter, r *http.R
 		PatientId:           patientId,
 		VisitReason:         reqBody.VisitReason,
 		VisitExtraDetails:   reqBody.VisitExtraDetails,
+		PatientWeight:       reqBody.PatientWeight,
+		PatientHeight:       reqBody.PatientHeight,
 		PrescribedMedicines: reqBody.PrescribedMedicines,
+		BleedingEpisode:     reqBody.BleedingEpisode,
+		AppointmentId:       reqBody.AppointmentId,
 	})
-	if errors.Is(err, actions.ErrInsufficientMedicine{}) {
-		imErr := err.(actions.ErrInsufficientMedicine)
+	var imErr actions.ErrInsufficientMedicine
+	if errors.As(err, &imErr) {
 		writeRawTextResponse(w, i18n.Strings("en").ErrorInsufficientMedicineAmountFmt(imErr.MedicineName, imErr.ExceedingAmount, imErr.LeftPackages))
 		components.GenericError(i18n.StringsCtx(r.Context()).ErrorInsufficientMedicineAmountFmt(imErr.MedicineName, imErr.ExceedingAmount, imErr.LeftPackages)).Render(r.Context(), w)
 		log.Errorln(err)
 		return
 	}
+	var emErr actions.ErrExpiredMedicine
+	if errors.As(err, &emErr) {
+		writeRawTextResponse(w, i18n.Strings("en").ErrorExpiredMedicineFmt(emErr.MedicineName, emErr.BatchNumber))
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorExpiredMedicineFmt(emErr.MedicineName, emErr.BatchNumber)).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
 	if err != nil {
 		writeRawTextResponse(w, i18n.Strings("en").ErrorSomethingWentWrong)
 		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
@@ -581,7 +740,7 @@ func (v *patientApi) HandleUpdatePatientPendingBloodTestResult(w http.ResponseWr
 		return
 	}
 
-	_, err = v.usecases.UpdatePatientPendingBloodTestResult(actions.UpdatePatientPendingBloodTestResultParams{
+	payload, err := v.usecases.UpdatePatientPendingBloodTestResult(actions.UpdatePatientPendingBloodTestResultParams{
 		ActionContext:     ctx,
 		PatientPublicId:   patientId,
 		BloodTestResultId: uint(btrId),
@@ -593,6 +752,11 @@ func (v *patientApi) HandleUpdatePatientPendingBloodTestResult(w http.ResponseWr
 		return
 	}
 
+	if payload.SeverityWarning != nil {
+		writeRawTextResponse(w, i18n.Strings("en").MessageSeverityContradiction)
+		return
+	}
+
 	writeRawTextResponse(w, i18n.Strings("en").MessageSuccess)
 }
 
@@ -764,6 +928,119 @@ func (v *patientApi) HandleDeletePatientProphylaxis(w http.ResponseWriter, r *ht
 	writeRawTextResponse(w, i18n.Strings("en").MessageSuccess)
 }
 
+func (v *patientApi) HandleCreatePatientBleedingEpisode(w http.ResponseWriter, r *http.Request) {
+	ctx, err := context.Parse(r.Context())
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	patientId := r.PathValue("id")
+
+	var reqBody BleedingEpisodeRequest
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	bleedingEpisode, err := clusterFuckBleedingEpisodeToActionsOne(reqBody)
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	_, err = v.usecases.CreatePatientBleedingEpisode(actions.CreatePatientBleedingEpisodeParams{
+		ActionContext:   ctx,
+		PatientId:       patientId,
+		BleedingEpisode: bleedingEpisode,
+	})
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	writeRawTextResponse(w, i18n.Strings("en").MessageSuccess)
+}
+
+func (v *patientApi) HandleDeletePatientBleedingEpisode(w http.ResponseWriter, r *http.Request) {
+	ctx, err := context.Parse(r.Context())
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	patientId := r.PathValue("id")
+	beId, err := strconv.Atoi(r.PathValue("be_id"))
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	_, err = v.usecases.DeletePatientBleedingEpisode(actions.DeletePatientBleedingEpisodeParams{
+		ActionContext:     ctx,
+		PatientId:         patientId,
+		BleedingEpisodeId: uint(beId),
+	})
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	writeRawTextResponse(w, i18n.Strings("en").MessageSuccess)
+}
+
+func (v *patientApi) HandleReportOwnBleedingEpisode(w http.ResponseWriter, r *http.Request) {
+	ctx, err := context.Parse(r.Context())
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	visitId, err := strconv.Atoi(r.PathValue("visit_id"))
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	var reqBody BleedingEpisodeRequest
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	bleedingEpisode, err := clusterFuckBleedingEpisodeToActionsOne(reqBody)
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+	bleedingEpisode.VisitId = uint(visitId)
+
+	_, err = v.usecases.ReportOwnBleedingEpisode(actions.ReportOwnBleedingEpisodeParams{
+		ActionContext:   ctx,
+		BleedingEpisode: bleedingEpisode,
+	})
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	writeRawTextResponse(w, i18n.Strings("en").MessageSuccess)
+}
+
 func validateFileType(r io.ReadSeeker, wantedTypes ...string) error {
 	reader := bufio.NewReader(r)
 
@@ -885,3 +1162,72 @@ func (v *patientApi) HandlePatientUseMedicine(w http.ResponseWriter, r *http.Req
 
 	writeRawTextResponse(w, i18n.Strings("en").MessageSuccess)
 }
+
+func (v *patientApi) HandleUpdatePatientInhibitorSurveillance(w http.ResponseWriter, r *http.Request) {
+	ctx, err := context.Parse(r.Context())
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	patientId := r.PathValue("id")
+
+	var reqBody InhibitorSurveillanceRequest
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	surveillance, err := clusterFuckInhibitorSurveillanceToActionsOne(reqBody)
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	_, err = v.usecases.UpdatePatientInhibitorSurveillance(actions.UpdatePatientInhibitorSurveillanceParams{
+		ActionContext: ctx,
+		PatientId:     patientId,
+		Surveillance:  surveillance,
+	})
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	writeRawTextResponse(w, i18n.Strings("en").MessageSuccess)
+}
+
+func (v *patientApi) HandleRestorePatientRevision(w http.ResponseWriter, r *http.Request) {
+	ctx, err := context.Parse(r.Context())
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	patientId := r.PathValue("id")
+	revisionId, err := strconv.Atoi(r.PathValue("revision_id"))
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	_, err = v.usecases.RestorePatientRevision(actions.RestorePatientRevisionParams{
+		ActionContext:   ctx,
+		PatientPublicId: patientId,
+		RevisionId:      uint(revisionId),
+	})
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	writeRawTextResponse(w, i18n.Strings("en").MessageSuccess)
+}
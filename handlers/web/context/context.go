@@ -3,6 +3,7 @@ package context
 import (
 	"context"
 	"shs/actions"
+	"shs/handlers/middlewares/clientinfo"
 	"shs/handlers/middlewares/webauth"
 	"shs/handlers/web/errors"
 	"shs/log"
@@ -20,8 +21,13 @@ func Parse(ctx context.Context) (actions.ActionContext, error) {
 		return actions.ActionContext{}, errors.ErrUnauthorized{}
 	}
 
+	clientIp, _ := ctx.Value(clientinfo.ClientIpKey).(string)
+	userAgent, _ := ctx.Value(clientinfo.UserAgentKey).(string)
+
 	return actions.ActionContext{
 		SessionToken: sessionToken,
 		Account:      account,
+		ClientIp:     clientIp,
+		UserAgent:    userAgent,
 	}, nil
 }
@@ -0,0 +1,76 @@
+package htmx
+
+import (
+	"encoding/json"
+	"net/http"
+	"shs/actions"
+	"shs/handlers/web/context"
+	"shs/log"
+	"shs/web/i18n"
+	"shs/web/views/components"
+	"strconv"
+)
+
+type doseHtmx struct {
+	usecases *actions.Actions
+}
+
+func NewDoseHtmx(usecases *actions.Actions) *doseHtmx {
+	return &doseHtmx{
+		usecases: usecases,
+	}
+}
+
+type calculateDoseRequest struct {
+	PatientWeight  string `json:"patient_weight"`
+	DoseTargetRise string `json:"dose_target_rise"`
+	DoseFactorType string `json:"dose_factor_type"`
+}
+
+// HandleCalculateDose is requested from the check-up form, and it swaps the
+// form's prescribed medicines with the calculated dose.
+func (d *doseHtmx) HandleCalculateDose(w http.ResponseWriter, r *http.Request) {
+	ctx, err := context.Parse(r.Context())
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	allMedicinePL, err := d.usecases.ListAllMedicine(actions.ListAllMedicineParams{
+		ActionContext: ctx,
+	})
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	var reqBody calculateDoseRequest
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		components.CheckUpPrescribedMedicines(allMedicinePL.Data, actions.FactorDose{}).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	// empty or malformed numbers are left as zeros, to be rejected by the action.
+	patientWeight, _ := strconv.ParseFloat(reqBody.PatientWeight, 64)
+	targetRise, _ := strconv.ParseFloat(reqBody.DoseTargetRise, 64)
+
+	payload, err := d.usecases.CalculateFactorDose(actions.CalculateFactorDoseParams{
+		ActionContext: ctx,
+		PatientWeight: patientWeight,
+		TargetRise:    targetRise,
+		FactorType:    reqBody.DoseFactorType,
+	})
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		components.CheckUpPrescribedMedicines(allMedicinePL.Data, actions.FactorDose{}).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	components.CheckUpPrescribedMedicines(allMedicinePL.Data, payload.Data).Render(r.Context(), w)
+}
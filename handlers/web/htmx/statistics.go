@@ -0,0 +1,87 @@
+package htmx
+
+import (
+	"encoding/json"
+	"net/http"
+	"shs/actions"
+	"shs/handlers/web/context"
+	"shs/log"
+	"shs/web/i18n"
+	"shs/web/views/components"
+	"time"
+)
+
+type statisticsHtmx struct {
+	usecases *actions.Actions
+}
+
+func NewStatisticsHtmx(usecases *actions.Actions) *statisticsHtmx {
+	return &statisticsHtmx{
+		usecases: usecases,
+	}
+}
+
+type findStatisticsRequest struct {
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+func (sr *findStatisticsRequest) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		StartDate string `json:"start_date"`
+		EndDate   string `json:"end_date"`
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if aux.StartDate != "" {
+		t, err := time.Parse(time.DateOnly, aux.StartDate)
+		if err != nil {
+			return err
+		}
+		sr.StartDate = t
+	}
+
+	if aux.EndDate != "" {
+		t, err := time.Parse(time.DateOnly, aux.EndDate)
+		if err != nil {
+			return err
+		}
+		// include the whole end day.
+		sr.EndDate = t.AddDate(0, 0, 1)
+	}
+
+	return nil
+}
+
+func (s *statisticsHtmx) HandleFindStatistics(w http.ResponseWriter, r *http.Request) {
+	ctx, err := context.Parse(r.Context())
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	var reqBody findStatisticsRequest
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	payload, err := s.usecases.GetStatistics(actions.GetStatisticsParams{
+		ActionContext: ctx,
+		StartDate:     reqBody.StartDate,
+		EndDate:       reqBody.EndDate,
+	})
+	if err != nil {
+		components.GenericError(i18n.StringsCtx(r.Context()).ErrorSomethingWentWrong).Render(r.Context(), w)
+		log.Errorln(err)
+		return
+	}
+
+	components.StatisticsBrief(payload.Data).Render(r.Context(), w)
+}
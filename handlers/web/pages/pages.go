@@ -3,6 +3,7 @@ package pages
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"shs/actions"
 	"shs/app/models"
 	"shs/config"
@@ -14,6 +15,7 @@ import (
 	"shs/web/views/pages"
 	"slices"
 	"strconv"
+	"time"
 
 	_ "github.com/a-h/templ"
 )
@@ -29,10 +31,30 @@ func New(usecases *actions.Actions) *pagesHandler {
 }
 
 func (p *pagesHandler) HandleHomePage(w http.ResponseWriter, r *http.Request) {
+	ctx, err := context.Parse(r.Context())
+	if err != nil {
+		components.GenericError("What do you think you're doing?").
+			Render(r.Context(), w)
+		return
+	}
+
+	var medicineAlerts []actions.MedicineAlert
+	if ctx.Account.HasPermission(models.AccountPermissionReadMedicine) {
+		medicineAlertsPL, err := p.usecases.ListMedicineAlerts(actions.ListMedicineAlertsParams{
+			ActionContext: ctx,
+		})
+		if err != nil {
+			components.GenericError("Something went wrong").
+				Render(r.Context(), w)
+			return
+		}
+		medicineAlerts = medicineAlertsPL.Data
+	}
+
 	if contenttype.IsNoLayoutPage(r) {
 		w.Header().Set("HX-Title", i18n.Strings("en").NavHome)
 		w.Header().Set("HX-Push-Url", "/")
-		pages.Index().Render(r.Context(), w)
+		pages.Index(medicineAlerts).Render(r.Context(), w)
 		return
 	}
 
@@ -40,7 +62,7 @@ func (p *pagesHandler) HandleHomePage(w http.ResponseWriter, r *http.Request) {
 		Title:    i18n.StringsCtx(r.Context()).NavHome,
 		Url:      config.Env().Hostname,
 		ImageUrl: config.Env().Hostname + "/assets/favicon-32x32.png",
-	}, pages.Index()).Render(r.Context(), w)
+	}, pages.Index(medicineAlerts)).Render(r.Context(), w)
 }
 
 func (p *pagesHandler) HandleAboutPage(w http.ResponseWriter, r *http.Request) {
@@ -185,10 +207,20 @@ func (p *pagesHandler) HandleMedicinePage(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	movements, err := p.usecases.ListMedicineStockMovements(actions.ListMedicineStockMovementsParams{
+		ActionContext: ctx,
+		MedicineId:    uint(intId),
+	})
+	if err != nil {
+		components.GenericError("Something went wrong").
+			Render(r.Context(), w)
+		return
+	}
+
 	if contenttype.IsNoLayoutPage(r) {
 		w.Header().Set("HX-Title", i18n.Strings("en").NavMedicine)
 		w.Header().Set("HX-Push-Url", "/medicine/"+id)
-		pages.Medicine(medicine.Data).Render(r.Context(), w)
+		pages.Medicine(medicine.Data, movements.Data).Render(r.Context(), w)
 		return
 	}
 
@@ -196,7 +228,7 @@ func (p *pagesHandler) HandleMedicinePage(w http.ResponseWriter, r *http.Request
 		Title:    i18n.StringsCtx(r.Context()).NavMedicine,
 		Url:      config.Env().Hostname,
 		ImageUrl: config.Env().Hostname + "/assets/favicon-32x32.png",
-	}, pages.Medicine(medicine.Data)).Render(r.Context(), w)
+	}, pages.Medicine(medicine.Data, movements.Data)).Render(r.Context(), w)
 }
 
 func (p *pagesHandler) HandleBloodTestsPage(w http.ResponseWriter, r *http.Request) {
@@ -485,10 +517,72 @@ func (p *pagesHandler) HandlePatientPage(w http.ResponseWriter, r *http.Request)
 		diagnoses = diagnosesPL.Data
 	}
 
+	bleedingRates, err := p.usecases.GetPatientBleedingRates(actions.GetPatientBleedingRatesParams{
+		ActionContext: ctx,
+		PatientId:     patient.Data.PublicId,
+	})
+	if err != nil {
+		components.GenericError("Something went wrong").
+			Render(r.Context(), w)
+		return
+	}
+
+	var appointments []actions.Appointment
+	if ctx.Account.HasPermission(models.AccountPermissionWriteOtherVisits) {
+		appointmentsPL, err := p.usecases.ListPatientAppointments(actions.ListPatientAppointmentsParams{
+			ActionContext: ctx,
+			PatientId:     patient.Data.PublicId,
+		})
+		if err != nil {
+			components.GenericError("Something went wrong").
+				Render(r.Context(), w)
+			return
+		}
+		appointments = appointmentsPL.Data
+	}
+
+	var prophylaxisAdherence actions.PatientProphylaxisAdherence
+	if ctx.Account.HasPermission(models.AccountPermissionReadProphylaxes) {
+		prophylaxisAdherencePL, err := p.usecases.GetPatientProphylaxisAdherence(actions.GetPatientProphylaxisAdherenceParams{
+			ActionContext: ctx,
+			PatientId:     patient.Data.PublicId,
+		})
+		if err != nil {
+			components.GenericError("Something went wrong").
+				Render(r.Context(), w)
+			return
+		}
+		prophylaxisAdherence = prophylaxisAdherencePL.Data
+	}
+
+	var inhibitorSurveillance actions.InhibitorSurveillance
+	if ctx.Account.HasPermission(models.AccountPermissionReadBloodTest) {
+		inhibitorSurveillancePL, err := p.usecases.GetPatientInhibitorSurveillance(actions.GetPatientInhibitorSurveillanceParams{
+			ActionContext: ctx,
+			PatientId:     patient.Data.PublicId,
+		})
+		if err != nil {
+			components.GenericError("Something went wrong").
+				Render(r.Context(), w)
+			return
+		}
+		inhibitorSurveillance = inhibitorSurveillancePL.Data
+	}
+
+	revisions, err := p.usecases.ListPatientRevisions(actions.ListPatientRevisionsParams{
+		ActionContext:   ctx,
+		PatientPublicId: patient.Data.PublicId,
+	})
+	if err != nil {
+		components.GenericError("Something went wrong").
+			Render(r.Context(), w)
+		return
+	}
+
 	if contenttype.IsNoLayoutPage(r) {
 		w.Header().Set("HX-Title", i18n.Strings("en").NavPatient)
 		w.Header().Set("HX-Push-Url", "/patient/"+id)
-		pages.Patient(patient.Data, bloodTests, viruses, allMedicine, visits, diagnoses).Render(r.Context(), w)
+		pages.Patient(patient.Data, bloodTests, viruses, allMedicine, visits, diagnoses, bleedingRates.Data, prophylaxisAdherence, appointments, inhibitorSurveillance, revisions.Data).Render(r.Context(), w)
 		return
 	}
 
@@ -496,7 +590,7 @@ func (p *pagesHandler) HandlePatientPage(w http.ResponseWriter, r *http.Request)
 		Title:    i18n.StringsCtx(r.Context()).NavPatient,
 		Url:      config.Env().Hostname,
 		ImageUrl: config.Env().Hostname + "/assets/favicon-32x32.png",
-	}, pages.Patient(patient.Data, bloodTests, viruses, allMedicine, visits, diagnoses)).Render(r.Context(), w)
+	}, pages.Patient(patient.Data, bloodTests, viruses, allMedicine, visits, diagnoses, bleedingRates.Data, prophylaxisAdherence, appointments, inhibitorSurveillance, revisions.Data)).Render(r.Context(), w)
 }
 
 func (p *pagesHandler) HandlePatientBloodTestResultPage(w http.ResponseWriter, r *http.Request) {
@@ -637,10 +731,20 @@ func (p *pagesHandler) HandlePatientMedicationsPage(w http.ResponseWriter, r *ht
 		return
 	}
 
+	calendarFeedToken, err := p.usecases.GetOwnCalendarFeedToken(actions.GetOwnCalendarFeedTokenParams{
+		ActionContext: ctx,
+	})
+	if err != nil {
+		components.GenericError("Something went wrong").
+			Render(r.Context(), w)
+		return
+	}
+	calendarFeedUrl := config.Env().Hostname + "/api/json/me/patient/calendar.ics?token=" + url.QueryEscape(calendarFeedToken.Token)
+
 	if contenttype.IsNoLayoutPage(r) {
 		w.Header().Set("HX-Title", i18n.Strings("en").NavPatient)
 		w.Header().Set("HX-Push-Url", "/patient/medications")
-		pages.PatientMedicine(payload).Render(r.Context(), w)
+		pages.PatientMedicine(payload, calendarFeedUrl).Render(r.Context(), w)
 		return
 	}
 
@@ -648,7 +752,7 @@ func (p *pagesHandler) HandlePatientMedicationsPage(w http.ResponseWriter, r *ht
 		Title:    i18n.StringsCtx(r.Context()).NavPatient,
 		Url:      config.Env().Hostname,
 		ImageUrl: config.Env().Hostname + "/assets/favicon-32x32.png",
-	}, pages.PatientMedicine(payload)).Render(r.Context(), w)
+	}, pages.PatientMedicine(payload, calendarFeedUrl)).Render(r.Context(), w)
 }
 
 func (p *pagesHandler) HandleDiagnosesPage(w http.ResponseWriter, r *http.Request) {
@@ -690,20 +794,25 @@ func (p *pagesHandler) HandleStatisticsPage(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	_ = ctx
+	stats, err := p.usecases.GetStatistics(actions.GetStatisticsParams{ActionContext: ctx})
+	if err != nil {
+		components.GenericError("Something went wrong").
+			Render(r.Context(), w)
+		return
+	}
 
 	if contenttype.IsNoLayoutPage(r) {
-		w.Header().Set("HX-Title", i18n.Strings("en").NavPatient)
+		w.Header().Set("HX-Title", i18n.Strings("en").NavStatistics)
 		w.Header().Set("HX-Push-Url", "/statistics")
-		pages.Statistics().Render(r.Context(), w)
+		pages.Statistics(stats.Data).Render(r.Context(), w)
 		return
 	}
 
 	layouts.Default(layouts.PageProps{
-		Title:    i18n.StringsCtx(r.Context()).NavPatient,
+		Title:    i18n.StringsCtx(r.Context()).NavStatistics,
 		Url:      config.Env().Hostname,
 		ImageUrl: config.Env().Hostname + "/assets/favicon-32x32.png",
-	}, pages.Statistics()).Render(r.Context(), w)
+	}, pages.Statistics(stats.Data)).Render(r.Context(), w)
 }
 
 func (p *pagesHandler) HandleMedicinesUseLogsPage(w http.ResponseWriter, r *http.Request) {
@@ -770,3 +879,247 @@ func (p *pagesHandler) HandleVisitsPage(w http.ResponseWriter, r *http.Request)
 		ImageUrl: config.Env().Hostname + "/assets/favicon-32x32.png",
 	}, pages.Visits(visits.Data, treatmentDetails.Data)).Render(r.Context(), w)
 }
+
+func (p *pagesHandler) HandleAgendaPage(w http.ResponseWriter, r *http.Request) {
+	ctx, err := context.Parse(r.Context())
+	if err != nil {
+		components.GenericError("What do you think you're doing?").
+			Render(r.Context(), w)
+		return
+	}
+
+	var date time.Time
+	if dateStr := r.URL.Query().Get("date"); dateStr != "" {
+		date, err = time.ParseInLocation(time.DateOnly, dateStr, time.Local)
+		if err != nil {
+			components.GenericError("What do you think you're doing?").
+				Render(r.Context(), w)
+			return
+		}
+	}
+
+	agenda, err := p.usecases.ListAppointmentsAgenda(actions.ListAppointmentsAgendaParams{
+		ActionContext: ctx,
+		Date:          date,
+	})
+	if err != nil {
+		components.GenericError("What do you think you're doing?").
+			Render(r.Context(), w)
+		return
+	}
+
+	var assignees []actions.Account
+	if ctx.Account.HasPermission(models.AccountPermissionWriteOtherVisits) {
+		assigneesPL, err := p.usecases.ListAppointmentAssignees(actions.ListAppointmentAssigneesParams{
+			ActionContext: ctx,
+		})
+		if err != nil {
+			components.GenericError("Something went wrong").
+				Render(r.Context(), w)
+			return
+		}
+		assignees = assigneesPL.Data
+	}
+
+	if contenttype.IsNoLayoutPage(r) {
+		w.Header().Set("HX-Title", i18n.Strings("en").NavAgenda)
+		w.Header().Set("HX-Push-Url", "/visits/agenda?date="+agenda.Date.Format(time.DateOnly))
+		pages.Agenda(agenda, assignees).Render(r.Context(), w)
+		return
+	}
+
+	layouts.Default(layouts.PageProps{
+		Title:    i18n.StringsCtx(r.Context()).NavAgenda,
+		Url:      config.Env().Hostname,
+		ImageUrl: config.Env().Hostname + "/assets/favicon-32x32.png",
+	}, pages.Agenda(agenda, assignees)).Render(r.Context(), w)
+}
+
+func (p *pagesHandler) HandleTargetJointsPage(w http.ResponseWriter, r *http.Request) {
+	ctx, err := context.Parse(r.Context())
+	if err != nil {
+		components.GenericError("What do you think you're doing?").
+			Render(r.Context(), w)
+		return
+	}
+
+	targetJoints, err := p.usecases.ListPatientsWithTargetJoints(actions.ListPatientsWithTargetJointsParams{
+		ActionContext: ctx,
+	})
+	if err != nil {
+		components.GenericError("Something went wrong").
+			Render(r.Context(), w)
+		return
+	}
+
+	if contenttype.IsNoLayoutPage(r) {
+		w.Header().Set("HX-Title", i18n.Strings("en").NavTargetJoints)
+		w.Header().Set("HX-Push-Url", "/patients/target-joints")
+		pages.TargetJoints(targetJoints.Data).Render(r.Context(), w)
+		return
+	}
+
+	layouts.Default(layouts.PageProps{
+		Title:    i18n.StringsCtx(r.Context()).NavTargetJoints,
+		Url:      config.Env().Hostname,
+		ImageUrl: config.Env().Hostname + "/assets/favicon-32x32.png",
+	}, pages.TargetJoints(targetJoints.Data)).Render(r.Context(), w)
+}
+
+func (p *pagesHandler) HandleInhibitorScreeningsDuePage(w http.ResponseWriter, r *http.Request) {
+	ctx, err := context.Parse(r.Context())
+	if err != nil {
+		components.GenericError("What do you think you're doing?").
+			Render(r.Context(), w)
+		return
+	}
+
+	screeningsDue, err := p.usecases.ListInhibitorScreeningsDue(actions.ListInhibitorScreeningsDueParams{
+		ActionContext: ctx,
+	})
+	if err != nil {
+		components.GenericError("Something went wrong").
+			Render(r.Context(), w)
+		return
+	}
+
+	if contenttype.IsNoLayoutPage(r) {
+		w.Header().Set("HX-Title", i18n.Strings("en").NavInhibitorScreeningsDue)
+		w.Header().Set("HX-Push-Url", "/patients/inhibitor-screenings-due")
+		pages.InhibitorScreeningsDue(screeningsDue.Data).Render(r.Context(), w)
+		return
+	}
+
+	layouts.Default(layouts.PageProps{
+		Title:    i18n.StringsCtx(r.Context()).NavInhibitorScreeningsDue,
+		Url:      config.Env().Hostname,
+		ImageUrl: config.Env().Hostname + "/assets/favicon-32x32.png",
+	}, pages.InhibitorScreeningsDue(screeningsDue.Data)).Render(r.Context(), w)
+}
+
+func (p *pagesHandler) HandleOutOfRangeBloodTestResultsPage(w http.ResponseWriter, r *http.Request) {
+	ctx, err := context.Parse(r.Context())
+	if err != nil {
+		components.GenericError("What do you think you're doing?").
+			Render(r.Context(), w)
+		return
+	}
+
+	var days int
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		days, err = strconv.Atoi(daysStr)
+		if err != nil {
+			components.GenericError("What do you think you're doing?").
+				Render(r.Context(), w)
+			return
+		}
+	}
+	if days == 0 {
+		days = 30
+	}
+
+	results, err := p.usecases.ListOutOfRangeBloodTestResults(actions.ListOutOfRangeBloodTestResultsParams{
+		ActionContext: ctx,
+		Days:          days,
+	})
+	if err != nil {
+		components.GenericError("Something went wrong").
+			Render(r.Context(), w)
+		return
+	}
+
+	if contenttype.IsNoLayoutPage(r) {
+		w.Header().Set("HX-Title", i18n.Strings("en").OutOfRangeResults)
+		w.Header().Set("HX-Push-Url", "/blood-tests/out-of-range?days="+strconv.Itoa(days))
+		pages.OutOfRangeBloodTestResults(results.Data, days).Render(r.Context(), w)
+		return
+	}
+
+	layouts.Default(layouts.PageProps{
+		Title:    i18n.StringsCtx(r.Context()).OutOfRangeResults,
+		Url:      config.Env().Hostname,
+		ImageUrl: config.Env().Hostname + "/assets/favicon-32x32.png",
+	}, pages.OutOfRangeBloodTestResults(results.Data, days)).Render(r.Context(), w)
+}
+
+func (p *pagesHandler) HandleAuditEventsPage(w http.ResponseWriter, r *http.Request) {
+	ctx, err := context.Parse(r.Context())
+	if err != nil {
+		components.GenericError("What do you think you're doing?").
+			Render(r.Context(), w)
+		return
+	}
+
+	filter := pages.AuditEventsFilter{
+		PatientPublicId: r.URL.Query().Get("patient_id"),
+	}
+	if accountIdStr := r.URL.Query().Get("account_id"); accountIdStr != "" {
+		accountId, err := strconv.Atoi(accountIdStr)
+		if err != nil {
+			components.GenericError("What do you think you're doing?").
+				Render(r.Context(), w)
+			return
+		}
+		filter.AccountId = uint(accountId)
+	}
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		filter.From, err = time.Parse(time.DateOnly, fromStr)
+		if err != nil {
+			components.GenericError("What do you think you're doing?").
+				Render(r.Context(), w)
+			return
+		}
+	}
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		filter.To, err = time.Parse(time.DateOnly, toStr)
+		if err != nil {
+			components.GenericError("What do you think you're doing?").
+				Render(r.Context(), w)
+			return
+		}
+	}
+
+	params := actions.ListAuditEventsParams{
+		ActionContext:   ctx,
+		AccountId:       filter.AccountId,
+		PatientPublicId: filter.PatientPublicId,
+		From:            filter.From,
+	}
+	if !filter.To.IsZero() {
+		// include the whole last day.
+		params.To = filter.To.AddDate(0, 0, 1)
+	}
+
+	events, err := p.usecases.ListAuditEvents(params)
+	if err != nil {
+		components.GenericError("Something went wrong").
+			Render(r.Context(), w)
+		return
+	}
+
+	var accounts []actions.Account
+	if ctx.Account.HasPermission(models.AccountPermissionReadAccounts) {
+		accountsPL, err := p.usecases.ListAllAccounts(actions.ListAllAccountsParams{
+			ActionContext: ctx,
+		})
+		if err != nil {
+			components.GenericError("Something went wrong").
+				Render(r.Context(), w)
+			return
+		}
+		accounts = accountsPL.Data
+	}
+
+	if contenttype.IsNoLayoutPage(r) {
+		w.Header().Set("HX-Title", i18n.Strings("en").AuditEvents)
+		w.Header().Set("HX-Push-Url", "/management/audit-events?"+filter.Query())
+		pages.AuditEvents(events.Data, accounts, filter).Render(r.Context(), w)
+		return
+	}
+
+	layouts.Default(layouts.PageProps{
+		Title:    i18n.StringsCtx(r.Context()).AuditEvents,
+		Url:      config.Env().Hostname,
+		ImageUrl: config.Env().Hostname + "/assets/favicon-32x32.png",
+	}, pages.AuditEvents(events.Data, accounts, filter)).Render(r.Context(), w)
+}
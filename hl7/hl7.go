@@ -0,0 +1,247 @@
+// Package hl7 parses HL7 v2 messages, and reads the lab results out of the
+// ORU^R01 ones, i.e. the PID, OBR and OBX segments.
+package hl7
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNoMessage      = errors.New("hl7: no MSH segment found")
+	ErrInvalidHeader  = errors.New("hl7: invalid MSH segment")
+	ErrNotOruR01      = errors.New("hl7: message is not an ORU^R01")
+	ErrMissingPatient = errors.New("hl7: message has no PID segment")
+)
+
+// delimiters are the message's separators, as declared in MSH-1 and MSH-2.
+type delimiters struct {
+	field        byte
+	component    byte
+	repetition   byte
+	escape       byte
+	subcomponent byte
+}
+
+// Segment is a segment's fields, where the first one is the segment's name,
+// so that a field's index is its HL7 sequence number, e.g. OBX-5 is Fields[5].
+type Segment struct {
+	Fields []string
+}
+
+func (s Segment) Name() string {
+	return s.Fields[0]
+}
+
+// Field returns the field's raw value, or an empty string when the segment
+// doesn't have it.
+func (s Segment) Field(sequence int) string {
+	if sequence < 0 || sequence >= len(s.Fields) {
+		return ""
+	}
+	return s.Fields[sequence]
+}
+
+type Message struct {
+	Segments []Segment
+	delims   delimiters
+}
+
+// ParseMessages parses the messages of a file, where a file can have many
+// messages, each starting with its MSH segment, and segments can be separated
+// by any of CR, LF or CRLF.
+func ParseMessages(data []byte) ([]Message, error) {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\r"))
+	data = bytes.ReplaceAll(data, []byte("\n"), []byte("\r"))
+
+	messages := make([]Message, 0)
+	for _, line := range bytes.Split(data, []byte("\r")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		if bytes.HasPrefix(line, []byte("MSH")) {
+			msg, err := parseHeader(string(line))
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, msg)
+			continue
+		}
+		if len(messages) == 0 {
+			// batch and file headers, i.e. FHS and BHS, are skipped.
+			continue
+		}
+
+		msg := &messages[len(messages)-1]
+		msg.Segments = append(msg.Segments, Segment{
+			Fields: strings.Split(string(line), string(msg.delims.field)),
+		})
+	}
+	if len(messages) == 0 {
+		return nil, ErrNoMessage
+	}
+
+	return messages, nil
+}
+
+func parseHeader(line string) (Message, error) {
+	// MSH|^~\&|...
+	if len(line) < 8 {
+		return Message{}, ErrInvalidHeader
+	}
+	delims := delimiters{
+		field:        line[3],
+		component:    line[4],
+		repetition:   line[5],
+		escape:       line[6],
+		subcomponent: line[7],
+	}
+
+	// MSH-1 is the field separator itself, so the fields are shifted by one
+	// to keep the sequence numbers right.
+	fields := strings.Split(line, string(delims.field))
+	header := Segment{
+		Fields: append([]string{"MSH", string(delims.field)}, fields[1:]...),
+	}
+
+	return Message{
+		Segments: []Segment{header},
+		delims:   delims,
+	}, nil
+}
+
+func (m Message) header() Segment {
+	return m.Segments[0]
+}
+
+// Type returns the message's type and trigger event, e.g. ORU^R01.
+func (m Message) Type() string {
+	components := m.Components(m.header().Field(9))
+	if len(components) < 2 {
+		return m.header().Field(9)
+	}
+	return components[0] + "^" + components[1]
+}
+
+// ControlId is the message's MSH-10, which identifies it for its sender.
+func (m Message) ControlId() string {
+	return m.Unescape(m.header().Field(10))
+}
+
+// SendingFacility is the message's MSH-4 first component.
+func (m Message) SendingFacility() string {
+	return m.Unescape(m.first(m.header().Field(4)))
+}
+
+// Repetitions splits a field into its repetitions.
+func (m Message) Repetitions(field string) []string {
+	return strings.Split(field, string(m.delims.repetition))
+}
+
+// Components splits a field into its components, unescaped.
+func (m Message) Components(field string) []string {
+	components := strings.Split(field, string(m.delims.component))
+	for i := range components {
+		components[i] = m.Unescape(components[i])
+	}
+	return components
+}
+
+func (m Message) first(field string) string {
+	before, _, _ := strings.Cut(field, string(m.delims.component))
+	return before
+}
+
+// Unescape replaces the delimiters' escape sequences, e.g. \F\, with the
+// delimiters, and drops the formatting ones.
+func (m Message) Unescape(value string) string {
+	esc := string(m.delims.escape)
+	if !strings.Contains(value, esc) {
+		return value
+	}
+
+	out := new(strings.Builder)
+	for {
+		start := strings.Index(value, esc)
+		if start == -1 {
+			out.WriteString(value)
+			break
+		}
+		end := strings.Index(value[start+1:], esc)
+		if end == -1 {
+			out.WriteString(value)
+			break
+		}
+
+		out.WriteString(value[:start])
+		switch sequence := value[start+1 : start+1+end]; sequence {
+		case "F":
+			out.WriteByte(m.delims.field)
+		case "S":
+			out.WriteByte(m.delims.component)
+		case "R":
+			out.WriteByte(m.delims.repetition)
+		case "E":
+			out.WriteByte(m.delims.escape)
+		case "T":
+			out.WriteByte(m.delims.subcomponent)
+		case ".br":
+			out.WriteByte('\n')
+		}
+		value = value[start+1+end+1:]
+	}
+
+	return out.String()
+}
+
+// ParseTime parses an HL7 DTM, i.e. YYYY[MM[DD[HH[MM[SS[.S[S[S[S]]]]]]]]][+/-ZZZZ],
+// where a time without an offset is taken in the clinic's location, which is
+// the server's local time, since that's the time of the labs that send them.
+func ParseTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	offset := ""
+	if i := strings.IndexAny(value, "+-"); i != -1 {
+		value, offset = value[:i], value[i:]
+	}
+	fraction := ""
+	if i := strings.IndexByte(value, '.'); i != -1 {
+		value, fraction = value[:i], value[i:]
+	}
+
+	layouts := map[int]string{
+		4:  "2006",
+		6:  "200601",
+		8:  "20060102",
+		10: "2006010215",
+		12: "200601021504",
+		14: "20060102150405",
+	}
+	layout, ok := layouts[len(value)]
+	if !ok {
+		return time.Time{}, fmt.Errorf("hl7: invalid time %q", value)
+	}
+	if fraction != "" && len(value) == 14 {
+		layout += "." + strings.Repeat("0", len(fraction)-1)
+		value += fraction
+	}
+	if offset != "" {
+		layout += "-0700"
+		value += offset
+	}
+
+	t, err := time.ParseInLocation(layout, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("hl7: invalid time %q: %w", value, err)
+	}
+
+	return t.UTC(), nil
+}
@@ -0,0 +1,186 @@
+package hl7
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseMessagesDelimiters(t *testing.T) {
+	tests := []struct {
+		name           string
+		data           string
+		wantMessages   int
+		wantType       string
+		wantControlId  string
+		wantFacility   string
+		wantComponents []string
+	}{
+		{
+			name:           "standard delimiters",
+			data:           "MSH|^~\\&|LIS|LAB^1.2.3^ISO|SHS|CLINIC|20240105083000||ORU^R01^ORU_R01|MSG0001|P|2.5\rPID|1||123^^^HOSP^PI||Doe^John",
+			wantMessages:   1,
+			wantType:       "ORU^R01",
+			wantControlId:  "MSG0001",
+			wantFacility:   "LAB",
+			wantComponents: []string{"Doe", "John"},
+		},
+		{
+			name:           "custom delimiters",
+			data:           "MSH#$*!@#LIS#LAB$1.2.3#SHS#CLINIC#20240105083000##ORU$R01#MSG0002#P#2.5\rPID#1##123$$$HOSP$PI##Doe$John",
+			wantMessages:   1,
+			wantType:       "ORU^R01",
+			wantControlId:  "MSG0002",
+			wantFacility:   "LAB",
+			wantComponents: []string{"Doe", "John"},
+		},
+		{
+			name:           "many messages separated by LF and CRLF with a batch header",
+			data:           "BHS|^~\\&|LIS\nMSH|^~\\&|LIS|LAB|||||ORU^R01|MSG0003|P|2.5\r\nPID|1||1||Doe^Jane\nMSH|^~\\&|LIS|LAB|||||ORU^R01|MSG0004|P|2.5\nPID|1||2||Roe^Jane\n",
+			wantMessages:   2,
+			wantType:       "ORU^R01",
+			wantControlId:  "MSG0003",
+			wantFacility:   "LAB",
+			wantComponents: []string{"Doe", "Jane"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages, err := ParseMessages([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("ParseMessages() error = %v", err)
+			}
+			if len(messages) != tt.wantMessages {
+				t.Fatalf("ParseMessages() got %d messages, want %d", len(messages), tt.wantMessages)
+			}
+
+			m := messages[0]
+			if got := m.Type(); got != tt.wantType {
+				t.Errorf("Type() = %q, want %q", got, tt.wantType)
+			}
+			if got := m.ControlId(); got != tt.wantControlId {
+				t.Errorf("ControlId() = %q, want %q", got, tt.wantControlId)
+			}
+			if got := m.SendingFacility(); got != tt.wantFacility {
+				t.Errorf("SendingFacility() = %q, want %q", got, tt.wantFacility)
+			}
+			if len(m.Segments) != 2 || m.Segments[1].Name() != "PID" {
+				t.Fatalf("Segments = %v, want MSH and PID", m.Segments)
+			}
+			if got := m.Components(m.Segments[1].Field(5)); !slices.Equal(got, tt.wantComponents) {
+				t.Errorf("Components(PID-5) = %q, want %q", got, tt.wantComponents)
+			}
+		})
+	}
+}
+
+func TestParseMessagesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{name: "empty", data: "", wantErr: ErrNoMessage},
+		{name: "no header", data: "PID|1||123\rOBX|1|NM|3209-4^FVIII^LN||45|%", wantErr: ErrNoMessage},
+		{name: "short header", data: "MSH|^~", wantErr: ErrInvalidHeader},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMessages([]byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseMessages() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnescape(t *testing.T) {
+	standard, err := ParseMessages([]byte("MSH|^~\\&|LIS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	custom, err := ParseMessages([]byte("MSH#$*!@#LIS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		message Message
+		value   string
+		want    string
+	}{
+		{name: "no escapes", message: standard[0], value: "Factor VIII", want: "Factor VIII"},
+		{name: "field", message: standard[0], value: `A\F\B`, want: "A|B"},
+		{name: "component", message: standard[0], value: `A\S\B`, want: "A^B"},
+		{name: "repetition", message: standard[0], value: `A\R\B`, want: "A~B"},
+		{name: "escape", message: standard[0], value: `A\E\B`, want: `A\B`},
+		{name: "subcomponent", message: standard[0], value: `A\T\B`, want: "A&B"},
+		{name: "line break", message: standard[0], value: `A\.br\B`, want: "A\nB"},
+		{name: "formatting is dropped", message: standard[0], value: `\H\High\N\`, want: "High"},
+		{name: "many escapes", message: standard[0], value: `\F\\S\\T\`, want: "|^&"},
+		{name: "unterminated escape is kept", message: standard[0], value: `A\F`, want: `A\F`},
+		{name: "custom delimiters", message: custom[0], value: "A!F!B!S!C!E!D", want: "A#B$C!D"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.message.Unescape(tt.value); got != tt.want {
+				t.Errorf("Unescape(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	// a time without an offset is in the clinic's location, i.e. time.Local.
+	local := time.Local
+	time.Local = time.FixedZone("UTC+3", 3*60*60)
+	t.Cleanup(func() {
+		time.Local = local
+	})
+
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "empty", value: "", want: time.Time{}},
+		{name: "blank", value: "  ", want: time.Time{}},
+		{name: "year", value: "2024", want: time.Date(2023, 12, 31, 21, 0, 0, 0, time.UTC)},
+		{name: "month", value: "202403", want: time.Date(2024, 2, 29, 21, 0, 0, 0, time.UTC)},
+		{name: "day", value: "20240305", want: time.Date(2024, 3, 4, 21, 0, 0, 0, time.UTC)},
+		{name: "hour", value: "2024030508", want: time.Date(2024, 3, 5, 5, 0, 0, 0, time.UTC)},
+		{name: "minute", value: "202403050830", want: time.Date(2024, 3, 5, 5, 30, 0, 0, time.UTC)},
+		{name: "second", value: "20240305083015", want: time.Date(2024, 3, 5, 5, 30, 15, 0, time.UTC)},
+		{name: "fraction", value: "20240305083015.25", want: time.Date(2024, 3, 5, 5, 30, 15, 250_000_000, time.UTC)},
+		{name: "four digits fraction", value: "20240305083015.1234", want: time.Date(2024, 3, 5, 5, 30, 15, 123_400_000, time.UTC)},
+		{name: "utc offset", value: "20240305083015+0000", want: time.Date(2024, 3, 5, 8, 30, 15, 0, time.UTC)},
+		{name: "positive offset", value: "20240305083015+0200", want: time.Date(2024, 3, 5, 6, 30, 15, 0, time.UTC)},
+		{name: "negative offset", value: "202403050830-0500", want: time.Date(2024, 3, 5, 13, 30, 0, 0, time.UTC)},
+		{name: "fraction and offset", value: "20240305083015.5-0130", want: time.Date(2024, 3, 5, 10, 0, 15, 500_000_000, time.UTC)},
+		{name: "invalid length", value: "2024030", wantErr: true},
+		{name: "invalid month", value: "20241305", wantErr: true},
+		{name: "not a number", value: "2024-03-05", wantErr: true},
+		{name: "invalid offset", value: "20240305083015+02", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTime(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTime(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseTime(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+			if !got.IsZero() && got.Location() != time.UTC {
+				t.Errorf("ParseTime(%q) location = %v, want UTC", tt.value, got.Location())
+			}
+		})
+	}
+}
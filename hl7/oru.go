@@ -0,0 +1,233 @@
+package hl7
+
+import (
+	"strings"
+	"time"
+)
+
+const MessageTypeOruR01 = "ORU^R01"
+
+// CodingSystemLoinc is LOINC's coding system id in a coded element.
+const CodingSystemLoinc = "LN"
+
+// Result statuses of an OBX-11, where a result that was posted for the wrong
+// patient, or deleted, is withdrawn by its sender.
+const (
+	ResultStatusFinal        = "F"
+	ResultStatusPreliminary  = "P"
+	ResultStatusWrongPatient = "W"
+	ResultStatusDeleted      = "D"
+)
+
+// Coded is a coded element, e.g. an OBX-3 observation identifier, where the
+// alternate code is the one from another coding system, e.g. a local code
+// alongside a LOINC code.
+type Coded struct {
+	Code            string
+	Text            string
+	System          string
+	AlternateCode   string
+	AlternateText   string
+	AlternateSystem string
+}
+
+func (m Message) coded(field string) Coded {
+	components := m.Components(field)
+	component := func(i int) string {
+		if i < len(components) {
+			return strings.TrimSpace(components[i])
+		}
+		return ""
+	}
+
+	return Coded{
+		Code:            component(0),
+		Text:            component(1),
+		System:          component(2),
+		AlternateCode:   component(3),
+		AlternateText:   component(4),
+		AlternateSystem: component(5),
+	}
+}
+
+// Observation is an OBX segment.
+type Observation struct {
+	SetId      string
+	ValueType  string
+	Identifier Coded
+	Value      string
+	Units      string
+	// ReferenceRange and AbnormalFlags are as the lab reported them.
+	ReferenceRange string
+	AbnormalFlags  string
+	// ResultStatus is OBX-11, e.g. F for final and P for preliminary.
+	ResultStatus string
+	ObservedAt   time.Time
+}
+
+// Order is an OBR segment with its observations.
+type Order struct {
+	PlacerOrderNumber string
+	FillerOrderNumber string
+	Service           Coded
+	ObservedAt        time.Time
+	Observations      []Observation
+}
+
+// Identifier types of a CX identifier's CX-5, where the patient's ids are
+// either the clinic's, or the national ones.
+const (
+	IdentifierTypePatientInternal = "PI"
+	IdentifierTypePatientExternal = "PT"
+	IdentifierTypeNational        = "NI"
+)
+
+// Identifier is a CX identifier, e.g. a PID-3 repetition, where the assigning
+// authority is CX-4's namespace id, and the type is CX-5's code, which are
+// empty when the sender doesn't set them.
+type Identifier struct {
+	Id                 string
+	AssigningAuthority string
+	TypeCode           string
+}
+
+// Patient is a PID segment, where the identifiers are PID-3's repetitions,
+// followed by PID-2's, which older senders use.
+type Patient struct {
+	Identifiers []Identifier
+	LastName    string
+	FirstName   string
+	DateOfBirth time.Time
+	Sex         string
+}
+
+// OruR01 is an unsolicited observation result message, i.e. a lab's results
+// for a patient.
+type OruR01 struct {
+	ControlId       string
+	SendingFacility string
+	Patient         Patient
+	Orders          []Order
+}
+
+// ParseOruR01 reads the lab results of an ORU^R01 message, where a time that
+// can't be parsed is left as zero, and an observation that comes before any
+// OBR is put in an order of its own.
+func ParseOruR01(m Message) (OruR01, error) {
+	if m.Type() != MessageTypeOruR01 {
+		return OruR01{}, ErrNotOruR01
+	}
+
+	oru := OruR01{
+		ControlId:       m.ControlId(),
+		SendingFacility: m.SendingFacility(),
+		Orders:          make([]Order, 0),
+	}
+	hasPatient := false
+
+	for _, segment := range m.Segments[1:] {
+		switch segment.Name() {
+		case "PID":
+			if hasPatient {
+				// a message is for a single patient, where the rest are ignored.
+				continue
+			}
+			hasPatient = true
+			oru.Patient = m.patient(segment)
+		case "OBR":
+			observedAt, _ := ParseTime(segment.Field(7))
+			oru.Orders = append(oru.Orders, Order{
+				PlacerOrderNumber: m.Unescape(m.first(segment.Field(2))),
+				FillerOrderNumber: m.Unescape(m.first(segment.Field(3))),
+				Service:           m.coded(segment.Field(4)),
+				ObservedAt:        observedAt,
+				Observations:      make([]Observation, 0),
+			})
+		case "OBX":
+			if len(oru.Orders) == 0 {
+				oru.Orders = append(oru.Orders, Order{
+					Observations: make([]Observation, 0),
+				})
+			}
+			order := &oru.Orders[len(oru.Orders)-1]
+			observedAt, _ := ParseTime(segment.Field(14))
+			order.Observations = append(order.Observations, Observation{
+				SetId:          segment.Field(1),
+				ValueType:      segment.Field(2),
+				Identifier:     m.coded(segment.Field(3)),
+				Value:          m.observationValue(segment.Field(2), segment.Field(5)),
+				Units:          m.Unescape(m.first(segment.Field(6))),
+				ReferenceRange: m.Unescape(segment.Field(7)),
+				AbnormalFlags:  m.Unescape(segment.Field(8)),
+				ResultStatus:   strings.ToUpper(strings.TrimSpace(segment.Field(11))),
+				ObservedAt:     observedAt,
+			})
+		}
+	}
+	if !hasPatient {
+		return OruR01{}, ErrMissingPatient
+	}
+
+	return oru, nil
+}
+
+func (m Message) patient(segment Segment) Patient {
+	identifiers := make([]Identifier, 0)
+	for _, field := range []string{segment.Field(3), segment.Field(2)} {
+		if field == "" {
+			continue
+		}
+		for _, repetition := range m.Repetitions(field) {
+			identifier := m.identifier(repetition)
+			if identifier.Id != "" {
+				identifiers = append(identifiers, identifier)
+			}
+		}
+	}
+
+	// PID-5 is family name^given name^..., where the first repetition is the
+	// patient's legal name.
+	name := m.Components(m.Repetitions(segment.Field(5))[0])
+	patient := Patient{
+		Identifiers: identifiers,
+		Sex:         segment.Field(8),
+	}
+	if len(name) > 0 {
+		patient.LastName = name[0]
+	}
+	if len(name) > 1 {
+		patient.FirstName = name[1]
+	}
+	patient.DateOfBirth, _ = ParseTime(segment.Field(7))
+
+	return patient
+}
+
+func (m Message) identifier(field string) Identifier {
+	components := strings.Split(field, string(m.delims.component))
+	component := func(i int) string {
+		if i < len(components) {
+			return components[i]
+		}
+		return ""
+	}
+	authority, _, _ := strings.Cut(component(3), string(m.delims.subcomponent))
+
+	return Identifier{
+		Id:                 strings.TrimSpace(m.Unescape(component(0))),
+		AssigningAuthority: strings.TrimSpace(m.Unescape(authority)),
+		TypeCode:           strings.ToUpper(strings.TrimSpace(m.Unescape(component(4)))),
+	}
+}
+
+// observationValue returns the OBX-5 value, where the first repetition is
+// used, and a structured numeric, e.g. "<^5" or "^1^:^128", is joined back
+// into its text.
+func (m Message) observationValue(valueType, field string) string {
+	value := m.Repetitions(field)[0]
+	if valueType != "SN" {
+		return strings.TrimSpace(m.Unescape(value))
+	}
+
+	return strings.TrimSpace(strings.Join(m.Components(value), ""))
+}
@@ -0,0 +1,180 @@
+package icalgen
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	prodId = "-//SyrianHemophiliaSocietyLogs//Patient Calendar//EN"
+	// maxLineOctets is the maximum length of a content line, excluding the line
+	// break, as in RFC 5545 section 3.1
+	maxLineOctets = 75
+
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405Z"
+)
+
+// Alarm is a display reminder, triggered relative to the event's start,
+// where a negative offset triggers before the start.
+type Alarm struct {
+	Offset      time.Duration
+	Description string
+}
+
+// Event is a calendar's VEVENT, an all day event uses the start's date only,
+// in the start's own location, and lasts until the end of that day.
+type Event struct {
+	Uid         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Alarms      []Alarm
+}
+
+type Calendar struct {
+	Name string
+	// RefreshInterval hints subscribed clients on how often to fetch the calendar.
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Generate writes the calendar as an RFC 5545 iCalendar object to w,
+// where stamp is used as the events' DTSTAMP.
+func Generate(w io.Writer, calendar Calendar, stamp time.Time) error {
+	cw := &contentWriter{w: w}
+
+	cw.line("BEGIN", "VCALENDAR")
+	cw.line("VERSION", "2.0")
+	cw.line("PRODID", prodId)
+	cw.line("CALSCALE", "GREGORIAN")
+	cw.line("METHOD", "PUBLISH")
+	if calendar.Name != "" {
+		cw.line("X-WR-CALNAME", escapeText(calendar.Name))
+	}
+	if calendar.RefreshInterval > 0 {
+		cw.line("REFRESH-INTERVAL;VALUE=DURATION", formatDuration(calendar.RefreshInterval))
+		cw.line("X-PUBLISHED-TTL", formatDuration(calendar.RefreshInterval))
+	}
+
+	for _, event := range calendar.Events {
+		cw.line("BEGIN", "VEVENT")
+		cw.line("UID", escapeText(event.Uid))
+		cw.line("DTSTAMP", stamp.UTC().Format(dateTimeFormat))
+		if event.AllDay {
+			start := event.Start
+			cw.line("DTSTART;VALUE=DATE", start.Format(dateFormat))
+			cw.line("DTEND;VALUE=DATE", start.AddDate(0, 0, 1).Format(dateFormat))
+		} else {
+			cw.line("DTSTART", event.Start.UTC().Format(dateTimeFormat))
+			cw.line("DTEND", event.End.UTC().Format(dateTimeFormat))
+		}
+		cw.line("SUMMARY", escapeText(event.Summary))
+		if event.Description != "" {
+			cw.line("DESCRIPTION", escapeText(event.Description))
+		}
+		if event.AllDay {
+			cw.line("TRANSP", "TRANSPARENT")
+		}
+		for _, alarm := range event.Alarms {
+			cw.line("BEGIN", "VALARM")
+			cw.line("ACTION", "DISPLAY")
+			cw.line("DESCRIPTION", escapeText(alarm.Description))
+			cw.line("TRIGGER", formatDuration(alarm.Offset))
+			cw.line("END", "VALARM")
+		}
+		cw.line("END", "VEVENT")
+	}
+
+	cw.line("END", "VCALENDAR")
+
+	return cw.err
+}
+
+// contentWriter writes folded content lines, and keeps the first occurring
+// error so that the writes don't have to be checked one by one.
+type contentWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (cw *contentWriter) line(name, value string) {
+	if cw.err != nil {
+		return
+	}
+	_, cw.err = io.WriteString(cw.w, foldLine(name+":"+value))
+}
+
+// foldLine splits a content line into lines of at most 75 octets, where each
+// continuation line starts with a space, without splitting a UTF-8 sequence.
+func foldLine(line string) string {
+	sb := new(strings.Builder)
+	lineOctets := 0
+	for _, r := range line {
+		runeOctets := utf8.RuneLen(r)
+		if lineOctets+runeOctets > maxLineOctets {
+			sb.WriteString("\r\n ")
+			lineOctets = 1
+		}
+		sb.WriteRune(r)
+		lineOctets += runeOctets
+	}
+	sb.WriteString("\r\n")
+
+	return sb.String()
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// formatDuration formats d as an RFC 5545 duration, e.g. -PT4H or P1DT30M.
+func formatDuration(d time.Duration) string {
+	sb := new(strings.Builder)
+	if d < 0 {
+		sb.WriteByte('-')
+		d = -d
+	}
+	sb.WriteByte('P')
+
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	if days > 0 {
+		fmt.Fprintf(sb, "%dD", days)
+	}
+	if d == 0 && days > 0 {
+		return sb.String()
+	}
+
+	sb.WriteByte('T')
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+	seconds := d / time.Second
+	if hours > 0 {
+		fmt.Fprintf(sb, "%dH", hours)
+	}
+	if minutes > 0 {
+		fmt.Fprintf(sb, "%dM", minutes)
+	}
+	if seconds > 0 || (hours == 0 && minutes == 0) {
+		fmt.Fprintf(sb, "%dS", seconds)
+	}
+
+	return sb.String()
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"shs/config"
 
+	gomysql "github.com/go-sql-driver/mysql"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
@@ -32,16 +33,36 @@ func dbConnector() (*gorm.DB, error) {
 		}
 	}
 
-	instance, err := gorm.Open(mysql.Open(
-		fmt.Sprintf("%s:%s@tcp(%s)/%s?parseTime=True&loc=Local&charset=utf8mb4",
-			config.Env().DB.Username,
-			config.Env().DB.Password,
-			config.Env().DB.Host,
-			config.Env().DB.Name,
-		),
-	), &gorm.Config{})
+	conn, err := gorm.Open(dialector{
+		Dialector: mysql.Open(
+			fmt.Sprintf("%s:%s@tcp(%s)/%s?parseTime=True&loc=Local&charset=utf8mb4",
+				config.Env().DB.Username,
+				config.Env().DB.Password,
+				config.Env().DB.Host,
+				config.Env().DB.Name,
+			),
+		).(*mysql.Dialector),
+	}, &gorm.Config{
+		TranslateError: true,
+	})
 	if err != nil {
 		return nil, err
 	}
+
+	instance = conn
 	return instance, nil
 }
+
+// dialector translates a missing table to a record that's not found, as well as
+// the errors that the mysql driver translates.
+type dialector struct {
+	*mysql.Dialector
+}
+
+func (d dialector) Translate(err error) error {
+	if mysqlErr, ok := err.(*gomysql.MySQLError); ok && mysqlErr.Number == 1146 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return d.Dialector.Translate(err)
+}
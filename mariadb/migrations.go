@@ -0,0 +1,61 @@
+package mariadb
+
+import (
+	"embed"
+	"shs/dbmigrate"
+)
+
+// migrationsFs has the numbered migrations of the schema, see dbmigrate.
+//
+//go:embed migrations/*.sql
+var migrationsFs embed.FS
+
+// MigrationsDir is the source directory of the migrations, where the new
+// migrations are created.
+const MigrationsDir = "mariadb/migrations"
+
+func loadMigrations() ([]dbmigrate.Migration, error) {
+	return dbmigrate.Load(migrationsFs, "migrations")
+}
+
+// MigrateUp applies the pending migrations up to and including the given
+// version, where a zero version applies all of them.
+func MigrateUp(toVersion uint) error {
+	dbConn, err := dbConnector()
+	if err != nil {
+		return err
+	}
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	return dbmigrate.Up(dbConn, migrations, toVersion)
+}
+
+// MigrateDown rolls back the given number of the latest applied migrations.
+func MigrateDown(steps int) error {
+	dbConn, err := dbConnector()
+	if err != nil {
+		return err
+	}
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	return dbmigrate.Down(dbConn, migrations, steps)
+}
+
+func MigrationsStatus() ([]dbmigrate.Status, error) {
+	dbConn, err := dbConnector()
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	return dbmigrate.Statuses(dbConn, migrations)
+}
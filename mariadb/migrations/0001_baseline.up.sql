@@ -0,0 +1,275 @@
+-- Baseline of the schema that AutoMigrate created before the numbered
+-- migrations, where the tables are only created when they don't exist, so that
+-- an existing database only records this version, and the tables and columns
+-- that were added since are added by the following migrations.
+
+CREATE TABLE IF NOT EXISTS `accounts` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `display_name` longtext NOT NULL,
+    `username` varchar(191) NOT NULL,
+    `password` longtext NOT NULL,
+    `type` longtext NOT NULL,
+    `permissions` bigint unsigned NOT NULL,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_accounts_username` (`username`),
+    INDEX `idx_accounts_created_at` (`created_at`),
+    CONSTRAINT `uni_accounts_username` UNIQUE (`username`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `viruses` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `name` longtext NOT NULL,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_viruses_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `medicines` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `name` longtext NOT NULL,
+    `dose` bigint NOT NULL,
+    `unit` longtext NOT NULL,
+    `amount` bigint NOT NULL,
+    `expires_at` datetime(3) NOT NULL,
+    `received_at` datetime(3) NOT NULL,
+    `manufacturer` longtext NOT NULL,
+    `batch_number` longtext NOT NULL,
+    `factor` longtext NOT NULL,
+    `factor_type` longtext NOT NULL,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_medicines_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `visits` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `patient_id` bigint unsigned NOT NULL,
+    `reason` longtext NOT NULL,
+    `notes` longtext,
+    `patient_weight` double,
+    `patient_height` double,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_visits_patient_id` (`patient_id`),
+    INDEX `idx_visits_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `blood_tests` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `name` longtext NOT NULL,
+    `display_in_brief` boolean NOT NULL,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_blood_tests_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `identifying_blood_tests` (
+    `virus_id` bigint unsigned,
+    `blood_test_id` bigint unsigned,
+    PRIMARY KEY (`virus_id`,`blood_test_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `blood_test_results` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `blood_test_id` bigint unsigned NOT NULL,
+    `patient_id` bigint unsigned NOT NULL,
+    `pending` boolean NOT NULL,
+    `tested_at` datetime(3) NOT NULL,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_blood_test_results_patient_id` (`patient_id`),
+    INDEX `idx_blood_test_results_created_at` (`created_at`),
+    CONSTRAINT `fk_blood_test_results_blood_test` FOREIGN KEY (`blood_test_id`) REFERENCES `blood_tests`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `blood_test_fields` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `blood_test_id` bigint unsigned NOT NULL,
+    `name` longtext NOT NULL,
+    `unit` longtext NOT NULL,
+    `min_value_number` double,
+    `min_value_string` longtext,
+    `max_value_number` double,
+    `max_value_string` longtext,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_blood_test_fields_created_at` (`created_at`),
+    CONSTRAINT `fk_blood_tests_fields` FOREIGN KEY (`blood_test_id`) REFERENCES `blood_tests`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `blood_test_filled_fields` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `blood_test_result_id` bigint unsigned,
+    `blood_test_field_id` bigint unsigned,
+    `value_number` double,
+    `value_string` longtext,
+    `tested_at` datetime(3) NOT NULL,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_blood_test_filled_fields_created_at` (`created_at`),
+    CONSTRAINT `fk_blood_test_results_filled_fields` FOREIGN KEY (`blood_test_result_id`) REFERENCES `blood_test_results`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `addresses` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `governorate` varchar(191) NOT NULL,
+    `suburb` varchar(191) NOT NULL,
+    `street` varchar(191) NOT NULL,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_addresses_governorate` (`governorate`),
+    INDEX `idx_addresses_suburb` (`suburb`),
+    INDEX `idx_addresses_street` (`street`),
+    INDEX `idx_addresses_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `patients` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `public_id` varchar(191) NOT NULL,
+    `national_id` varchar(191),
+    `nationality` longtext NOT NULL,
+    `first_name` varchar(191) NOT NULL,
+    `last_name` varchar(191) NOT NULL,
+    `father_name` varchar(191) NOT NULL,
+    `mother_name` varchar(191) NOT NULL,
+    `place_of_birth_id` bigint unsigned NOT NULL,
+    `date_of_birth` datetime(3) NOT NULL,
+    `residency_id` bigint unsigned NOT NULL,
+    `gender` boolean NOT NULL,
+    `phone_number_country_code` longtext NOT NULL,
+    `phone_number` varchar(191) NOT NULL,
+    `family_history_exists` boolean NOT NULL,
+    `first_visit_reason` longtext NOT NULL,
+    `bat_score` bigint unsigned NOT NULL,
+    `wbdr` longtext,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_patients_public_id` (`public_id`),
+    INDEX `idx_patients_national_id` (`national_id`),
+    INDEX `idx_patients_first_name` (`first_name`),
+    INDEX `idx_patients_last_name` (`last_name`),
+    INDEX `idx_patients_father_name` (`father_name`),
+    INDEX `idx_patients_mother_name` (`mother_name`),
+    INDEX `idx_patients_place_of_birth_id` (`place_of_birth_id`),
+    INDEX `idx_patients_residency_id` (`residency_id`),
+    INDEX `idx_patients_gender` (`gender`),
+    INDEX `idx_patients_phone_number` (`phone_number`),
+    INDEX `idx_patients_created_at` (`created_at`),
+    CONSTRAINT `fk_patients_place_of_birth` FOREIGN KEY (`place_of_birth_id`) REFERENCES `addresses`(`id`),
+    CONSTRAINT `fk_patients_residency` FOREIGN KEY (`residency_id`) REFERENCES `addresses`(`id`),
+    CONSTRAINT `uni_patients_national_id` UNIQUE (`national_id`),
+    CONSTRAINT `uni_patients_public_id` UNIQUE (`public_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `patient_ids` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `public_id` bigint unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_patient_ids_public_id` (`public_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `has_viruses` (
+    `virus_id` bigint unsigned,
+    `patient_id` bigint unsigned,
+    `created_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`virus_id`,`patient_id`),
+    INDEX `idx_has_viruses_created_at` (`created_at`),
+    CONSTRAINT `fk_has_viruses_virus` FOREIGN KEY (`virus_id`) REFERENCES `viruses`(`id`),
+    CONSTRAINT `fk_has_viruses_patient` FOREIGN KEY (`patient_id`) REFERENCES `patients`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `treatment_details` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `title` longtext NOT NULL,
+    `arabic_title` longtext NOT NULL,
+    `type` longtext NOT NULL,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_treatment_details_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `prescribed_medicines` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `visit_id` bigint unsigned NOT NULL,
+    `patient_id` bigint unsigned NOT NULL,
+    `medicine_id` bigint unsigned NOT NULL,
+    `used_at` datetime(3) NULL,
+    `treatment_details_id` bigint unsigned,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_prescribed_medicines_visit_id` (`visit_id`),
+    INDEX `idx_prescribed_medicines_created_at` (`created_at`),
+    CONSTRAINT `fk_prescribed_medicines_medicine` FOREIGN KEY (`medicine_id`) REFERENCES `medicines`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `joints_evaluations` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `patient_id` bigint unsigned,
+    `right_ankle` bigint NOT NULL,
+    `left_ankle` bigint NOT NULL,
+    `right_knee` bigint NOT NULL,
+    `left_knee` bigint NOT NULL,
+    `right_elbow` bigint NOT NULL,
+    `left_elbow` bigint NOT NULL,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_joints_evaluations_patient_id` (`patient_id`),
+    INDEX `idx_joints_evaluations_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `prophylaxes` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `patient_id` bigint unsigned NOT NULL,
+    `medicine_id` bigint unsigned NOT NULL,
+    `medicine_amount` bigint NOT NULL,
+    `title` longtext NOT NULL,
+    `frequency_per_days` float NOT NULL,
+    `end_date` datetime(3) NULL,
+    `chosen` boolean,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_prophylaxes_patient_id` (`patient_id`),
+    INDEX `idx_prophylaxes_medicine_id` (`medicine_id`),
+    INDEX `idx_prophylaxes_created_at` (`created_at`),
+    CONSTRAINT `fk_prophylaxes_medicine` FOREIGN KEY (`medicine_id`) REFERENCES `medicines`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `diagnoses` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `group_name` longtext NOT NULL,
+    `title` longtext NOT NULL,
+    `icd11` longtext NOT NULL,
+    `aka` longtext,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_diagnoses_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `diagnoses_results` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `diagnosis_id` bigint unsigned NOT NULL,
+    `patient_id` bigint unsigned NOT NULL,
+    `diagnosed_at` datetime(3) NOT NULL,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_diagnoses_results_patient_id` (`patient_id`),
+    INDEX `idx_diagnoses_results_created_at` (`created_at`),
+    CONSTRAINT `fk_diagnoses_results_diagnosis` FOREIGN KEY (`diagnosis_id`) REFERENCES `diagnoses`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS `bleeding_episodes` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `patient_id` bigint unsigned NOT NULL,
+    `site` longtext NOT NULL,
+    `cause` longtext NOT NULL,
+    `severity` longtext NOT NULL,
+    `onset_at` datetime(3) NOT NULL,
+    `notes` longtext,
+    `visit_id` bigint unsigned,
+    `prescribed_medicine_id` bigint unsigned,
+    `reported_by_patient` boolean NOT NULL,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_bleeding_episodes_patient_id` (`patient_id`),
+    INDEX `idx_bleeding_episodes_onset_at` (`onset_at`),
+    INDEX `idx_bleeding_episodes_visit_id` (`visit_id`),
+    INDEX `idx_bleeding_episodes_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS `stock_movements` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `medicine_id` bigint unsigned NOT NULL,
+    `kind` longtext NOT NULL,
+    `delta` bigint NOT NULL,
+    `amount_after` bigint NOT NULL,
+    `account_id` bigint unsigned NOT NULL,
+    `visit_id` bigint unsigned,
+    `note` longtext,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_stock_movements_medicine_id` (`medicine_id`),
+    INDEX `idx_stock_movements_account_id` (`account_id`),
+    INDEX `idx_stock_movements_visit_id` (`visit_id`),
+    INDEX `idx_stock_movements_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS `medicine_alerts` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `medicine_id` bigint unsigned NOT NULL,
+    `kind` longtext NOT NULL,
+    `window_days` bigint,
+    `amount` bigint NOT NULL,
+    `expires_at` datetime(3) NOT NULL,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_medicine_alerts_medicine_id` (`medicine_id`),
+    INDEX `idx_medicine_alerts_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
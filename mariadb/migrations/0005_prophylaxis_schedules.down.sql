@@ -0,0 +1,5 @@
+ALTER TABLE `prophylaxes` DROP COLUMN `start_date`;
+ALTER TABLE `prophylaxes` DROP COLUMN `dose_iu`;
+ALTER TABLE `prophylaxes` DROP COLUMN `interval_days`;
+ALTER TABLE `prophylaxes` DROP COLUMN `weekdays`;
+ALTER TABLE `prophylaxes` DROP COLUMN `schedule_kind`;
@@ -0,0 +1,5 @@
+ALTER TABLE `prophylaxes` ADD COLUMN IF NOT EXISTS `schedule_kind` longtext;
+ALTER TABLE `prophylaxes` ADD COLUMN IF NOT EXISTS `weekdays` tinyint unsigned;
+ALTER TABLE `prophylaxes` ADD COLUMN IF NOT EXISTS `interval_days` bigint;
+ALTER TABLE `prophylaxes` ADD COLUMN IF NOT EXISTS `dose_iu` bigint;
+ALTER TABLE `prophylaxes` ADD COLUMN IF NOT EXISTS `start_date` datetime(3) NULL;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS `appointments` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `patient_id` bigint unsigned NOT NULL,
+    `reason` longtext NOT NULL,
+    `scheduled_at` datetime(3) NOT NULL,
+    `account_id` bigint unsigned,
+    `status` varchar(191) NOT NULL,
+    `notes` longtext,
+    `visit_id` bigint unsigned,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_appointments_patient_id` (`patient_id`),
+    INDEX `idx_appointments_scheduled_at` (`scheduled_at`),
+    INDEX `idx_appointments_account_id` (`account_id`),
+    INDEX `idx_appointments_status` (`status`),
+    INDEX `idx_appointments_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `hjhs_joint_scores`;
+ALTER TABLE `joints_evaluations` DROP COLUMN `global_gait`;
@@ -0,0 +1,20 @@
+ALTER TABLE `joints_evaluations` ADD COLUMN IF NOT EXISTS `global_gait` bigint;
+CREATE TABLE IF NOT EXISTS `hjhs_joint_scores` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `joints_evaluation_id` bigint unsigned NOT NULL,
+    `joint` longtext NOT NULL,
+    `swelling` bigint NOT NULL,
+    `swelling_duration` bigint NOT NULL,
+    `muscle_atrophy` bigint NOT NULL,
+    `crepitus` bigint NOT NULL,
+    `flexion_loss` bigint NOT NULL,
+    `extension_loss` bigint NOT NULL,
+    `joint_pain` bigint NOT NULL,
+    `strength` bigint NOT NULL,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_hjhs_joint_scores_joints_evaluation_id` (`joints_evaluation_id`),
+    INDEX `idx_hjhs_joint_scores_created_at` (`created_at`),
+    CONSTRAINT `fk_joints_evaluations_scores` FOREIGN KEY (`joints_evaluation_id`) REFERENCES `joints_evaluations`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS `blood_test_reference_ranges` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `blood_test_field_id` bigint unsigned NOT NULL,
+    `sex` longtext NOT NULL,
+    `min_age_months` bigint NOT NULL,
+    `max_age_months` bigint NOT NULL,
+    `low` double NOT NULL,
+    `high` double NOT NULL,
+    `critical_low` double,
+    `critical_high` double,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_blood_test_reference_ranges_blood_test_field_id` (`blood_test_field_id`),
+    INDEX `idx_blood_test_reference_ranges_created_at` (`created_at`),
+    CONSTRAINT `fk_blood_test_fields_reference_ranges` FOREIGN KEY (`blood_test_field_id`) REFERENCES `blood_test_fields`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
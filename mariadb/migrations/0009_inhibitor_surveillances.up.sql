@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS `inhibitor_surveillances` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `patient_id` bigint unsigned NOT NULL,
+    `screening_interval_eds` bigint NOT NULL,
+    `iti_status` longtext NOT NULL,
+    `iti_started_at` datetime(3) NULL,
+    `iti_ended_at` datetime(3) NULL,
+    `notes` longtext,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_inhibitor_surveillances_patient_id` (`patient_id`),
+    INDEX `idx_inhibitor_surveillances_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1 @@
+ALTER TABLE `blood_test_fields` ADD COLUMN IF NOT EXISTS `formula` longtext;
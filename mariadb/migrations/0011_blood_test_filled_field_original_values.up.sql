@@ -0,0 +1,2 @@
+ALTER TABLE `blood_test_filled_fields` ADD COLUMN IF NOT EXISTS `original_value` longtext;
+ALTER TABLE `blood_test_filled_fields` ADD COLUMN IF NOT EXISTS `original_unit` longtext;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `lab_code_mappings` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `coding_system` varchar(64) NOT NULL,
+    `code` varchar(64) NOT NULL,
+    `blood_test_field_id` bigint unsigned NOT NULL,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_lab_code_mapping_code` (`coding_system`,`code`),
+    INDEX `idx_lab_code_mappings_blood_test_field_id` (`blood_test_field_id`),
+    INDEX `idx_lab_code_mappings_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,2 @@
+DROP INDEX `idx_blood_test_fields_loinc_code` ON `blood_test_fields`;
+ALTER TABLE `blood_test_fields` DROP COLUMN `loinc_code`;
@@ -0,0 +1,2 @@
+ALTER TABLE `blood_test_fields` ADD COLUMN IF NOT EXISTS `loinc_code` varchar(16);
+CREATE INDEX IF NOT EXISTS `idx_blood_test_fields_loinc_code` ON `blood_test_fields`(`loinc_code`);
@@ -0,0 +1,3 @@
+UPDATE `accounts` SET `permissions` = `permissions` & ~1048576;
+
+DROP TABLE IF EXISTS `audit_events`;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS `audit_events` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `account_id` bigint unsigned NOT NULL,
+    `account_username` longtext NOT NULL,
+    `action` varchar(64) NOT NULL,
+    `kind` varchar(16) NOT NULL,
+    `patient_public_id` varchar(32),
+    `resource_ids` longtext,
+    `diff` longtext,
+    `client_ip` varchar(64),
+    `user_agent` longtext,
+    `created_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_audit_events_account_id` (`account_id`),
+    INDEX `idx_audit_events_action` (`action`),
+    INDEX `idx_audit_events_patient_public_id` (`patient_public_id`),
+    INDEX `idx_audit_events_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- The super admins can read the audit trail, where 1048576 is
+-- AccountPermissionReadAuditEvents.
+UPDATE `accounts` SET `permissions` = `permissions` | 1048576 WHERE `type` = 'superadmin';
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS `patient_revisions` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `patient_id` bigint unsigned NOT NULL,
+    `account_id` bigint unsigned NOT NULL,
+    `account_username` longtext NOT NULL,
+    `restored_from_revision_id` bigint unsigned,
+    `national_id` longtext NOT NULL,
+    `nationality` longtext NOT NULL,
+    `first_name` longtext NOT NULL,
+    `last_name` longtext NOT NULL,
+    `father_name` longtext NOT NULL,
+    `mother_name` longtext NOT NULL,
+    `place_of_birth_id` bigint unsigned NOT NULL,
+    `date_of_birth` datetime(3) NOT NULL,
+    `residency_id` bigint unsigned NOT NULL,
+    `gender` boolean NOT NULL,
+    `phone_number_country_code` longtext NOT NULL,
+    `phone_number` longtext NOT NULL,
+    `family_history_exists` boolean NOT NULL,
+    `first_visit_reason` longtext NOT NULL,
+    `bat_score` bigint unsigned NOT NULL,
+    `wbdr` longtext,
+    `created_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_patient_revisions_patient_id` (`patient_id`),
+    INDEX `idx_patient_revisions_created_at` (`created_at`),
+    CONSTRAINT `fk_patient_revisions_place_of_birth` FOREIGN KEY (`place_of_birth_id`) REFERENCES `addresses`(`id`),
+    CONSTRAINT `fk_patient_revisions_residency` FOREIGN KEY (`residency_id`) REFERENCES `addresses`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,3 @@
+DROP INDEX `idx_blood_test_results_lab_message` ON `blood_test_results`;
+ALTER TABLE `blood_test_results` DROP COLUMN `lab_message_control_id`;
+ALTER TABLE `blood_test_results` DROP COLUMN `lab_sending_facility`;
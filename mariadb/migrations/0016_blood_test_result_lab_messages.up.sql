@@ -0,0 +1,3 @@
+ALTER TABLE `blood_test_results` ADD COLUMN IF NOT EXISTS `lab_sending_facility` varchar(255);
+ALTER TABLE `blood_test_results` ADD COLUMN IF NOT EXISTS `lab_message_control_id` varchar(255);
+CREATE INDEX IF NOT EXISTS `idx_blood_test_results_lab_message` ON `blood_test_results`(`lab_sending_facility`, `lab_message_control_id`);
@@ -0,0 +1 @@
+ALTER TABLE `accounts` DROP COLUMN `calendar_feed_version`;
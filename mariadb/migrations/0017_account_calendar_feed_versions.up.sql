@@ -0,0 +1 @@
+ALTER TABLE `accounts` ADD COLUMN IF NOT EXISTS `calendar_feed_version` bigint unsigned NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+-- The backfilled texts are the fields' own numbers, so they're kept.
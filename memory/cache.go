@@ -0,0 +1,294 @@
+// Package memory has an actions.Cache that's kept in the server's memory, for
+// the installations that run a single server without a Redis, where the cache
+// can be saved to a file periodically, so that the sessions survive a restart.
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"shs/actions"
+	"shs/app"
+	"shs/config"
+	"shs/log"
+	"sync"
+	"time"
+)
+
+const (
+	accountSessionTokenTtlDays = 60
+	redirectPathTtlMinutes     = 30
+)
+
+type entry struct {
+	Value     string    `json:"value"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (e entry) expired(now time.Time) bool {
+	return !e.ExpiresAt.After(now)
+}
+
+type Cache struct {
+	mu           sync.Mutex
+	entries      map[string]entry
+	snapshotPath string
+	done         chan struct{}
+	closeOnce    sync.Once
+}
+
+// New returns a cache that's loaded from the configured snapshot, and saves
+// it to the snapshot on the configured interval, where the expired entries are
+// removed on the same interval.
+func New() *Cache {
+	return newCache(config.Env().Cache.SnapshotPath, config.Env().Cache.SnapshotInterval)
+}
+
+// NewWithoutSnapshot returns a cache that's never loaded from or saved to a
+// snapshot, for the binaries that run next to the server, so that they don't
+// overwrite the server's snapshot with their own entries.
+func NewWithoutSnapshot() *Cache {
+	return newCache("", config.Env().Cache.SnapshotInterval)
+}
+
+func newCache(snapshotPath string, snapshotInterval time.Duration) *Cache {
+	c := &Cache{
+		entries:      make(map[string]entry),
+		snapshotPath: snapshotPath,
+		done:         make(chan struct{}),
+	}
+
+	if c.snapshotPath != "" {
+		err := c.loadSnapshot()
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Errorf("[MEMORY CACHE]: Failed to load the snapshot %s, error: %s\n", c.snapshotPath, err.Error())
+		}
+	}
+
+	go c.runJanitor(snapshotInterval)
+
+	return c
+}
+
+// Close stops the janitor and saves a final snapshot, so that the entries that
+// were set since the last interval aren't lost on shutdown.
+func (c *Cache) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+
+	if c.snapshotPath == "" {
+		return nil
+	}
+	c.removeExpired()
+
+	return c.saveSnapshot()
+}
+
+func (c *Cache) runJanitor(interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+		}
+
+		c.removeExpired()
+		if c.snapshotPath == "" {
+			continue
+		}
+		err := c.saveSnapshot()
+		if err != nil {
+			log.Errorf("[MEMORY CACHE]: Failed to save the snapshot %s, error: %s\n", c.snapshotPath, err.Error())
+		}
+	}
+}
+
+func (c *Cache) set(key, value string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry{
+		Value:     value,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+}
+
+func (c *Cache) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	if e.expired(time.Now()) {
+		delete(c.entries, key)
+		return "", false
+	}
+
+	return e.Value, true
+}
+
+func (c *Cache) del(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
+
+func (c *Cache) removeExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for key, e := range c.entries {
+		if e.expired(now) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (c *Cache) loadSnapshot() error {
+	data, err := os.ReadFile(c.snapshotPath)
+	if err != nil {
+		return err
+	}
+
+	var entries map[string]entry
+	err = json.Unmarshal(data, &entries)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for key, e := range entries {
+		if !e.expired(now) {
+			c.entries[key] = e
+		}
+	}
+
+	return nil
+}
+
+// saveSnapshot writes the entries to a temporary file that replaces the
+// snapshot, so that a crash while saving doesn't leave a broken snapshot, where
+// the file is only readable by its owner, since it has the session tokens.
+func (c *Cache) saveSnapshot() error {
+	c.mu.Lock()
+	data, err := json.Marshal(c.entries)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.snapshotPath), filepath.Base(c.snapshotPath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.snapshotPath)
+}
+
+func accountTokenKey(sessionToken string) string {
+	return fmt.Sprintf("account-session-token:%s", sessionToken)
+}
+
+func accountIdToTokenKey(accountId uint) string {
+	return fmt.Sprintf("account-id-to-token:%d", accountId)
+}
+
+func (c *Cache) SetAuthenticatedAccount(sessionToken string, account actions.Account) error {
+	accountJson, err := json.Marshal(account)
+	if err != nil {
+		return err
+	}
+
+	c.set(accountIdToTokenKey(account.Id), sessionToken, accountSessionTokenTtlDays*time.Hour*24)
+	c.set(accountTokenKey(sessionToken), string(accountJson), accountSessionTokenTtlDays*time.Hour*24)
+
+	return nil
+}
+
+func (c *Cache) GetAuthenticatedAccount(sessionToken string) (actions.Account, error) {
+	value, ok := c.get(accountTokenKey(sessionToken))
+	if !ok {
+		return actions.Account{}, &app.ErrNotFound{
+			ResourceName: "account",
+		}
+	}
+
+	var account actions.Account
+	err := json.Unmarshal([]byte(value), &account)
+	if err != nil {
+		return actions.Account{}, err
+	}
+
+	return account, nil
+}
+
+func (c *Cache) InvalidateAuthenticatedAccount(sessionToken string) error {
+	c.del(accountTokenKey(sessionToken))
+
+	return nil
+}
+
+func (c *Cache) InvalidateAuthenticatedAccountById(accountId uint) error {
+	sessionToken, ok := c.get(accountIdToTokenKey(accountId))
+	c.del(accountIdToTokenKey(accountId))
+	if ok {
+		c.del(accountTokenKey(sessionToken))
+	}
+
+	return nil
+}
+
+func redirectPathKey(clientHash string) string {
+	return fmt.Sprintf("redirect-path:%s", clientHash)
+}
+
+func (c *Cache) SetRedirectPath(clientHash, path string) error {
+	c.set(redirectPathKey(clientHash), path, redirectPathTtlMinutes*time.Minute)
+
+	return nil
+}
+
+func (c *Cache) GetRedirectPath(clientHash string) (string, error) {
+	value, ok := c.get(redirectPathKey(clientHash))
+	if !ok {
+		return "", errors.New("oopsie")
+	}
+
+	return value, nil
+}
+
+func (c *Cache) FlushAll() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]entry)
+
+	return nil
+}
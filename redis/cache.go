@@ -34,6 +34,11 @@ func New() *Cache {
 	}
 }
 
+// Close closes the client's connections.
+func (c *Cache) Close() error {
+	return c.client.Close()
+}
+
 func accountTokenKey(sessionToken string) string {
 	return fmt.Sprintf("%saccount-session-token:%s", keyPrefix, sessionToken)
 }
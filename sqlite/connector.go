@@ -0,0 +1,34 @@
+package sqlite
+
+import (
+	"fmt"
+	"shs/config"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+var instance *gorm.DB = nil
+
+func dbConnector() (*gorm.DB, error) {
+	if instance != nil {
+		return instance, nil
+	}
+
+	// The transactions take the database's write lock when they start, so that
+	// the rows that are read to be updated can't change before they're
+	// updated, which SQLite doesn't have a SELECT ... FOR UPDATE for.
+	conn, err := gorm.Open(sqlite.Open(
+		fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_txlock=immediate",
+			config.Env().DB.Path,
+		),
+	), &gorm.Config{
+		TranslateError: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	instance = conn
+	return instance, nil
+}
@@ -0,0 +1,258 @@
+-- Baseline of the schema, which has the same tables as MariaDB's baseline
+-- migration.
+
+CREATE TABLE IF NOT EXISTS `accounts` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `display_name` text NOT NULL,
+    `username` text NOT NULL,
+    `password` text NOT NULL,
+    `type` text NOT NULL,
+    `permissions` integer NOT NULL,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime,
+    CONSTRAINT `uni_accounts_username` UNIQUE (`username`)
+);
+CREATE INDEX IF NOT EXISTS `idx_accounts_created_at` ON `accounts`(`created_at`);
+CREATE INDEX IF NOT EXISTS `idx_accounts_username` ON `accounts`(`username`);
+
+CREATE TABLE IF NOT EXISTS `viruses` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `name` text NOT NULL,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_viruses_created_at` ON `viruses`(`created_at`);
+
+CREATE TABLE IF NOT EXISTS `blood_tests` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `name` text NOT NULL,
+    `display_in_brief` numeric NOT NULL,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_blood_tests_created_at` ON `blood_tests`(`created_at`);
+
+CREATE TABLE IF NOT EXISTS `identifying_blood_tests` (
+    `virus_id` integer,
+    `blood_test_id` integer,
+    PRIMARY KEY (`virus_id`,`blood_test_id`),
+    CONSTRAINT `fk_identifying_blood_tests_virus` FOREIGN KEY (`virus_id`) REFERENCES `viruses`(`id`),
+    CONSTRAINT `fk_identifying_blood_tests_blood_test` FOREIGN KEY (`blood_test_id`) REFERENCES `blood_tests`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `medicines` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `name` text NOT NULL,
+    `dose` integer NOT NULL,
+    `unit` text NOT NULL,
+    `amount` integer NOT NULL,
+    `expires_at` datetime NOT NULL,
+    `received_at` datetime NOT NULL,
+    `manufacturer` text NOT NULL,
+    `batch_number` text NOT NULL,
+    `factor` text NOT NULL,
+    `factor_type` text NOT NULL,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_medicines_created_at` ON `medicines`(`created_at`);
+
+CREATE TABLE IF NOT EXISTS `visits` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `patient_id` integer NOT NULL,
+    `reason` text NOT NULL,
+    `notes` text,
+    `patient_weight` real,
+    `patient_height` real,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_visits_created_at` ON `visits`(`created_at`);
+CREATE INDEX IF NOT EXISTS `idx_visits_patient_id` ON `visits`(`patient_id`);
+
+CREATE TABLE IF NOT EXISTS `blood_test_results` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `blood_test_id` integer NOT NULL,
+    `patient_id` integer NOT NULL,
+    `pending` numeric NOT NULL,
+    `tested_at` datetime NOT NULL,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime,
+    CONSTRAINT `fk_blood_test_results_blood_test` FOREIGN KEY (`blood_test_id`) REFERENCES `blood_tests`(`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_blood_test_results_created_at` ON `blood_test_results`(`created_at`);
+CREATE INDEX IF NOT EXISTS `idx_blood_test_results_patient_id` ON `blood_test_results`(`patient_id`);
+
+CREATE TABLE IF NOT EXISTS `blood_test_fields` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `blood_test_id` integer NOT NULL,
+    `name` text NOT NULL,
+    `unit` text NOT NULL,
+    `min_value_number` real,
+    `min_value_string` text,
+    `max_value_number` real,
+    `max_value_string` text,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime,
+    CONSTRAINT `fk_blood_tests_fields` FOREIGN KEY (`blood_test_id`) REFERENCES `blood_tests`(`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_blood_test_fields_created_at` ON `blood_test_fields`(`created_at`);
+
+CREATE TABLE IF NOT EXISTS `blood_test_filled_fields` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `blood_test_result_id` integer,
+    `blood_test_field_id` integer,
+    `value_number` real,
+    `value_string` text,
+    `tested_at` datetime NOT NULL,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime,
+    CONSTRAINT `fk_blood_test_results_filled_fields` FOREIGN KEY (`blood_test_result_id`) REFERENCES `blood_test_results`(`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_blood_test_filled_fields_created_at` ON `blood_test_filled_fields`(`created_at`);
+
+CREATE TABLE IF NOT EXISTS `addresses` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `governorate` text NOT NULL,
+    `suburb` text NOT NULL,
+    `street` text NOT NULL,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_addresses_created_at` ON `addresses`(`created_at`);
+CREATE INDEX IF NOT EXISTS `idx_addresses_street` ON `addresses`(`street`);
+CREATE INDEX IF NOT EXISTS `idx_addresses_suburb` ON `addresses`(`suburb`);
+CREATE INDEX IF NOT EXISTS `idx_addresses_governorate` ON `addresses`(`governorate`);
+
+CREATE TABLE IF NOT EXISTS `patients` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `public_id` text NOT NULL,
+    `national_id` text,
+    `nationality` text NOT NULL,
+    `first_name` text NOT NULL,
+    `last_name` text NOT NULL,
+    `father_name` text NOT NULL,
+    `mother_name` text NOT NULL,
+    `place_of_birth_id` integer NOT NULL,
+    `date_of_birth` datetime NOT NULL,
+    `residency_id` integer NOT NULL,
+    `gender` numeric NOT NULL,
+    `phone_number_country_code` text NOT NULL,
+    `phone_number` text NOT NULL,
+    `family_history_exists` numeric NOT NULL,
+    `first_visit_reason` text NOT NULL,
+    `bat_score` integer NOT NULL,
+    `wbdr` text,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime,
+    CONSTRAINT `fk_patients_residency` FOREIGN KEY (`residency_id`) REFERENCES `addresses`(`id`),
+    CONSTRAINT `fk_patients_place_of_birth` FOREIGN KEY (`place_of_birth_id`) REFERENCES `addresses`(`id`),
+    CONSTRAINT `uni_patients_public_id` UNIQUE (`public_id`),
+    CONSTRAINT `uni_patients_national_id` UNIQUE (`national_id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_patients_created_at` ON `patients`(`created_at`);
+CREATE INDEX IF NOT EXISTS `idx_patients_phone_number` ON `patients`(`phone_number`);
+CREATE INDEX IF NOT EXISTS `idx_patients_gender` ON `patients`(`gender`);
+CREATE INDEX IF NOT EXISTS `idx_patients_residency_id` ON `patients`(`residency_id`);
+CREATE INDEX IF NOT EXISTS `idx_patients_place_of_birth_id` ON `patients`(`place_of_birth_id`);
+CREATE INDEX IF NOT EXISTS `idx_patients_mother_name` ON `patients`(`mother_name`);
+CREATE INDEX IF NOT EXISTS `idx_patients_father_name` ON `patients`(`father_name`);
+CREATE INDEX IF NOT EXISTS `idx_patients_last_name` ON `patients`(`last_name`);
+CREATE INDEX IF NOT EXISTS `idx_patients_first_name` ON `patients`(`first_name`);
+CREATE INDEX IF NOT EXISTS `idx_patients_national_id` ON `patients`(`national_id`);
+CREATE INDEX IF NOT EXISTS `idx_patients_public_id` ON `patients`(`public_id`);
+
+CREATE TABLE IF NOT EXISTS `patient_ids` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `public_id` integer NOT NULL
+);
+CREATE INDEX IF NOT EXISTS `idx_patient_ids_public_id` ON `patient_ids`(`public_id`);
+
+CREATE TABLE IF NOT EXISTS `has_viruses` (
+    `virus_id` integer,
+    `patient_id` integer,
+    `created_at` datetime NOT NULL,
+    PRIMARY KEY (`virus_id`,`patient_id`),
+    CONSTRAINT `fk_has_viruses_virus` FOREIGN KEY (`virus_id`) REFERENCES `viruses`(`id`),
+    CONSTRAINT `fk_has_viruses_patient` FOREIGN KEY (`patient_id`) REFERENCES `patients`(`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_has_viruses_created_at` ON `has_viruses`(`created_at`);
+
+CREATE TABLE IF NOT EXISTS `treatment_details` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `title` text NOT NULL,
+    `arabic_title` text NOT NULL,
+    `type` text NOT NULL,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_treatment_details_created_at` ON `treatment_details`(`created_at`);
+
+CREATE TABLE IF NOT EXISTS `prescribed_medicines` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `visit_id` integer NOT NULL,
+    `patient_id` integer NOT NULL,
+    `medicine_id` integer NOT NULL,
+    `used_at` datetime,
+    `treatment_details_id` integer,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime,
+    CONSTRAINT `fk_prescribed_medicines_medicine` FOREIGN KEY (`medicine_id`) REFERENCES `medicines`(`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_prescribed_medicines_created_at` ON `prescribed_medicines`(`created_at`);
+CREATE INDEX IF NOT EXISTS `idx_prescribed_medicines_visit_id` ON `prescribed_medicines`(`visit_id`);
+
+CREATE TABLE IF NOT EXISTS `joints_evaluations` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `patient_id` integer,
+    `right_ankle` integer NOT NULL,
+    `left_ankle` integer NOT NULL,
+    `right_knee` integer NOT NULL,
+    `left_knee` integer NOT NULL,
+    `right_elbow` integer NOT NULL,
+    `left_elbow` integer NOT NULL,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_joints_evaluations_created_at` ON `joints_evaluations`(`created_at`);
+CREATE INDEX IF NOT EXISTS `idx_joints_evaluations_patient_id` ON `joints_evaluations`(`patient_id`);
+
+CREATE TABLE IF NOT EXISTS `prophylaxes` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `patient_id` integer NOT NULL,
+    `medicine_id` integer NOT NULL,
+    `medicine_amount` integer NOT NULL,
+    `title` text NOT NULL,
+    `frequency_per_days` real NOT NULL,
+    `end_date` datetime,
+    `chosen` numeric,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime,
+    CONSTRAINT `fk_prophylaxes_medicine` FOREIGN KEY (`medicine_id`) REFERENCES `medicines`(`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_prophylaxes_created_at` ON `prophylaxes`(`created_at`);
+CREATE INDEX IF NOT EXISTS `idx_prophylaxes_medicine_id` ON `prophylaxes`(`medicine_id`);
+CREATE INDEX IF NOT EXISTS `idx_prophylaxes_patient_id` ON `prophylaxes`(`patient_id`);
+
+CREATE TABLE IF NOT EXISTS `diagnoses` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `group_name` text NOT NULL,
+    `title` text NOT NULL,
+    `icd11` text NOT NULL,
+    `aka` text,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_diagnoses_created_at` ON `diagnoses`(`created_at`);
+
+CREATE TABLE IF NOT EXISTS `diagnoses_results` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `diagnosis_id` integer NOT NULL,
+    `patient_id` integer NOT NULL,
+    `diagnosed_at` datetime NOT NULL,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime,
+    CONSTRAINT `fk_diagnoses_results_diagnosis` FOREIGN KEY (`diagnosis_id`) REFERENCES `diagnoses`(`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_diagnoses_results_created_at` ON `diagnoses_results`(`created_at`);
+CREATE INDEX IF NOT EXISTS `idx_diagnoses_results_patient_id` ON `diagnoses_results`(`patient_id`);
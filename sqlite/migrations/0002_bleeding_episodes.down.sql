@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `bleeding_episodes`;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS `bleeding_episodes` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `patient_id` integer NOT NULL,
+    `site` text NOT NULL,
+    `cause` text NOT NULL,
+    `severity` text NOT NULL,
+    `onset_at` datetime NOT NULL,
+    `notes` text,
+    `visit_id` integer,
+    `prescribed_medicine_id` integer,
+    `reported_by_patient` numeric NOT NULL,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_bleeding_episodes_created_at` ON `bleeding_episodes`(`created_at`);
+CREATE INDEX IF NOT EXISTS `idx_bleeding_episodes_visit_id` ON `bleeding_episodes`(`visit_id`);
+CREATE INDEX IF NOT EXISTS `idx_bleeding_episodes_onset_at` ON `bleeding_episodes`(`onset_at`);
+CREATE INDEX IF NOT EXISTS `idx_bleeding_episodes_patient_id` ON `bleeding_episodes`(`patient_id`);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS `stock_movements` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `medicine_id` integer NOT NULL,
+    `kind` text NOT NULL,
+    `delta` integer NOT NULL,
+    `amount_after` integer NOT NULL,
+    `account_id` integer NOT NULL,
+    `visit_id` integer,
+    `note` text,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_stock_movements_created_at` ON `stock_movements`(`created_at`);
+CREATE INDEX IF NOT EXISTS `idx_stock_movements_visit_id` ON `stock_movements`(`visit_id`);
+CREATE INDEX IF NOT EXISTS `idx_stock_movements_account_id` ON `stock_movements`(`account_id`);
+CREATE INDEX IF NOT EXISTS `idx_stock_movements_medicine_id` ON `stock_movements`(`medicine_id`);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `medicine_alerts` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `medicine_id` integer NOT NULL,
+    `kind` text NOT NULL,
+    `window_days` integer,
+    `amount` integer NOT NULL,
+    `expires_at` datetime NOT NULL,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_medicine_alerts_created_at` ON `medicine_alerts`(`created_at`);
+CREATE INDEX IF NOT EXISTS `idx_medicine_alerts_medicine_id` ON `medicine_alerts`(`medicine_id`);
@@ -0,0 +1,5 @@
+ALTER TABLE `prophylaxes` ADD COLUMN `schedule_kind` text;
+ALTER TABLE `prophylaxes` ADD COLUMN `weekdays` integer;
+ALTER TABLE `prophylaxes` ADD COLUMN `interval_days` integer;
+ALTER TABLE `prophylaxes` ADD COLUMN `dose_iu` integer;
+ALTER TABLE `prophylaxes` ADD COLUMN `start_date` datetime;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS `appointments` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `patient_id` integer NOT NULL,
+    `reason` text NOT NULL,
+    `scheduled_at` datetime NOT NULL,
+    `account_id` integer,
+    `status` text NOT NULL,
+    `notes` text,
+    `visit_id` integer,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_appointments_created_at` ON `appointments`(`created_at`);
+CREATE INDEX IF NOT EXISTS `idx_appointments_status` ON `appointments`(`status`);
+CREATE INDEX IF NOT EXISTS `idx_appointments_account_id` ON `appointments`(`account_id`);
+CREATE INDEX IF NOT EXISTS `idx_appointments_scheduled_at` ON `appointments`(`scheduled_at`);
+CREATE INDEX IF NOT EXISTS `idx_appointments_patient_id` ON `appointments`(`patient_id`);
@@ -0,0 +1,19 @@
+ALTER TABLE `joints_evaluations` ADD COLUMN `global_gait` integer;
+CREATE TABLE IF NOT EXISTS `hjhs_joint_scores` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `joints_evaluation_id` integer NOT NULL,
+    `joint` text NOT NULL,
+    `swelling` integer NOT NULL,
+    `swelling_duration` integer NOT NULL,
+    `muscle_atrophy` integer NOT NULL,
+    `crepitus` integer NOT NULL,
+    `flexion_loss` integer NOT NULL,
+    `extension_loss` integer NOT NULL,
+    `joint_pain` integer NOT NULL,
+    `strength` integer NOT NULL,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime,
+    CONSTRAINT `fk_joints_evaluations_scores` FOREIGN KEY (`joints_evaluation_id`) REFERENCES `joints_evaluations`(`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_hjhs_joint_scores_created_at` ON `hjhs_joint_scores`(`created_at`);
+CREATE INDEX IF NOT EXISTS `idx_hjhs_joint_scores_joints_evaluation_id` ON `hjhs_joint_scores`(`joints_evaluation_id`);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `blood_test_reference_ranges`;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS `blood_test_reference_ranges` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `blood_test_field_id` integer NOT NULL,
+    `sex` text NOT NULL,
+    `min_age_months` integer NOT NULL,
+    `max_age_months` integer NOT NULL,
+    `low` real NOT NULL,
+    `high` real NOT NULL,
+    `critical_low` real,
+    `critical_high` real,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime,
+    CONSTRAINT `fk_blood_test_fields_reference_ranges` FOREIGN KEY (`blood_test_field_id`) REFERENCES `blood_test_fields`(`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_blood_test_reference_ranges_created_at` ON `blood_test_reference_ranges`(`created_at`);
+CREATE INDEX IF NOT EXISTS `idx_blood_test_reference_ranges_blood_test_field_id` ON `blood_test_reference_ranges`(`blood_test_field_id`);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS `inhibitor_surveillances` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `patient_id` integer NOT NULL,
+    `screening_interval_eds` integer NOT NULL,
+    `iti_status` text NOT NULL,
+    `iti_started_at` datetime,
+    `iti_ended_at` datetime,
+    `notes` text,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_inhibitor_surveillances_created_at` ON `inhibitor_surveillances`(`created_at`);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_inhibitor_surveillances_patient_id` ON `inhibitor_surveillances`(`patient_id`);
@@ -0,0 +1 @@
+ALTER TABLE `blood_test_fields` ADD COLUMN `formula` text;
@@ -0,0 +1,2 @@
+ALTER TABLE `blood_test_filled_fields` DROP COLUMN `original_unit`;
+ALTER TABLE `blood_test_filled_fields` DROP COLUMN `original_value`;
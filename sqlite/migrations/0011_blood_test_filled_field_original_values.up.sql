@@ -0,0 +1,2 @@
+ALTER TABLE `blood_test_filled_fields` ADD COLUMN `original_value` text;
+ALTER TABLE `blood_test_filled_fields` ADD COLUMN `original_unit` text;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `lab_code_mappings`;
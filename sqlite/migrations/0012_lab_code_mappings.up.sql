@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS `lab_code_mappings` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `coding_system` text NOT NULL,
+    `code` text NOT NULL,
+    `blood_test_field_id` integer NOT NULL,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_lab_code_mappings_created_at` ON `lab_code_mappings`(`created_at`);
+CREATE INDEX IF NOT EXISTS `idx_lab_code_mappings_blood_test_field_id` ON `lab_code_mappings`(`blood_test_field_id`);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_lab_code_mapping_code` ON `lab_code_mappings`(`coding_system`,`code`);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS `idx_blood_test_fields_loinc_code`;
+ALTER TABLE `blood_test_fields` DROP COLUMN `loinc_code`;
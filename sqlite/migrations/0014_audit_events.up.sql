@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS `audit_events` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `account_id` integer NOT NULL,
+    `account_username` text NOT NULL,
+    `action` text NOT NULL,
+    `kind` text NOT NULL,
+    `patient_public_id` text,
+    `resource_ids` text,
+    `diff` text,
+    `client_ip` text,
+    `user_agent` text,
+    `created_at` datetime NOT NULL
+);
+CREATE INDEX IF NOT EXISTS `idx_audit_events_created_at` ON `audit_events`(`created_at`);
+CREATE INDEX IF NOT EXISTS `idx_audit_events_patient_public_id` ON `audit_events`(`patient_public_id`);
+CREATE INDEX IF NOT EXISTS `idx_audit_events_action` ON `audit_events`(`action`);
+CREATE INDEX IF NOT EXISTS `idx_audit_events_account_id` ON `audit_events`(`account_id`);
+
+-- The super admins can read the audit trail, where 1048576 is
+-- AccountPermissionReadAuditEvents.
+UPDATE `accounts` SET `permissions` = `permissions` | 1048576 WHERE `type` = 'superadmin';
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS `patient_revisions` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `patient_id` integer NOT NULL,
+    `account_id` integer NOT NULL,
+    `account_username` text NOT NULL,
+    `restored_from_revision_id` integer,
+    `national_id` text NOT NULL,
+    `nationality` text NOT NULL,
+    `first_name` text NOT NULL,
+    `last_name` text NOT NULL,
+    `father_name` text NOT NULL,
+    `mother_name` text NOT NULL,
+    `place_of_birth_id` integer NOT NULL,
+    `date_of_birth` datetime NOT NULL,
+    `residency_id` integer NOT NULL,
+    `gender` numeric NOT NULL,
+    `phone_number_country_code` text NOT NULL,
+    `phone_number` text NOT NULL,
+    `family_history_exists` numeric NOT NULL,
+    `first_visit_reason` text NOT NULL,
+    `bat_score` integer NOT NULL,
+    `wbdr` text,
+    `created_at` datetime NOT NULL,
+    CONSTRAINT `fk_patient_revisions_place_of_birth` FOREIGN KEY (`place_of_birth_id`) REFERENCES `addresses`(`id`),
+    CONSTRAINT `fk_patient_revisions_residency` FOREIGN KEY (`residency_id`) REFERENCES `addresses`(`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_patient_revisions_patient_id` ON `patient_revisions`(`patient_id`);
+CREATE INDEX IF NOT EXISTS `idx_patient_revisions_created_at` ON `patient_revisions`(`created_at`);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS `idx_blood_test_results_lab_message`;
+ALTER TABLE `blood_test_results` DROP COLUMN `lab_message_control_id`;
+ALTER TABLE `blood_test_results` DROP COLUMN `lab_sending_facility`;
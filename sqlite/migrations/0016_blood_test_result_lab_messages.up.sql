@@ -0,0 +1,3 @@
+ALTER TABLE `blood_test_results` ADD COLUMN `lab_sending_facility` text;
+ALTER TABLE `blood_test_results` ADD COLUMN `lab_message_control_id` text;
+CREATE INDEX IF NOT EXISTS `idx_blood_test_results_lab_message` ON `blood_test_results`(`lab_sending_facility`, `lab_message_control_id`);
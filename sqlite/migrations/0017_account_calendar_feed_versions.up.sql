@@ -0,0 +1 @@
+ALTER TABLE `accounts` ADD COLUMN `calendar_feed_version` integer NOT NULL DEFAULT 0;
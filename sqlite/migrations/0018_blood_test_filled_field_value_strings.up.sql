@@ -0,0 +1,6 @@
+-- A field's text is the value as it was entered, where the fields that were
+-- created with a number only have their number as their text, so that a zero
+-- isn't taken as an empty field. The fields that were created with a zero and
+-- no text can't be told apart from the empty ones, and they're kept empty.
+UPDATE `blood_test_filled_fields` SET `value_string` = CAST(`value_number` AS TEXT)
+WHERE (`value_string` IS NULL OR `value_string` = '') AND `value_number` IS NOT NULL AND `value_number` <> 0;
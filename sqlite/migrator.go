@@ -0,0 +1,18 @@
+package sqlite
+
+// Migrate applies the pending migrations, and creates the super admin when it
+// doesn't exist.
+func Migrate() error {
+	err := MigrateUp(0)
+	if err != nil {
+		return err
+	}
+
+	repo, err := New()
+	if err != nil {
+		return err
+	}
+	_ = repo.CreateSuperAdmin()
+
+	return nil
+}
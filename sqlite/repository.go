@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"shs/app/models"
+	"shs/gormrepo"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func New() (*gormrepo.Repository, error) {
+	conn, err := dbConnector()
+	if err != nil {
+		return nil, err
+	}
+
+	return gormrepo.New(conn, dialect{}), nil
+}
+
+type dialect struct{}
+
+// AgeInYears is the difference of the years, less one when the birthday didn't
+// come yet, since SQLite doesn't have a TIMESTAMPDIFF.
+func (dialect) AgeInYears(column string, at time.Time) (string, []any) {
+	return "CAST(strftime('%Y', ?) AS INTEGER) - CAST(strftime('%Y', " + column + ") AS INTEGER) - " +
+			"(strftime('%m-%d', ?) < strftime('%m-%d', " + column + "))",
+		[]any{at, at}
+}
+
+// FindOrCreateLastPatientId reads the last id and creates the next one in a
+// single transaction, whose write lock keeps the patients that are created at
+// the same time from getting the same id.
+func (dialect) FindOrCreateLastPatientId(db *gorm.DB) (models.PatientId, error) {
+	lastPatientId := models.PatientId{
+		PublicId: 1,
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var patientIds []models.PatientId
+		err := tx.
+			Model(new(models.PatientId)).
+			Order("id DESC").
+			Limit(1).
+			Find(&patientIds).
+			Error
+		if err != nil {
+			return err
+		}
+		if len(patientIds) > 0 {
+			lastPatientId = patientIds[0]
+		}
+
+		return tx.
+			Model(new(models.PatientId)).
+			Create(&models.PatientId{
+				PublicId: lastPatientId.PublicId + 1,
+			}).
+			Error
+	})
+	if err != nil {
+		return models.PatientId{}, err
+	}
+
+	return lastPatientId, nil
+}
+
+func (dialect) SetForeignKeyChecks(enabled bool) string {
+	if enabled {
+		return "PRAGMA foreign_keys = ON;"
+	}
+	return "PRAGMA foreign_keys = OFF;"
+}
@@ -0,0 +1,310 @@
+// Package unitconv converts blood test values between the units of
+// [models.BlootTestUnit], so that a value from a lab that reports in other
+// units than the field's can be normalised to the field's unit.
+//
+// Units of the same dimension, e.g. g/dL and mg/dL, convert by a fixed
+// factor, and some units convert across dimensions only for specific
+// analytes, e.g. mg/dL and mmol/L need the analyte's molar mass, and a
+// coagulation factor's % activity is the same as its IU/dL.
+package unitconv
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"shs/app/models"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+var ErrIncompatibleUnits = errors.New("unitconv: incompatible units")
+
+type dimension int
+
+const (
+	dimensionNone dimension = iota
+	dimensionTime
+	dimensionMass
+	dimensionVolume
+	dimensionMassConcentration
+	dimensionMolarConcentration
+	dimensionCellConcentration
+	dimensionActivityConcentration
+	dimensionFraction
+)
+
+// scale is a unit's dimension and the factor that converts a value in the
+// unit to the dimension's base unit, which is noted by each dimension.
+type scale struct {
+	dimension dimension
+	factor    float64
+}
+
+var scales = map[models.BlootTestUnit]scale{
+	// base: second
+	models.BlootTestUnitSecond: {dimensionTime, 1},
+	models.BlootTestUnitMinute: {dimensionTime, 60},
+
+	// base: g
+	models.BlootTestUnitGram:     {dimensionMass, 1},
+	models.BlootTestUnitPicoGram: {dimensionMass, 1e-12},
+
+	// base: mL
+	models.BlootTestUnitML:         {dimensionVolume, 1},
+	models.BlootTestUnitFemtoLiter: {dimensionVolume, 1e-12},
+
+	// base: g/L
+	models.BlootTestUnitGramPerLiter:           {dimensionMassConcentration, 1},
+	models.BlootTestUnitGramPerDeciLiter:       {dimensionMassConcentration, 10},
+	models.BlootTestUnitGramPerCubicCentimeter: {dimensionMassConcentration, 1000},
+	models.BlootTestUnitMilligramPerDeciLiter:  {dimensionMassConcentration, 1e-2},
+	models.BlootTestUnitMicroGramPerDeciLiter:  {dimensionMassConcentration, 1e-5},
+	models.BlootTestUnitNanoGramPerDeciLiter:   {dimensionMassConcentration, 1e-8},
+	models.BlootTestUnitPicoGramPerDeciLiter:   {dimensionMassConcentration, 1e-11},
+
+	// base: mmol/L
+	models.BlootTestUnitMilliMolePerLiter: {dimensionMolarConcentration, 1},
+	models.BlootTestUnitMicroMolePerLiter: {dimensionMolarConcentration, 1e-3},
+
+	// base: cell/mm^3, where 10^9/L is the same as 10^3/mm^3
+	models.BlootTestUnitCellPerCubicMilliLiter:         {dimensionCellConcentration, 1},
+	models.BlootTestUnitThousandCellPerCubicMillimeter: {dimensionCellConcentration, 1e3},
+	models.BlootTestUnitMillionCellPerCubicMillimeter:  {dimensionCellConcentration, 1e6},
+	models.BlootTestUnitBillionCellPerLiter:            {dimensionCellConcentration, 1e3},
+	models.BlootTestUnitTrillionCellPerLiter:           {dimensionCellConcentration, 1e6},
+
+	// base: IU/dL
+	models.BlootTestUnitInternationalUnitPerDeciLiter:  {dimensionActivityConcentration, 1},
+	models.BlootTestUnitInternationalUnitPerMilliLiter: {dimensionActivityConcentration, 100},
+	models.BlootTestUnitUnitPerLiter:                   {dimensionActivityConcentration, 0.1},
+
+	// base: L/L
+	models.BlootTestUnitLiterPerLiter: {dimensionFraction, 1},
+	models.BlootTestUnitPercentage:    {dimensionFraction, 1e-2},
+}
+
+// analyte has the factors that convert its values across dimensions, where
+// the names are matched as whole words of a field's name, case insensitively.
+type analyte struct {
+	names []string
+	// molarMass in g/mol, converts between mass and molar concentrations.
+	molarMass float64
+	// activityPercent is a coagulation factor, where 1% of normal activity
+	// is 1 IU/dL.
+	activityPercent bool
+}
+
+var analytes = []analyte{
+	{names: []string{"hb", "hgb", "hemoglobin", "haemoglobin"}, molarMass: 16114.5},
+	{names: []string{"glucose", "glu"}, molarMass: 180.16},
+	{names: []string{"creatinine", "creat", "cr"}, molarMass: 113.12},
+	{names: []string{"bilirubin", "bil", "tbil", "dbil"}, molarMass: 584.66},
+	{names: []string{"cholesterol", "chol", "ldl", "hdl"}, molarMass: 386.65},
+	{names: []string{"triglycerides", "triglyceride", "tg"}, molarMass: 885.7},
+	{names: []string{"urea"}, molarMass: 60.06},
+	{names: []string{"bun", "urea nitrogen"}, molarMass: 28.014},
+	{names: []string{"uric acid", "urate"}, molarMass: 168.11},
+	{names: []string{"calcium", "ca"}, molarMass: 40.08},
+	{names: []string{"iron", "fe"}, molarMass: 55.845},
+	{names: []string{"factor", "fviii", "fix", "viii", "ix", "vwf", "von willebrand"}, activityPercent: true},
+}
+
+// findAnalyte returns the first analyte that any of the names refer to, e.g.
+// a field's name then its blood test's name.
+func findAnalyte(names ...string) (analyte, bool) {
+	for _, name := range names {
+		words := " " + strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}), " ") + " "
+		for _, a := range analytes {
+			if slices.ContainsFunc(a.names, func(analyteName string) bool {
+				return strings.Contains(words, " "+analyteName+" ")
+			}) {
+				return a, true
+			}
+		}
+	}
+
+	return analyte{}, false
+}
+
+// scaleOf returns the unit's scale for the analyte, where an analyte's factors
+// move a unit into the dimension that the analyte is converted in.
+func scaleOf(unit models.BlootTestUnit, a analyte) (scale, bool) {
+	s, ok := scales[unit]
+	if !ok {
+		return scale{}, false
+	}
+
+	switch {
+	case a.activityPercent && unit == models.BlootTestUnitPercentage:
+		return scale{dimensionActivityConcentration, 1}, true
+	case a.molarMass != 0 && s.dimension == dimensionMassConcentration:
+		// g/L to mmol/L
+		return scale{dimensionMolarConcentration, s.factor / a.molarMass * 1000}, true
+	default:
+		return s, true
+	}
+}
+
+// Convert converts the value from a unit to another, where the analyte names
+// are used for conversions that depend on what's measured, and the same unit
+// converts to itself even when it's not a known unit, e.g. BU.
+func Convert(value float64, from, to models.BlootTestUnit, analyteNames ...string) (float64, error) {
+	if from == to {
+		return value, nil
+	}
+
+	a, _ := findAnalyte(analyteNames...)
+	fromScale, ok := scaleOf(from, a)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q to %q", ErrIncompatibleUnits, from, to)
+	}
+	toScale, ok := scaleOf(to, a)
+	if !ok || fromScale.dimension != toScale.dimension {
+		return 0, fmt.Errorf("%w: %q to %q", ErrIncompatibleUnits, from, to)
+	}
+
+	return value * fromScale.factor / toScale.factor, nil
+}
+
+// Compatible reports whether a value can be converted between the units.
+func Compatible(from, to models.BlootTestUnit, analyteNames ...string) bool {
+	_, err := Convert(1, from, to, analyteNames...)
+	return err == nil
+}
+
+// CompatibleUnits returns the units that a value can be entered in, and be
+// converted to the given unit, starting with the unit itself.
+func CompatibleUnits(to models.BlootTestUnit, analyteNames ...string) []models.BlootTestUnit {
+	units := []models.BlootTestUnit{to}
+	for _, unit := range models.BloodTestUnits() {
+		if unit != to && Compatible(unit, to, analyteNames...) {
+			units = append(units, unit)
+		}
+	}
+
+	return units
+}
+
+// Round rounds a converted value to a number of significant digits, to drop
+// the noise that the conversion factors add.
+func Round(value float64, significantDigits int) float64 {
+	if value == 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+		return value
+	}
+
+	magnitude := int(math.Ceil(math.Log10(math.Abs(value))))
+	factor := math.Pow10(significantDigits - magnitude)
+
+	return math.Round(value*factor) / factor
+}
+
+// unitAliases are the units' spellings that labs use, e.g. in HL7 OBX-6 and
+// UCUM, keyed by their normalised spelling, see normaliseUnit.
+var unitAliases = map[string]models.BlootTestUnit{
+	"s":           models.BlootTestUnitSecond,
+	"sec":         models.BlootTestUnitSecond,
+	"seconds":     models.BlootTestUnitSecond,
+	"min":         models.BlootTestUnitMinute,
+	"minutes":     models.BlootTestUnitMinute,
+	"ug/dl":       models.BlootTestUnitMicroGramPerDeciLiter,
+	"uiu/ml":      models.BlootTestUnitMicroUnitPerMilliLiter,
+	"uu/ml":       models.BlootTestUnitMicroUnitPerMilliLiter,
+	"miu/l":       models.BlootTestUnitMicroUnitPerMilliLiter,
+	"[iu]/dl":     models.BlootTestUnitInternationalUnitPerDeciLiter,
+	"[iu]/ml":     models.BlootTestUnitInternationalUnitPerMilliLiter,
+	"iu/l":        models.BlootTestUnitUnitPerLiter,
+	"[iu]/l":      models.BlootTestUnitUnitPerLiter,
+	"/ul":         models.BlootTestUnitCellPerCubicMilliLiter,
+	"/mm^3":       models.BlootTestUnitCellPerCubicMilliLiter,
+	"cells/ul":    models.BlootTestUnitCellPerCubicMilliLiter,
+	"10^3/ul":     models.BlootTestUnitThousandCellPerCubicMillimeter,
+	"10^3/mm^3":   models.BlootTestUnitThousandCellPerCubicMillimeter,
+	"x10^3/ul":    models.BlootTestUnitThousandCellPerCubicMillimeter,
+	"10^6/ul":     models.BlootTestUnitMillionCellPerCubicMillimeter,
+	"10^6/mm^3":   models.BlootTestUnitMillionCellPerCubicMillimeter,
+	"x10^6/ul":    models.BlootTestUnitMillionCellPerCubicMillimeter,
+	"x10^9/l":     models.BlootTestUnitBillionCellPerLiter,
+	"10^9/l":      models.BlootTestUnitBillionCellPerLiter,
+	"x10^12/l":    models.BlootTestUnitTrillionCellPerLiter,
+	"10^12/l":     models.BlootTestUnitTrillionCellPerLiter,
+	"l/l":         models.BlootTestUnitLiterPerLiter,
+	"{ratio}":     models.BlootTestUnitRatioOrIndex,
+	"ratio":       models.BlootTestUnitRatioOrIndex,
+	"{index}":     models.BlootTestUnitRatioOrIndex,
+	"[beth'u]":    models.BlootTestUnitBU,
+	"bu/ml":       models.BlootTestUnitBU,
+	"bethesda":    models.BlootTestUnitBU,
+	"mmol/l":      models.BlootTestUnitMilliMolePerLiter,
+	"umol/l":      models.BlootTestUnitMicroMolePerLiter,
+	"g/cm3":       models.BlootTestUnitGramPerCubicCentimeter,
+	"10^3cell/ul": models.BlootTestUnitThousandCellPerCubicMillimeter,
+}
+
+// normaliseUnit lower cases the unit, drops its spaces, and spells its
+// exponents and micro prefixes the same way, e.g. "x10*9/L" and "X10E9/L" are
+// both "x10^9/l", and "µmol/L" is "umol/l".
+func normaliseUnit(unit string) string {
+	unit = strings.ToLower(strings.Join(strings.Fields(unit), ""))
+	unit = strings.NewReplacer("µ", "u", "μ", "u", "mc", "u", "*", "^", "10e", "10^").Replace(unit)
+	return unit
+}
+
+// ParseUnit returns the unit that a lab's unit spelling refers to, where an
+// empty unit isn't a unit.
+func ParseUnit(unit string) (models.BlootTestUnit, bool) {
+	normalised := normaliseUnit(unit)
+	if normalised == "" {
+		return "", false
+	}
+	for _, known := range models.BloodTestUnits() {
+		if normaliseUnit(string(known)) == normalised {
+			return known, true
+		}
+	}
+	known, ok := unitAliases[normalised]
+
+	return known, ok
+}
+
+// ucumCodes are the units' UCUM codes, see https://ucum.org
+var ucumCodes = map[models.BlootTestUnit]string{
+	models.BlootTestUnitSecond:                         "s",
+	models.BlootTestUnitMinute:                         "min",
+	models.BlootTestUnitPercentage:                     "%",
+	models.BlootTestUnitCell:                           "{cells}",
+	models.BlootTestUnitBU:                             "[beth'U]",
+	models.BlootTestUnitGram:                           "g",
+	models.BlootTestUnitPicoGram:                       "pg",
+	models.BlootTestUnitGramPerDeciLiter:               "g/dL",
+	models.BlootTestUnitGramPerLiter:                   "g/L",
+	models.BlootTestUnitGramPerCubicCentimeter:         "g/cm3",
+	models.BlootTestUnitMilligramPerDeciLiter:          "mg/dL",
+	models.BlootTestUnitMicroUnitPerMilliLiter:         "u[IU]/mL",
+	models.BlootTestUnitMicroGramPerDeciLiter:          "ug/dL",
+	models.BlootTestUnitNanoGramPerDeciLiter:           "ng/dL",
+	models.BlootTestUnitPicoGramPerDeciLiter:           "pg/dL",
+	models.BlootTestUnitMilliMolePerLiter:              "mmol/L",
+	models.BlootTestUnitMicroMolePerLiter:              "umol/L",
+	models.BlootTestUnitML:                             "mL",
+	models.BlootTestUnitFemtoLiter:                     "fL",
+	models.BlootTestUnitInternationalUnitPerDeciLiter:  "[IU]/dL",
+	models.BlootTestUnitUnitPerLiter:                   "U/L",
+	models.BlootTestUnitInternationalUnitPerMilliLiter: "[IU]/mL",
+	models.BlootTestUnitCellPerCubicMilliLiter:         "/mm3",
+	models.BlootTestUnitThousandCellPerCubicMillimeter: "10*3/mm3",
+	models.BlootTestUnitMillionCellPerCubicMillimeter:  "10*6/mm3",
+	models.BlootTestUnitBillionCellPerLiter:            "10*9/L",
+	models.BlootTestUnitTrillionCellPerLiter:           "10*12/L",
+	models.BlootTestUnitLiterPerLiter:                  "L/L",
+	models.BlootTestUnitRatioOrIndex:                   "1",
+}
+
+// Ucum returns the unit's UCUM code, where a field without a unit doesn't
+// have one.
+func Ucum(unit models.BlootTestUnit) (string, bool) {
+	code, ok := ucumCodes[unit]
+	return code, ok
+}
@@ -0,0 +1,192 @@
+package unitconv
+
+import (
+	"errors"
+	"math"
+	"shs/app/models"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name         string
+		value        float64
+		from, to     models.BlootTestUnit
+		analyteNames []string
+		want         float64
+		wantErr      bool
+	}{
+		{
+			name: "same unit", value: 12.5,
+			from: models.BlootTestUnitBU, to: models.BlootTestUnitBU,
+			want: 12.5,
+		},
+		{
+			name: "seconds to minutes", value: 90,
+			from: models.BlootTestUnitSecond, to: models.BlootTestUnitMinute,
+			want: 1.5,
+		},
+		{
+			name: "hemoglobin g/dL to g/L", value: 13.5,
+			from: models.BlootTestUnitGramPerDeciLiter, to: models.BlootTestUnitGramPerLiter,
+			analyteNames: []string{"Hb"},
+			want:         135,
+		},
+		{
+			name: "fibrinogen g/L to mg/dL", value: 2.5,
+			from: models.BlootTestUnitGramPerLiter, to: models.BlootTestUnitMilligramPerDeciLiter,
+			want: 250,
+		},
+		{
+			name: "glucose mg/dL to mmol/L", value: 90,
+			from: models.BlootTestUnitMilligramPerDeciLiter, to: models.BlootTestUnitMilliMolePerLiter,
+			analyteNames: []string{"Glucose"},
+			want:         4.9956,
+		},
+		{
+			name: "glucose mmol/L to mg/dL", value: 5.5,
+			from: models.BlootTestUnitMilliMolePerLiter, to: models.BlootTestUnitMilligramPerDeciLiter,
+			analyteNames: []string{"Fasting Blood Glucose"},
+			want:         99.088,
+		},
+		{
+			name: "creatinine mg/dL to umol/L", value: 1,
+			from: models.BlootTestUnitMilligramPerDeciLiter, to: models.BlootTestUnitMicroMolePerLiter,
+			analyteNames: []string{"S. Creat"},
+			want:         88.401,
+		},
+		{
+			name: "analyte from the blood test's name", value: 90,
+			from: models.BlootTestUnitMilligramPerDeciLiter, to: models.BlootTestUnitMilliMolePerLiter,
+			analyteNames: []string{"Fasting", "Glucose Test"},
+			want:         4.9956,
+		},
+		{
+			name: "factor VIII % activity to IU/dL", value: 45,
+			from: models.BlootTestUnitPercentage, to: models.BlootTestUnitInternationalUnitPerDeciLiter,
+			analyteNames: []string{"Factor - VIII"},
+			want:         45,
+		},
+		{
+			name: "factor IX IU/mL to % activity", value: 0.02,
+			from: models.BlootTestUnitInternationalUnitPerMilliLiter, to: models.BlootTestUnitPercentage,
+			analyteNames: []string{"FIX"},
+			want:         2,
+		},
+		{
+			name: "hematocrit % to L/L", value: 42,
+			from: models.BlootTestUnitPercentage, to: models.BlootTestUnitLiterPerLiter,
+			analyteNames: []string{"Hct"},
+			want:         0.42,
+		},
+		{
+			name: "platelets 10^9/L to 10^3 cell/mm^3", value: 250,
+			from: models.BlootTestUnitBillionCellPerLiter, to: models.BlootTestUnitThousandCellPerCubicMillimeter,
+			want: 250,
+		},
+		{
+			name: "mass to molar without an analyte", value: 90,
+			from: models.BlootTestUnitMilligramPerDeciLiter, to: models.BlootTestUnitMilliMolePerLiter,
+			wantErr: true,
+		},
+		{
+			name: "% activity to IU/dL without a factor", value: 45,
+			from: models.BlootTestUnitPercentage, to: models.BlootTestUnitInternationalUnitPerDeciLiter,
+			analyteNames: []string{"Hct"},
+			wantErr:      true,
+		},
+		{
+			name: "factor % activity to L/L", value: 45,
+			from: models.BlootTestUnitPercentage, to: models.BlootTestUnitLiterPerLiter,
+			analyteNames: []string{"Factor VIII"},
+			wantErr:      true,
+		},
+		{
+			name: "analyte name is a whole word", value: 90,
+			from: models.BlootTestUnitMilligramPerDeciLiter, to: models.BlootTestUnitMilliMolePerLiter,
+			analyteNames: []string{"Glucosamine"},
+			wantErr:      true,
+		},
+		{
+			name: "different dimensions", value: 1,
+			from: models.BlootTestUnitSecond, to: models.BlootTestUnitGram,
+			wantErr: true,
+		},
+		{
+			name: "unknown unit", value: 1,
+			from: models.BlootTestUnitBU, to: models.BlootTestUnitInternationalUnitPerDeciLiter,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.value, tt.from, tt.to, tt.analyteNames...)
+			if tt.wantErr {
+				if !errors.Is(err, ErrIncompatibleUnits) {
+					t.Errorf("Convert() error = %v, want %v", err, ErrIncompatibleUnits)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-3 {
+				t.Errorf("Convert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		name              string
+		value             float64
+		significantDigits int
+		want              float64
+	}{
+		{name: "zero", value: 0, significantDigits: 3, want: 0},
+		{name: "conversion noise", value: 134.99999999999997, significantDigits: 4, want: 135},
+		{name: "glucose", value: 4.99555950266, significantDigits: 3, want: 5},
+		{name: "small value", value: 0.0123456, significantDigits: 2, want: 0.012},
+		{name: "large value", value: 123456, significantDigits: 3, want: 123000},
+		{name: "negative value", value: -1.23456, significantDigits: 3, want: -1.23},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Round(tt.value, tt.significantDigits); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Round(%v, %d) = %v, want %v", tt.value, tt.significantDigits, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnit(t *testing.T) {
+	tests := []struct {
+		unit   string
+		want   models.BlootTestUnit
+		wantOk bool
+	}{
+		{unit: "mg/dL", want: models.BlootTestUnitMilligramPerDeciLiter, wantOk: true},
+		{unit: "MG/DL", want: models.BlootTestUnitMilligramPerDeciLiter, wantOk: true},
+		{unit: "µmol/L", want: models.BlootTestUnitMicroMolePerLiter, wantOk: true},
+		{unit: "x10*9/L", want: models.BlootTestUnitBillionCellPerLiter, wantOk: true},
+		{unit: "X10E9/L", want: models.BlootTestUnitBillionCellPerLiter, wantOk: true},
+		{unit: "10^3 cell/mm^3", want: models.BlootTestUnitThousandCellPerCubicMillimeter, wantOk: true},
+		{unit: "[IU]/dL", want: models.BlootTestUnitInternationalUnitPerDeciLiter, wantOk: true},
+		{unit: "[beth'U]", want: models.BlootTestUnitBU, wantOk: true},
+		{unit: "%", want: models.BlootTestUnitPercentage, wantOk: true},
+		{unit: "", wantOk: false},
+		{unit: "furlong", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.unit, func(t *testing.T) {
+			got, ok := ParseUnit(tt.unit)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("ParseUnit(%q) = %q, %v, want %q, %v", tt.unit, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
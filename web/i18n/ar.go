@@ -304,4 +304,301 @@ var arabic = Keys{
 	NationalityIraqi:                "عراقي",
 	NationalityEgyptian:             "مصري",
 	NationalityLebanese:             "لبناني",
+
+	StatisticsTotalPatients:   "المرضى النشطون",
+	StatisticsTotalVisits:     "الزيارات",
+	StatisticsDiagnosisGroups: "المرضى حسب مجموعة التشخيص",
+	StatisticsGenders:         "المرضى حسب الجنس",
+	StatisticsAgeBands:        "المرضى حسب العمر",
+	StatisticsGovernorates:    "المرضى حسب محافظة الإقامة",
+	StatisticsViruses:         "المرضى حسب الإصابة بالفيروسات",
+	StatisticsVisitReasons:    "الزيارات حسب السبب",
+	StatisticsCount:           "العدد",
+	StatisticsNotDiagnosed:    "غير مشخص",
+	StatisticsNoViruses:       "لا يوجد فيروسات",
+	StatisticsAgeBandFmt: func(band string) string {
+		return fmt.Sprintf("%s سنة", band)
+	},
+
+	TabsBleedingEpisodes:       "نوبات النزف",
+	BleedingEpisode:            "نوبة النزف",
+	BleedingSite:               "مكان النزف",
+	EnterBleedingSite:          "اختر مكان النزف",
+	BleedingSiteOtherJoint:     "مفصل آخر",
+	BleedingSiteMuscle:         "عضلي",
+	BleedingSiteMucosal:        "مخاطي",
+	BleedingSiteIntracranial:   "داخل القحف",
+	BleedingSiteOther:          "آخر",
+	BleedingCause:              "سبب النزف",
+	EnterBleedingCause:         "اختر سبب النزف",
+	BleedingCauseSpontaneous:   "عفوي",
+	BleedingCauseTraumatic:     "رضّي",
+	BleedingSeverity:           "شدة النزف",
+	EnterBleedingSeverity:      "اختر شدة النزف",
+	BleedingSeverityMild:       "خفيف",
+	BleedingSeverityModerate:   "متوسط",
+	BleedingSeveritySevere:     "شديد",
+	BleedingOnsetAt:            "وقت بدء النزف",
+	BleedingNotes:              "ملاحظات",
+	EnterBleedingNotes:         "ادخل الملاحظات",
+	BleedingTreatedWith:        "عولج بـ",
+	ChooseBleedingTreatedWith:  "اختر الدواء المستخدم",
+	BleedingReportedByPatient:  "أبلغ عنه المريض",
+	BleedingRates:              "معدلات النزف خلال آخر 12 شهراً",
+	BleedingABR:                "معدل النزف السنوي",
+	BleedingAJBR:               "معدل نزف المفاصل السنوي",
+	BleedingSpontaneousABR:     "معدل النزف العفوي السنوي",
+	BleedingRatesOnProphylaxis: "معدلات النزف أثناء العلاج الوقائي",
+	BleedingObservedDaysFmt: func(days int) string {
+		return fmt.Sprintf("مدة المتابعة %d يوماً", days)
+	},
+	ReportBleedingEpisode:           "الإبلاغ عن نوبة نزف",
+	ReportBleedingEpisodeParagraph:  "إذا تعرضت لنزف، يرجى إخبارنا بمكانه ووقته، وأي من الأدوية الموصوفة استخدمت لعلاجه.",
+	CheckUpBleedingEpisodeParagraph: "املأ الحقول التالية فقط عندما تكون الزيارة بسبب نزف.",
+
+	StockLedger:                       "سجل المخزون",
+	StockLedgerRecordMovement:         "تسجيل حركة مخزون",
+	StockLedgerCorrectAmount:          "تصحيح الكمية المعدودة",
+	StockMovementKind:                 "الحركة",
+	ChooseStockMovementKind:           "اختر الحركة",
+	StockMovementKindReceivedDonation: "تبرع مستلم",
+	StockMovementKindDispensedToVisit: "صرف لزيارة",
+	StockMovementKindExpiredDestroyed: "منتهي الصلاحية/متلف",
+	StockMovementKindManualCorrection: "تصحيح يدوي",
+	StockMovementKindReturned:         "مرتجع",
+	StockMovementAmount:               "عدد العبوات",
+	StockMovementDelta:                "التغيير",
+	StockMovementAmountAfter:          "الرصيد",
+	StockMovementAccount:              "بواسطة",
+	StockMovementVisit:                "الزيارة",
+	StockMovementNote:                 "ملاحظة",
+	EnterStockMovementNote:            "أدخل ملاحظة",
+	StockMovementCreatedAt:            "التاريخ",
+
+	ErrorExpiredMedicineFmt: func(medicineName, batchNumber string) string {
+		return fmt.Sprintf("لا يمكن وصف %s، حيث انتهت صلاحية الدفعة %s.", medicineName, batchNumber)
+	},
+	MedicineAlerts: "تنبيهات الأدوية",
+	MedicineAlertExpiredFmt: func(medicineName, batchNumber string, leftPackages int) string {
+		return fmt.Sprintf("انتهت صلاحية الدفعة %s من %s، وتبقى منها %d عبوة للإتلاف.", batchNumber, medicineName, leftPackages)
+	},
+	MedicineAlertExpiringFmt: func(medicineName, batchNumber, expiresAt string, windowDays int) string {
+		return fmt.Sprintf("تنتهي صلاحية الدفعة %s من %s خلال %d يوم، بتاريخ %s.", batchNumber, medicineName, windowDays, expiresAt)
+	},
+	MedicineAlertLowStockFmt: func(medicineName string, leftPackages int) string {
+		return fmt.Sprintf("أوشك %s على النفاد، وتبقى منه %d عبوة.", medicineName, leftPackages)
+	},
+
+	DoseCalculator:          "حاسبة الجرعة",
+	DoseCalculatorParagraph: "تحسب جرعة العامل من وزن المريض أعلاه، وتملأ الأدوية الموصوفة من الدفعات المتوفرة في المخزون.",
+	DoseTargetRise:          "الارتفاع المطلوب في مستوى العامل (%)",
+	EnterDoseTargetRise:     "مثلاً 50 لنزف مفصلي",
+	DoseFactorType:          "نوع العامل",
+	ChooseDoseFactorType:    "اختر نوع العامل",
+	DoseFactorVIII:          "العامل الثامن",
+	DoseFactorIX:            "العامل التاسع",
+	DoseCalculate:           "حساب الجرعة",
+	DoseSummaryFmt: func(requiredIu, prescribedIu int) string {
+		return fmt.Sprintf("الجرعة المطلوبة %d وحدة دولية، وسيتم وصف %d وحدة دولية", requiredIu, prescribedIu)
+	},
+	DoseShortfallFmt: func(shortfallIu int) string {
+		return fmt.Sprintf("ينقص المخزون %d وحدة دولية", shortfallIu)
+	},
+
+	ProphylaxesScheduleKind:       "الجدول",
+	ChooseProphylaxesScheduleKind: "اختر الجدول",
+	ProphylaxesScheduleWeekdays:   "في أيام محددة من الأسبوع",
+	ProphylaxesScheduleEveryNDays: "كل عدد من الأيام",
+	ProphylaxesWeekdays:           "أيام الأسبوع",
+	ProphylaxesIntervalDays:       "كل (أيام)",
+	EnterProphylaxesIntervalDays:  "أدخل عدد الأيام بين الجرعات",
+	ProphylaxesDoseIu:             "الجرعة (وحدة دولية)",
+	EnterProphylaxesDoseIu:        "أدخل الجرعة بالوحدات الدولية",
+	ProphylaxesStartDate:          "تاريخ البدء",
+	ProphylaxesScheduleWeekdaysFmt: func(weekdays string) string {
+		return fmt.Sprintf("كل %s", weekdays)
+	},
+	ProphylaxesScheduleEveryNDaysFmt: func(days int) string {
+		return fmt.Sprintf("كل %d يوم", days)
+	},
+	WeekdaySunday:       "الأحد",
+	WeekdayMonday:       "الاثنين",
+	WeekdayTuesday:      "الثلاثاء",
+	WeekdayWednesday:    "الأربعاء",
+	WeekdayThursday:     "الخميس",
+	WeekdayFriday:       "الجمعة",
+	WeekdaySaturday:     "السبت",
+	ProphylaxesCalendar: "التقويم",
+	ProphylaxesAdherenceFmt: func(taken, due int, percent float64) string {
+		return fmt.Sprintf("الالتزام: تم أخذ %d من %d جرعة مستحقة (%.0f%%)", taken, due, percent)
+	},
+	ProphylaxisInfusionTaken:    "مأخوذة",
+	ProphylaxisInfusionMissed:   "فائتة",
+	ProphylaxisInfusionUpcoming: "قادمة",
+
+	CalendarReminders:          "تذكيرات التقويم",
+	CalendarRemindersParagraph: "اشترك بجدول علاجك الوقائي وزياراتك من تقويم هاتفك لتصلك تذكيرات قبل كل جرعة. أبقِ هذا الرابط خاصاً، لأن أي شخص يملكه يستطيع رؤية جدولك.",
+	CalendarSubscribe:          "الاشتراك بالتقويم",
+	CalendarDownload:           "تنزيل ملف التقويم",
+
+	NavAgenda:                  "المواعيد",
+	Appointments:               "المواعيد",
+	AgendaPreviousDay:          "اليوم السابق",
+	AgendaNextDay:              "اليوم التالي",
+	AgendaDate:                 "يوم المواعيد",
+	AppointmentTime:            "الوقت",
+	AppointmentScheduledAt:     "موعد الزيارة",
+	AppointmentAssignee:        "مسند إلى",
+	ChooseAppointmentAssignee:  "اختر من يُسند إليه الموعد",
+	AppointmentNotes:           "ملاحظات",
+	EnterAppointmentNotes:      "أدخل ملاحظات للموعد",
+	AppointmentStatus:          "الحالة",
+	AppointmentStatusBooked:    "محجوز",
+	AppointmentStatusAttended:  "حضر",
+	AppointmentStatusNoShow:    "لم يحضر",
+	AppointmentStatusCancelled: "ملغى",
+	AppointmentCheckIn:         "تسجيل الحضور",
+	AppointmentMarkNoShow:      "تسجيل عدم الحضور",
+	AppointmentCancel:          "إلغاء",
+	AppointmentRebook:          "إعادة الحجز",
+	CheckUpAppointment:         "الموعد",
+	CheckUpWithoutAppointment:  "بدون موعد",
+
+	AppointmentViewVisit: "عرض الزيارة",
+
+	HjhsSwelling:         "التورم",
+	HjhsSwellingDuration: "مدة التورم",
+	HjhsMuscleAtrophy:    "ضمور العضلات",
+	HjhsCrepitus:         "الطقطقة عند الحركة",
+	HjhsFlexionLoss:      "فقدان الثني",
+	HjhsExtensionLoss:    "فقدان البسط",
+	HjhsJointPain:        "ألم المفصل",
+	HjhsStrength:         "القوة",
+	HjhsGlobalGait:       "المشية العامة",
+	HjhsTotal:            "مجموع HJHS",
+	HjhsChange:           "التغير",
+	HjhsParagraph:        "قيّم كل بند من مقياس صحة المفاصل لمرضى الناعور 2.1، حيث 0 يعني بنداً سليماً والقيمة الأعلى تعني حالة أسوأ.",
+
+	NavTargetJoints:       "المفاصل المستهدفة",
+	TargetJoints:          "المفاصل المستهدفة",
+	TargetJointsParagraph: "المفصل المستهدف هو مفصل حدث فيه 3 نزوف عفوية أو أكثر خلال 6 أشهر متتالية، ولا يعود مفصلاً مستهدفاً بعد 12 شهراً متتالية حدث فيها نزفان أو أقل.",
+	TargetJointJoint:      "المفصل",
+	TargetJointStatus:     "الحالة",
+	TargetJointActive:     "نشط",
+	TargetJointResolved:   "زال",
+	TargetJointSince:      "منذ",
+	TargetJointResolvedAt: "تاريخ الزوال",
+	TargetJointBleeds:     "النزوف خلال آخر 12 شهراً",
+	TargetJointLastBleed:  "آخر نزف",
+	TargetJointHjhsScore:  "آخر قيمة HJHS",
+
+	OutOfRangeResults:          "النتائج خارج المجال",
+	OutOfRangeResultsParagraph: "نتائج التحاليل التي فيها قيم خارج مجالاتها المرجعية، حسب عمر المريض وجنسه وقت إجراء التحليل.",
+	OutOfRangeResultsDays:      "آخر أيام",
+	BloodTestFlagLow:           "منخفض",
+	BloodTestFlagHigh:          "مرتفع",
+	BloodTestFlagCriticalLow:   "منخفض بشكل حرج",
+	BloodTestFlagCriticalHigh:  "مرتفع بشكل حرج",
+	BloodTestFlagNormal:        "طبيعي",
+	BloodTestAbnormal:          "قيم غير طبيعية",
+	BloodTestResultValue:       "القيمة",
+	BloodTestResultFlag:        "الحالة",
+	ReferenceRange:             "المجال المرجعي",
+	ReferenceRanges:            "المجالات المرجعية",
+	ReferenceRangesParagraph:   "تحل المجالات المرجعية للحقل محل قيمتيه الدنيا والعليا للمرضى ضمن الفئة العمرية والجنس المحددين، ويُفضل المجال الخاص بجنس معين على المجال العام.",
+	ReferenceRangeField:        "الحقل",
+	ReferenceRangeSex:          "الجنس",
+	ReferenceRangeSexAny:       "الكل",
+	ReferenceRangeAgeBand:      "الفئة العمرية (بالأشهر)",
+	ReferenceRangeMinAgeMonths: "العمر الأدنى بالأشهر",
+	ReferenceRangeMaxAgeMonths: "العمر الأقصى بالأشهر، فارغ لعدم التحديد",
+	ReferenceRangeLow:          "الحد الأدنى",
+	ReferenceRangeHigh:         "الحد الأعلى",
+	ReferenceRangeCriticalLow:  "الحد الأدنى الحرج، اختياري",
+	ReferenceRangeCriticalHigh: "الحد الأعلى الحرج، اختياري",
+	ReferenceRangeAdd:          "إضافة مجال مرجعي",
+
+	HemophiliaSeverity:             "شدة الناعور",
+	HemophiliaSeveritySevere:       "شديد",
+	HemophiliaSeverityModerate:     "متوسط",
+	HemophiliaSeverityMild:         "خفيف",
+	HemophiliaSeverityUnclassified: "لا توجد نتيجة للعامل الثامن أو التاسع أقل من 40%",
+	HemophiliaTypeFmt: func(hemophiliaType string) string {
+		return "ناعور " + hemophiliaType
+	},
+	HemophiliaSeverityEvidenceFmt: func(bloodTestName string, factorLevel float64, testedAt time.Time) string {
+		return fmt.Sprintf("%s بنسبة %g%% بتاريخ %s", bloodTestName, factorLevel, testedAt.Format("2006 Jan/02"))
+	},
+	HemophiliaSeverityContradictionFmt: func(diagnosis string) string {
+		return fmt.Sprintf("نتيجة العامل الأخيرة تخالف التشخيص المسجل (%s)", diagnosis)
+	},
+	MessageSeverityContradiction: "تم الحفظ، لكن نتيجة العامل تخالف التشخيص المسجل",
+
+	NavInhibitorScreeningsDue:       "فحوصات المثبطات",
+	InhibitorScreeningsDueParagraph: "المرضى ضمن أول 50 يوم تعرض للعامل الثامن أو التاسع والذين حان موعد فحص المثبطات لهم.",
+	InhibitorScreeningsDue:          "فحوصات المثبطات المستحقة",
+	TabsInhibitors:                  "المثبطات",
+	InhibitorTests:                  "فحوصات المثبطات",
+	InhibitorScreening:              "المسح",
+	InhibitorTiter:                  "العيار (BU)",
+	InhibitorResult:                 "النتيجة",
+	InhibitorPositive:               "إيجابي",
+	InhibitorNegative:               "سلبي",
+	InhibitorResponder:              "الاستجابة",
+	InhibitorResponderNone:          "لا يوجد مثبط",
+	InhibitorResponderLow:           "مستجيب منخفض",
+	InhibitorResponderHigh:          "مستجيب مرتفع",
+	InhibitorPeakTiter:              "أعلى عيار (BU)",
+	ExposureDays:                    "أيام التعرض",
+	FirstExposure:                   "أول تعرض",
+	InhibitorNextScreening:          "الفحص التالي عند يوم التعرض",
+	InhibitorNoNextScreening:        "لا يوجد فحص مجدول",
+	InhibitorScreeningDue:           "حان موعد الفحص",
+	InhibitorScreeningIntervalEds:   "الفحص كل (أيام تعرض)",
+	InhibitorNotes:                  "ملاحظات",
+	ItiStatus:                       "حالة تحريض التحمل المناعي",
+	ItiStatusNone:                   "لا يوجد",
+	ItiStatusPlanned:                "مخطط",
+	ItiStatusOngoing:                "جاري",
+	ItiStatusSuccess:                "ناجح",
+	ItiStatusPartialSuccess:         "ناجح جزئياً",
+	ItiStatusFailure:                "فاشل",
+	ItiStartedAt:                    "تاريخ بدء تحريض التحمل",
+	ItiEndedAt:                      "تاريخ انتهاء تحريض التحمل",
+
+	BloodTestTrends: "التطورات",
+
+	BloodTestFieldFormula:         "الصيغة",
+	EnterBloodTestFieldFormula:    "تحسب من الحقول الأخرى، مثلاً {Hb} / {Hct} * 100",
+	BloodTestFieldComputed:        "محسوبة",
+	BloodTestComputedFieldsNotice: "تُملأ الحقول المحسوبة من الحقول الأخرى",
+
+	BloodTestOriginalValueFmt: func(value, unit string) string { return fmt.Sprintf("أُدخلت %s %s", value, unit) },
+
+	BloodTestFieldLoincCode:      "رمز LOINC",
+	EnterBloodTestFieldLoincCode: "اختياري، مثلاً 3209-4",
+	BloodTestLoincCodes:          "رموز LOINC",
+	BloodTestLoincCodesParagraph: "يعرّف رمز LOINC الحقل بغض النظر عن اسمه، حتى تُطابق بيانات الاستيراد ونتائج المختبر معه بالرمز، والرمز الفارغ يحذف رمز الحقل.",
+	BloodTestLoincCodeAssign:     "تعيين رمز LOINC",
+
+	AuditEvents:            "سجل التدقيق",
+	AuditEventsParagraph:   "كل قراءة وتعديل لبيانات المرضى، الأحدث أولاً، حيث تُعرض آخر 500 حدث فقط، ويحتوي التصدير على كل الأحداث المصفّاة.",
+	AuditEventsAllAccounts: "كل الحسابات",
+	AuditEventsFrom:        "من",
+	AuditEventsTo:          "إلى",
+	AuditEventsExport:      "تصدير CSV",
+	AuditEventDate:         "التاريخ",
+	AuditEventAction:       "الإجراء",
+	AuditEventKind:         "النوع",
+	AuditEventDetails:      "التفاصيل",
+	AuditEventClient:       "العميل",
+	AuditEventSystem:       "النظام",
+
+	TabsHistory:                  "السجل",
+	PatientHistoryOriginalRecord: "السجل الأصلي",
+	PatientHistoryRestoredFrom:   "مستعاد من النسخة",
+	PatientHistoryRevision:       "النسخة",
+	PatientHistoryChanges:        "التغييرات",
+	PatientHistoryRestore:        "استعادة",
+	PatientHistoryBATScore:       "نقاط BAT",
 }
@@ -302,4 +302,301 @@ var english = Keys{
 	NationalityIraqi:                "Iraqi",
 	NationalityEgyptian:             "Egyptian",
 	NationalityLebanese:             "Lebanese",
+
+	StatisticsTotalPatients:   "Active patients",
+	StatisticsTotalVisits:     "Visits",
+	StatisticsDiagnosisGroups: "Patients by diagnosis group",
+	StatisticsGenders:         "Patients by gender",
+	StatisticsAgeBands:        "Patients by age",
+	StatisticsGovernorates:    "Patients by governorate of residency",
+	StatisticsViruses:         "Patients by virus status",
+	StatisticsVisitReasons:    "Visits by reason",
+	StatisticsCount:           "Count",
+	StatisticsNotDiagnosed:    "Not diagnosed",
+	StatisticsNoViruses:       "No viruses",
+	StatisticsAgeBandFmt: func(band string) string {
+		return fmt.Sprintf("%s years", band)
+	},
+
+	TabsBleedingEpisodes:       "Bleeding episodes",
+	BleedingEpisode:            "Bleeding episode",
+	BleedingSite:               "Bleeding site",
+	EnterBleedingSite:          "Choose bleeding site",
+	BleedingSiteOtherJoint:     "Other joint",
+	BleedingSiteMuscle:         "Muscle",
+	BleedingSiteMucosal:        "Mucosal",
+	BleedingSiteIntracranial:   "Intracranial",
+	BleedingSiteOther:          "Other",
+	BleedingCause:              "Bleeding cause",
+	EnterBleedingCause:         "Choose bleeding cause",
+	BleedingCauseSpontaneous:   "Spontaneous",
+	BleedingCauseTraumatic:     "Traumatic",
+	BleedingSeverity:           "Bleeding severity",
+	EnterBleedingSeverity:      "Choose bleeding severity",
+	BleedingSeverityMild:       "Mild",
+	BleedingSeverityModerate:   "Moderate",
+	BleedingSeveritySevere:     "Severe",
+	BleedingOnsetAt:            "Onset time",
+	BleedingNotes:              "Notes",
+	EnterBleedingNotes:         "Enter notes",
+	BleedingTreatedWith:        "Treated with",
+	ChooseBleedingTreatedWith:  "Choose the used medicine",
+	BleedingReportedByPatient:  "Reported by patient",
+	BleedingRates:              "Bleeding rates over the last 12 months",
+	BleedingABR:                "ABR",
+	BleedingAJBR:               "AJBR",
+	BleedingSpontaneousABR:     "Spontaneous ABR",
+	BleedingRatesOnProphylaxis: "Bleeding rates while on prophylaxis",
+	BleedingObservedDaysFmt: func(days int) string {
+		return fmt.Sprintf("Observed for %d days", days)
+	},
+	ReportBleedingEpisode:           "Report a bleeding episode",
+	ReportBleedingEpisodeParagraph:  "If you had a bleed, kindly tell us where and when it happened, and which of the prescribed medicines you used for it.",
+	CheckUpBleedingEpisodeParagraph: "Fill the following only when the visit is for a bleed.",
+
+	StockLedger:                       "Stock ledger",
+	StockLedgerRecordMovement:         "Record stock movement",
+	StockLedgerCorrectAmount:          "Correct counted amount",
+	StockMovementKind:                 "Movement",
+	ChooseStockMovementKind:           "Choose movement",
+	StockMovementKindReceivedDonation: "Received donation",
+	StockMovementKindDispensedToVisit: "Dispensed to visit",
+	StockMovementKindExpiredDestroyed: "Expired/destroyed",
+	StockMovementKindManualCorrection: "Manual correction",
+	StockMovementKindReturned:         "Returned",
+	StockMovementAmount:               "Packages",
+	StockMovementDelta:                "Change",
+	StockMovementAmountAfter:          "Balance",
+	StockMovementAccount:              "By",
+	StockMovementVisit:                "Visit",
+	StockMovementNote:                 "Note",
+	EnterStockMovementNote:            "Enter a note",
+	StockMovementCreatedAt:            "Date",
+
+	ErrorExpiredMedicineFmt: func(medicineName, batchNumber string) string {
+		return fmt.Sprintf("Can't prescribe %s, as its batch %s has expired", medicineName, batchNumber)
+	},
+	MedicineAlerts: "Medicine alerts",
+	MedicineAlertExpiredFmt: func(medicineName, batchNumber string, leftPackages int) string {
+		return fmt.Sprintf("Batch %s of %s has expired, with %d packages left to destroy", batchNumber, medicineName, leftPackages)
+	},
+	MedicineAlertExpiringFmt: func(medicineName, batchNumber, expiresAt string, windowDays int) string {
+		return fmt.Sprintf("Batch %s of %s expires within %d days, on %s", batchNumber, medicineName, windowDays, expiresAt)
+	},
+	MedicineAlertLowStockFmt: func(medicineName string, leftPackages int) string {
+		return fmt.Sprintf("%s is running low, with %d packages left", medicineName, leftPackages)
+	},
+
+	DoseCalculator:          "Dose calculator",
+	DoseCalculatorParagraph: "Computes the factor dose from the patient's weight above, and fills the prescribed medicines from the in-stock batches.",
+	DoseTargetRise:          "Target factor level rise (%)",
+	EnterDoseTargetRise:     "e.g. 50 for a joint bleed",
+	DoseFactorType:          "Factor type",
+	ChooseDoseFactorType:    "Choose factor type",
+	DoseFactorVIII:          "Factor VIII",
+	DoseFactorIX:            "Factor IX",
+	DoseCalculate:           "Calculate dose",
+	DoseSummaryFmt: func(requiredIu, prescribedIu int) string {
+		return fmt.Sprintf("Required dose is %d IU, prescribing %d IU", requiredIu, prescribedIu)
+	},
+	DoseShortfallFmt: func(shortfallIu int) string {
+		return fmt.Sprintf("The stock is short by %d IU", shortfallIu)
+	},
+
+	ProphylaxesScheduleKind:       "Schedule",
+	ChooseProphylaxesScheduleKind: "Choose schedule",
+	ProphylaxesScheduleWeekdays:   "On weekdays",
+	ProphylaxesScheduleEveryNDays: "Every number of days",
+	ProphylaxesWeekdays:           "Weekdays",
+	ProphylaxesIntervalDays:       "Every (days)",
+	EnterProphylaxesIntervalDays:  "Enter the number of days between infusions",
+	ProphylaxesDoseIu:             "Dose (IU)",
+	EnterProphylaxesDoseIu:        "Enter the dose in IU",
+	ProphylaxesStartDate:          "Start Date",
+	ProphylaxesScheduleWeekdaysFmt: func(weekdays string) string {
+		return fmt.Sprintf("Every %s", weekdays)
+	},
+	ProphylaxesScheduleEveryNDaysFmt: func(days int) string {
+		return fmt.Sprintf("Every %d days", days)
+	},
+	WeekdaySunday:       "Sunday",
+	WeekdayMonday:       "Monday",
+	WeekdayTuesday:      "Tuesday",
+	WeekdayWednesday:    "Wednesday",
+	WeekdayThursday:     "Thursday",
+	WeekdayFriday:       "Friday",
+	WeekdaySaturday:     "Saturday",
+	ProphylaxesCalendar: "Calendar",
+	ProphylaxesAdherenceFmt: func(taken, due int, percent float64) string {
+		return fmt.Sprintf("Adherence: %d of %d due infusions taken (%.0f%%)", taken, due, percent)
+	},
+	ProphylaxisInfusionTaken:    "Taken",
+	ProphylaxisInfusionMissed:   "Missed",
+	ProphylaxisInfusionUpcoming: "Upcoming",
+
+	CalendarReminders:          "Calendar reminders",
+	CalendarRemindersParagraph: "Subscribe to your prophylaxis schedule and visits from your phone's calendar to get reminded before each infusion. Keep this link private, since anyone with it can see your schedule.",
+	CalendarSubscribe:          "Subscribe to calendar",
+	CalendarDownload:           "Download calendar file",
+
+	NavAgenda:                  "Agenda",
+	Appointments:               "Appointments",
+	AgendaPreviousDay:          "Previous day",
+	AgendaNextDay:              "Next day",
+	AgendaDate:                 "Agenda's day",
+	AppointmentTime:            "Time",
+	AppointmentScheduledAt:     "Scheduled at",
+	AppointmentAssignee:        "Assigned to",
+	ChooseAppointmentAssignee:  "Choose who the appointment is assigned to",
+	AppointmentNotes:           "Notes",
+	EnterAppointmentNotes:      "Enter notes for the appointment",
+	AppointmentStatus:          "Status",
+	AppointmentStatusBooked:    "Booked",
+	AppointmentStatusAttended:  "Attended",
+	AppointmentStatusNoShow:    "No-show",
+	AppointmentStatusCancelled: "Cancelled",
+	AppointmentCheckIn:         "Check in",
+	AppointmentMarkNoShow:      "Mark as no-show",
+	AppointmentCancel:          "Cancel",
+	AppointmentRebook:          "Book again",
+	CheckUpAppointment:         "Appointment",
+	CheckUpWithoutAppointment:  "Without an appointment",
+
+	AppointmentViewVisit: "View visit",
+
+	HjhsSwelling:         "Swelling",
+	HjhsSwellingDuration: "Duration of swelling",
+	HjhsMuscleAtrophy:    "Muscle atrophy",
+	HjhsCrepitus:         "Crepitus on motion",
+	HjhsFlexionLoss:      "Flexion loss",
+	HjhsExtensionLoss:    "Extension loss",
+	HjhsJointPain:        "Joint pain",
+	HjhsStrength:         "Strength",
+	HjhsGlobalGait:       "Global gait",
+	HjhsTotal:            "HJHS total",
+	HjhsChange:           "Change",
+	HjhsParagraph:        "Score each item of the Hemophilia Joint Health Score 2.1, where 0 is a healthy item and a higher score is worse.",
+
+	NavTargetJoints:       "Target joints",
+	TargetJoints:          "Target joints",
+	TargetJointsParagraph: "A target joint has 3 or more spontaneous bleeds within 6 consecutive months, and it's no longer a target joint after 12 consecutive months with 2 or less bleeds.",
+	TargetJointJoint:      "Joint",
+	TargetJointStatus:     "Status",
+	TargetJointActive:     "Active",
+	TargetJointResolved:   "Resolved",
+	TargetJointSince:      "Since",
+	TargetJointResolvedAt: "Resolved at",
+	TargetJointBleeds:     "Bleeds in the last 12 months",
+	TargetJointLastBleed:  "Last bleed",
+	TargetJointHjhsScore:  "Latest HJHS score",
+
+	OutOfRangeResults:          "Out of range results",
+	OutOfRangeResultsParagraph: "Blood test results with values out of their reference ranges, by the patient's age and sex when the test was done.",
+	OutOfRangeResultsDays:      "Last days",
+	BloodTestFlagLow:           "Low",
+	BloodTestFlagHigh:          "High",
+	BloodTestFlagCriticalLow:   "Critically low",
+	BloodTestFlagCriticalHigh:  "Critically high",
+	BloodTestFlagNormal:        "Normal",
+	BloodTestAbnormal:          "Abnormal values",
+	BloodTestResultValue:       "Value",
+	BloodTestResultFlag:        "Flag",
+	ReferenceRange:             "Reference range",
+	ReferenceRanges:            "Reference ranges",
+	ReferenceRangesParagraph:   "A field's reference ranges override its min and max values for the patients in a range's age band and sex, where a range for a specific sex is preferred over a range for any sex.",
+	ReferenceRangeField:        "Field",
+	ReferenceRangeSex:          "Sex",
+	ReferenceRangeSexAny:       "Any",
+	ReferenceRangeAgeBand:      "Age band (months)",
+	ReferenceRangeMinAgeMonths: "Minimum age in months",
+	ReferenceRangeMaxAgeMonths: "Maximum age in months, empty for no limit",
+	ReferenceRangeLow:          "Low",
+	ReferenceRangeHigh:         "High",
+	ReferenceRangeCriticalLow:  "Critical low, optional",
+	ReferenceRangeCriticalHigh: "Critical high, optional",
+	ReferenceRangeAdd:          "Add reference range",
+
+	HemophiliaSeverity:             "Hemophilia severity",
+	HemophiliaSeveritySevere:       "Severe",
+	HemophiliaSeverityModerate:     "Moderate",
+	HemophiliaSeverityMild:         "Mild",
+	HemophiliaSeverityUnclassified: "No factor VIII or factor IX result below 40%",
+	HemophiliaTypeFmt: func(hemophiliaType string) string {
+		return "Hemophilia " + hemophiliaType
+	},
+	HemophiliaSeverityEvidenceFmt: func(bloodTestName string, factorLevel float64, testedAt time.Time) string {
+		return fmt.Sprintf("%s of %g%% tested on %s", bloodTestName, factorLevel, testedAt.Format("2006 Jan/02"))
+	},
+	HemophiliaSeverityContradictionFmt: func(diagnosis string) string {
+		return fmt.Sprintf("The latest factor result contradicts the recorded diagnosis (%s)", diagnosis)
+	},
+	MessageSeverityContradiction: "Saved, but the factor result contradicts the recorded diagnosis",
+
+	NavInhibitorScreeningsDue:       "Inhibitor screenings",
+	InhibitorScreeningsDueParagraph: "Patients in their first 50 exposure days to factor VIII or factor IX who are due for an inhibitor screening.",
+	InhibitorScreeningsDue:          "Due inhibitor screenings",
+	TabsInhibitors:                  "Inhibitors",
+	InhibitorTests:                  "Inhibitor tests",
+	InhibitorScreening:              "Screening",
+	InhibitorTiter:                  "Titer (BU)",
+	InhibitorResult:                 "Result",
+	InhibitorPositive:               "Positive",
+	InhibitorNegative:               "Negative",
+	InhibitorResponder:              "Response",
+	InhibitorResponderNone:          "No inhibitor",
+	InhibitorResponderLow:           "Low responder",
+	InhibitorResponderHigh:          "High responder",
+	InhibitorPeakTiter:              "Peak titer (BU)",
+	ExposureDays:                    "Exposure days",
+	FirstExposure:                   "First exposure",
+	InhibitorNextScreening:          "Next screening at exposure day",
+	InhibitorNoNextScreening:        "No screening is scheduled",
+	InhibitorScreeningDue:           "Screening is due",
+	InhibitorScreeningIntervalEds:   "Screen every (exposure days)",
+	InhibitorNotes:                  "Notes",
+	ItiStatus:                       "ITI status",
+	ItiStatusNone:                   "None",
+	ItiStatusPlanned:                "Planned",
+	ItiStatusOngoing:                "Ongoing",
+	ItiStatusSuccess:                "Success",
+	ItiStatusPartialSuccess:         "Partial success",
+	ItiStatusFailure:                "Failure",
+	ItiStartedAt:                    "ITI start date",
+	ItiEndedAt:                      "ITI end date",
+
+	BloodTestTrends: "Trends",
+
+	BloodTestFieldFormula:         "Formula",
+	EnterBloodTestFieldFormula:    "Computed from other fields, e.g. {Hb} / {Hct} * 100",
+	BloodTestFieldComputed:        "computed",
+	BloodTestComputedFieldsNotice: "Computed fields are filled from the other fields",
+
+	BloodTestOriginalValueFmt: func(value, unit string) string { return fmt.Sprintf("entered as %s %s", value, unit) },
+
+	BloodTestFieldLoincCode:      "LOINC code",
+	EnterBloodTestFieldLoincCode: "Optional, e.g. 3209-4",
+	BloodTestLoincCodes:          "LOINC codes",
+	BloodTestLoincCodesParagraph: "A field's LOINC code identifies it regardless of its name, so that the importer and the lab results are matched to it by the code, and an empty code clears the field's code.",
+	BloodTestLoincCodeAssign:     "Assign LOINC code",
+
+	AuditEvents:            "Audit trail",
+	AuditEventsParagraph:   "Every read and write of the patients' data, newest first, where only the latest 500 events are listed, and the export has all of the filtered events.",
+	AuditEventsAllAccounts: "All accounts",
+	AuditEventsFrom:        "From",
+	AuditEventsTo:          "To",
+	AuditEventsExport:      "Export CSV",
+	AuditEventDate:         "Date",
+	AuditEventAction:       "Action",
+	AuditEventKind:         "Kind",
+	AuditEventDetails:      "Details",
+	AuditEventClient:       "Client",
+	AuditEventSystem:       "System",
+
+	TabsHistory:                  "History",
+	PatientHistoryOriginalRecord: "Original record",
+	PatientHistoryRestoredFrom:   "Restored from revision",
+	PatientHistoryRevision:       "Revision",
+	PatientHistoryChanges:        "Changes",
+	PatientHistoryRestore:        "Restore",
+	PatientHistoryBATScore:       "BAT score",
 }
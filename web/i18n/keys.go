@@ -285,6 +285,275 @@ type Keys struct {
 	NationalityIraqi       string
 	NationalityEgyptian    string
 	NationalityLebanese    string
+
+	StatisticsTotalPatients   string
+	StatisticsTotalVisits     string
+	StatisticsDiagnosisGroups string
+	StatisticsGenders         string
+	StatisticsAgeBands        string
+	StatisticsGovernorates    string
+	StatisticsViruses         string
+	StatisticsVisitReasons    string
+	StatisticsCount           string
+	StatisticsNotDiagnosed    string
+	StatisticsNoViruses       string
+	StatisticsAgeBandFmt      func(band string) string
+
+	TabsBleedingEpisodes            string
+	BleedingEpisode                 string
+	BleedingSite                    string
+	EnterBleedingSite               string
+	BleedingSiteOtherJoint          string
+	BleedingSiteMuscle              string
+	BleedingSiteMucosal             string
+	BleedingSiteIntracranial        string
+	BleedingSiteOther               string
+	BleedingCause                   string
+	EnterBleedingCause              string
+	BleedingCauseSpontaneous        string
+	BleedingCauseTraumatic          string
+	BleedingSeverity                string
+	EnterBleedingSeverity           string
+	BleedingSeverityMild            string
+	BleedingSeverityModerate        string
+	BleedingSeveritySevere          string
+	BleedingOnsetAt                 string
+	BleedingNotes                   string
+	EnterBleedingNotes              string
+	BleedingTreatedWith             string
+	ChooseBleedingTreatedWith       string
+	BleedingReportedByPatient       string
+	BleedingRates                   string
+	BleedingABR                     string
+	BleedingAJBR                    string
+	BleedingSpontaneousABR          string
+	BleedingRatesOnProphylaxis      string
+	BleedingObservedDaysFmt         func(days int) string
+	ReportBleedingEpisode           string
+	ReportBleedingEpisodeParagraph  string
+	CheckUpBleedingEpisodeParagraph string
+
+	StockLedger                       string
+	StockLedgerRecordMovement         string
+	StockLedgerCorrectAmount          string
+	StockMovementKind                 string
+	ChooseStockMovementKind           string
+	StockMovementKindReceivedDonation string
+	StockMovementKindDispensedToVisit string
+	StockMovementKindExpiredDestroyed string
+	StockMovementKindManualCorrection string
+	StockMovementKindReturned         string
+	StockMovementAmount               string
+	StockMovementDelta                string
+	StockMovementAmountAfter          string
+	StockMovementAccount              string
+	StockMovementVisit                string
+	StockMovementNote                 string
+	EnterStockMovementNote            string
+	StockMovementCreatedAt            string
+
+	ErrorExpiredMedicineFmt  func(medicineName, batchNumber string) string
+	MedicineAlerts           string
+	MedicineAlertExpiredFmt  func(medicineName, batchNumber string, leftPackages int) string
+	MedicineAlertExpiringFmt func(medicineName, batchNumber, expiresAt string, windowDays int) string
+	MedicineAlertLowStockFmt func(medicineName string, leftPackages int) string
+
+	DoseCalculator          string
+	DoseCalculatorParagraph string
+	DoseTargetRise          string
+	EnterDoseTargetRise     string
+	DoseFactorType          string
+	ChooseDoseFactorType    string
+	DoseFactorVIII          string
+	DoseFactorIX            string
+	DoseCalculate           string
+	DoseSummaryFmt          func(requiredIu, prescribedIu int) string
+	DoseShortfallFmt        func(shortfallIu int) string
+
+	ProphylaxesScheduleKind          string
+	ChooseProphylaxesScheduleKind    string
+	ProphylaxesScheduleWeekdays      string
+	ProphylaxesScheduleEveryNDays    string
+	ProphylaxesWeekdays              string
+	ProphylaxesIntervalDays          string
+	EnterProphylaxesIntervalDays     string
+	ProphylaxesDoseIu                string
+	EnterProphylaxesDoseIu           string
+	ProphylaxesStartDate             string
+	ProphylaxesScheduleWeekdaysFmt   func(weekdays string) string
+	ProphylaxesScheduleEveryNDaysFmt func(days int) string
+	WeekdaySunday                    string
+	WeekdayMonday                    string
+	WeekdayTuesday                   string
+	WeekdayWednesday                 string
+	WeekdayThursday                  string
+	WeekdayFriday                    string
+	WeekdaySaturday                  string
+	ProphylaxesCalendar              string
+	ProphylaxesAdherenceFmt          func(taken, due int, percent float64) string
+	ProphylaxisInfusionTaken         string
+	ProphylaxisInfusionMissed        string
+	ProphylaxisInfusionUpcoming      string
+
+	CalendarReminders          string
+	CalendarRemindersParagraph string
+	CalendarSubscribe          string
+	CalendarDownload           string
+
+	NavAgenda                  string
+	Appointments               string
+	AgendaPreviousDay          string
+	AgendaNextDay              string
+	AgendaDate                 string
+	AppointmentTime            string
+	AppointmentScheduledAt     string
+	AppointmentAssignee        string
+	ChooseAppointmentAssignee  string
+	AppointmentNotes           string
+	EnterAppointmentNotes      string
+	AppointmentStatus          string
+	AppointmentStatusBooked    string
+	AppointmentStatusAttended  string
+	AppointmentStatusNoShow    string
+	AppointmentStatusCancelled string
+	AppointmentCheckIn         string
+	AppointmentMarkNoShow      string
+	AppointmentCancel          string
+	AppointmentRebook          string
+	CheckUpAppointment         string
+	CheckUpWithoutAppointment  string
+
+	AppointmentViewVisit string
+
+	HjhsSwelling         string
+	HjhsSwellingDuration string
+	HjhsMuscleAtrophy    string
+	HjhsCrepitus         string
+	HjhsFlexionLoss      string
+	HjhsExtensionLoss    string
+	HjhsJointPain        string
+	HjhsStrength         string
+	HjhsGlobalGait       string
+	HjhsTotal            string
+	HjhsChange           string
+	HjhsParagraph        string
+
+	NavTargetJoints       string
+	TargetJoints          string
+	TargetJointsParagraph string
+	TargetJointJoint      string
+	TargetJointStatus     string
+	TargetJointActive     string
+	TargetJointResolved   string
+	TargetJointSince      string
+	TargetJointResolvedAt string
+	TargetJointBleeds     string
+	TargetJointLastBleed  string
+	TargetJointHjhsScore  string
+
+	OutOfRangeResults          string
+	OutOfRangeResultsParagraph string
+	OutOfRangeResultsDays      string
+	BloodTestFlagLow           string
+	BloodTestFlagHigh          string
+	BloodTestFlagCriticalLow   string
+	BloodTestFlagCriticalHigh  string
+	BloodTestFlagNormal        string
+	BloodTestAbnormal          string
+	BloodTestResultValue       string
+	BloodTestResultFlag        string
+	ReferenceRange             string
+	ReferenceRanges            string
+	ReferenceRangesParagraph   string
+	ReferenceRangeField        string
+	ReferenceRangeSex          string
+	ReferenceRangeSexAny       string
+	ReferenceRangeAgeBand      string
+	ReferenceRangeMinAgeMonths string
+	ReferenceRangeMaxAgeMonths string
+	ReferenceRangeLow          string
+	ReferenceRangeHigh         string
+	ReferenceRangeCriticalLow  string
+	ReferenceRangeCriticalHigh string
+	ReferenceRangeAdd          string
+
+	HemophiliaSeverity                 string
+	HemophiliaSeveritySevere           string
+	HemophiliaSeverityModerate         string
+	HemophiliaSeverityMild             string
+	HemophiliaSeverityUnclassified     string
+	HemophiliaTypeFmt                  func(hemophiliaType string) string
+	HemophiliaSeverityEvidenceFmt      func(bloodTestName string, factorLevel float64, testedAt time.Time) string
+	HemophiliaSeverityContradictionFmt func(diagnosis string) string
+	MessageSeverityContradiction       string
+
+	NavInhibitorScreeningsDue       string
+	InhibitorScreeningsDueParagraph string
+	InhibitorScreeningsDue          string
+	TabsInhibitors                  string
+	InhibitorTests                  string
+	InhibitorScreening              string
+	InhibitorTiter                  string
+	InhibitorResult                 string
+	InhibitorPositive               string
+	InhibitorNegative               string
+	InhibitorResponder              string
+	InhibitorResponderNone          string
+	InhibitorResponderLow           string
+	InhibitorResponderHigh          string
+	InhibitorPeakTiter              string
+	ExposureDays                    string
+	FirstExposure                   string
+	InhibitorNextScreening          string
+	InhibitorNoNextScreening        string
+	InhibitorScreeningDue           string
+	InhibitorScreeningIntervalEds   string
+	InhibitorNotes                  string
+	ItiStatus                       string
+	ItiStatusNone                   string
+	ItiStatusPlanned                string
+	ItiStatusOngoing                string
+	ItiStatusSuccess                string
+	ItiStatusPartialSuccess         string
+	ItiStatusFailure                string
+	ItiStartedAt                    string
+	ItiEndedAt                      string
+
+	BloodTestTrends string
+
+	BloodTestFieldFormula         string
+	EnterBloodTestFieldFormula    string
+	BloodTestFieldComputed        string
+	BloodTestComputedFieldsNotice string
+
+	BloodTestOriginalValueFmt func(value, unit string) string
+
+	BloodTestFieldLoincCode      string
+	EnterBloodTestFieldLoincCode string
+	BloodTestLoincCodes          string
+	BloodTestLoincCodesParagraph string
+	BloodTestLoincCodeAssign     string
+
+	AuditEvents            string
+	AuditEventsParagraph   string
+	AuditEventsAllAccounts string
+	AuditEventsFrom        string
+	AuditEventsTo          string
+	AuditEventsExport      string
+	AuditEventDate         string
+	AuditEventAction       string
+	AuditEventKind         string
+	AuditEventDetails      string
+	AuditEventClient       string
+	AuditEventSystem       string
+
+	TabsHistory                  string
+	PatientHistoryOriginalRecord string
+	PatientHistoryRestoredFrom   string
+	PatientHistoryRevision       string
+	PatientHistoryChanges        string
+	PatientHistoryRestore        string
+	PatientHistoryBATScore       string
 }
 
 var localeKeys = map[string]Keys{